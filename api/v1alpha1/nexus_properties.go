@@ -0,0 +1,86 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ContextPath returns the path under which the Nexus server is served, either empty or starting with "/" without a trailing one.
+// Taken from `spec.networking.contextPath` or, if not set, from the `nexus-context-path` property, without writing it back to the spec.
+func (in *Nexus) ContextPath() string {
+	contextPath := in.Spec.Networking.ContextPath
+	if len(contextPath) == 0 {
+		contextPath = in.Spec.Properties[ContextPathProperty]
+	}
+	if len(contextPath) == 0 {
+		return ""
+	}
+	contextPath = path.Clean("/" + contextPath)
+	if contextPath == "/" {
+		return ""
+	}
+	return contextPath
+}
+
+// HTTPPort returns the port the Nexus server listens on for HTTP.
+// Taken from `spec.configuration.httpPort` or, if not set, from the `application-port` property, falling back to the default one if it's not a valid port.
+func (in *Nexus) HTTPPort() int32 {
+	if in.Spec.Configuration.HTTPPort > 0 {
+		return in.Spec.Configuration.HTTPPort
+	}
+	if port, ok := in.HTTPPortFromProperties(); ok {
+		return port
+	}
+	return DefaultNexusHTTPPort
+}
+
+// HTTPPortFromProperties parses the `application-port` property, returning false if it's not set or not a valid port
+func (in *Nexus) HTTPPortFromProperties() (int32, bool) {
+	property, ok := in.Spec.Properties[HTTPPortProperty]
+	if !ok {
+		return 0, false
+	}
+	port, err := strconv.ParseInt(strings.TrimSpace(property), 10, 32)
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, false
+	}
+	return int32(port), true
+}
+
+// ScriptCreationAllowed checks if the creation of scripts through the REST API is enabled,
+// either by `spec.configuration.allowScriptCreation` or by the `nexus.scripts.allowCreation` property.
+func (in *Nexus) ScriptCreationAllowed() bool {
+	return in.Spec.Configuration.AllowScriptCreation || in.propertyEnabled(AllowScriptCreationProperty)
+}
+
+// Datastore returns the database of the Nexus server.
+// Taken from `spec.configuration.datastore` or, if not set, from the `nexus.datastore.enabled` property, OrientDB by default.
+func (in *Nexus) Datastore() NexusDatastore {
+	if len(in.Spec.Configuration.Datastore) > 0 {
+		return in.Spec.Configuration.Datastore
+	}
+	if in.propertyEnabled(DatastoreEnabledProperty) {
+		return H2Datastore
+	}
+	return OrientDBDatastore
+}
+
+// propertyEnabled checks if the boolean property is set to true, ignoring case as Java does
+func (in *Nexus) propertyEnabled(key string) bool {
+	return strings.EqualFold(strings.TrimSpace(in.Spec.Properties[key]), "true")
+}
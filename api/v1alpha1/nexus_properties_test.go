@@ -0,0 +1,80 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNexus_ContextPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		contextPath string
+		properties  map[string]string
+		want        string
+	}{
+		{"No context path", "", nil, ""},
+		{"Root context path", "/", nil, ""},
+		{"Context path", "/nexus", nil, "/nexus"},
+		{"Context path without leading slash and with a trailing one", "nexus/", nil, "/nexus"},
+		{"Context path from the properties", "", map[string]string{ContextPathProperty: "/tools/nexus/"}, "/tools/nexus"},
+		{"Context path overriding the properties", "/nexus", map[string]string{ContextPathProperty: "/other"}, "/nexus"},
+	}
+	for _, tt := range tests {
+		nexus := &Nexus{Spec: NexusSpec{Properties: tt.properties, Networking: NexusNetworking{ContextPath: tt.contextPath}}}
+		assert.Equal(t, tt.want, nexus.ContextPath(), tt.name)
+		// the spec is left untouched
+		assert.Equal(t, tt.contextPath, nexus.Spec.Networking.ContextPath, tt.name)
+	}
+}
+
+func TestNexus_Configuration(t *testing.T) {
+	tests := []struct {
+		name                string
+		configuration       NexusConfiguration
+		properties          map[string]string
+		wantHTTPPort        int32
+		wantScriptsCreation bool
+		wantDatastore       NexusDatastore
+	}{
+		{"No configuration", NexusConfiguration{}, nil, DefaultNexusHTTPPort, false, OrientDBDatastore},
+		{"Configuration", NexusConfiguration{HTTPPort: 8082, AllowScriptCreation: true, Datastore: H2Datastore}, nil, 8082, true, H2Datastore},
+		{"Configuration from the properties",
+			NexusConfiguration{},
+			map[string]string{HTTPPortProperty: "8082", AllowScriptCreationProperty: "True", DatastoreEnabledProperty: "true"},
+			8082, true, H2Datastore},
+		{"Configuration overriding the properties",
+			NexusConfiguration{HTTPPort: 8083, Datastore: OrientDBDatastore},
+			map[string]string{HTTPPortProperty: "8082", DatastoreEnabledProperty: "true"},
+			8083, false, OrientDBDatastore},
+		{"Invalid port in the properties", NexusConfiguration{}, map[string]string{HTTPPortProperty: "http"}, DefaultNexusHTTPPort, false, OrientDBDatastore},
+	}
+	for _, tt := range tests {
+		nexus := &Nexus{Spec: NexusSpec{Properties: tt.properties, Configuration: tt.configuration}}
+		assert.Equal(t, tt.wantHTTPPort, nexus.HTTPPort(), tt.name)
+		assert.Equal(t, tt.wantScriptsCreation, nexus.ScriptCreationAllowed(), tt.name)
+		assert.Equal(t, tt.wantDatastore, nexus.Datastore(), tt.name)
+		// the spec is left untouched
+		assert.Equal(t, tt.configuration, nexus.Spec.Configuration, tt.name)
+	}
+
+	// removing the property disables the script creation again
+	nexus := &Nexus{Spec: NexusSpec{Properties: map[string]string{AllowScriptCreationProperty: "true"}}}
+	assert.True(t, nexus.ScriptCreationAllowed())
+	delete(nexus.Spec.Properties, AllowScriptCreationProperty)
+	assert.False(t, nexus.ScriptCreationAllowed())
+}
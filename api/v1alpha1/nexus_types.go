@@ -17,6 +17,7 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -62,6 +63,11 @@ type NexusSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.x-descriptors="urn:alm:descriptor:com.tectonic.ui:resourceRequirements"
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// JVM describes the tuning of the Java virtual machine running the Nexus server, such as its memory sizing
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	JVM NexusJVM `json:"jvm,omitempty"`
+
 	// Persistence definition
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Persistence"
@@ -78,7 +84,8 @@ type NexusSpec struct {
 	// Defaults to `false`: the default password for a newly created instance is 'admin123', which should be changed in the first login.
 	// If set to `true`, you must use the automatically generated 'admin' password, stored in the container's file system at `/nexus-data/admin.password`.
 	// The operator uses the default credentials to create a user for itself to create default repositories.
-	// If set to `true`, the repositories won't be created since the operator won't fetch for the random password.
+	// If set to `true`, the operator reads the generated password from the server pod and stores it in the instance Secret to perform the server operations,
+	// unless `spec.serverOperations.adminCredentialsSecret` is set.
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Generate Random Admin Password"
 	// +optional
@@ -95,6 +102,38 @@ type NexusSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// Scheduling describes where the Nexus pods may run, such as dedicated nodes
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	Scheduling NexusScheduling `json:"scheduling,omitempty"`
+
+	// Sidecars are additional containers run in the Nexus pods next to the Nexus server, such as a log shipper or a backup agent.
+	// Their names must differ from the ones of the containers managed by the Operator: `nexus-server` and `oauth2-proxy`.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// InitContainers are run in order in the Nexus pods before the Nexus server starts, e.g. to fix the permissions of the data volume
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// Patches are applied to the resources generated by the Operator before they're created or updated in the cluster, e.g. to add a sidecar container to the Deployment or a field not covered by this spec.
+	// Fields changed by the patches are kept in sync with the deployed resources.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=atomic
+	Patches []ResourcePatch `json:"patches,omitempty"`
+
+	// Security describes how the users authenticate against the Nexus server, such as the LDAP server to use
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	Security NexusSecurity `json:"security,omitempty"`
+
 	// LivenessProbe describes how the Nexus container liveness probe should work
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
 	// +optional
@@ -115,6 +154,127 @@ type NexusSpec struct {
 	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
 	// +optional
 	Properties map[string]string `json:"properties,omitempty"`
+
+	// Configuration describes the common settings of the nexus.properties file. They take precedence over the same keys in `properties`.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +optional
+	Configuration NexusConfiguration `json:"configuration,omitempty"`
+
+	// Repositories describes the repositories managed by the Operator in the Nexus server.
+	// Repositories created from this list are removed from the server once they're removed from here.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Repositories []Repository `json:"repositories,omitempty"`
+
+	// BlobStores describes the blob stores managed by the Operator in the Nexus server.
+	// Blob stores created from this list are removed from the server once they're removed from here.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	BlobStores []BlobStore `json:"blobStores,omitempty"`
+
+	// CleanupPolicies describes the cleanup policies managed by the Operator in the Nexus server, referenced by the repositories in `cleanup.policyNames`.
+	// Cleanup policies created from this list are removed from the server once they're removed from here.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	CleanupPolicies []CleanupPolicy `json:"cleanupPolicies,omitempty"`
+
+	// Tasks describes the scheduled tasks managed by the Operator in the Nexus server.
+	// Tasks created from this list are removed from the server once they're removed from here.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Tasks []Task `json:"tasks,omitempty"`
+}
+
+const (
+	// NexusContainerName is the name of the Nexus server container in the pods
+	NexusContainerName = "nexus-server"
+	// OAuth2ProxyContainerName is the name of the oauth2-proxy sidecar container in the pods
+	OAuth2ProxyContainerName = "oauth2-proxy"
+	// NexusDataDir is the directory where the Nexus server stores its data, where the persistent volume is mounted
+	NexusDataDir = "/nexus-data"
+)
+
+// NexusGarbageCollector defines the garbage collector used by the JVM
+type NexusGarbageCollector string
+
+const (
+	// G1GarbageCollector the Garbage-First collector (`-XX:+UseG1GC`)
+	G1GarbageCollector NexusGarbageCollector = "G1"
+	// ParallelGarbageCollector the throughput collector (`-XX:+UseParallelGC`)
+	ParallelGarbageCollector NexusGarbageCollector = "Parallel"
+	// SerialGarbageCollector the single threaded collector (`-XX:+UseSerialGC`)
+	SerialGarbageCollector NexusGarbageCollector = "Serial"
+)
+
+// NexusJVM describes the tuning of the JVM running the Nexus server, see https://help.sonatype.com/repomanager3/installation/system-requirements#SystemRequirements-Memory
+type NexusJVM struct {
+	// HeapSize is the initial and maximum heap size (`-Xms` and `-Xmx`), e.g. `2Gi`. Takes precedence over `heapPercentage`.
+	// +optional
+	HeapSize *resource.Quantity `json:"heapSize,omitempty"`
+	// HeapPercentage is the heap size as a percentage of the Nexus container memory limit. Ignored if there's no memory limit.
+	// Defaults: 50
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=90
+	// +optional
+	HeapPercentage *int32 `json:"heapPercentage,omitempty"`
+	// DirectMemorySize is the maximum size of the memory allocated outside of the heap for the I/O buffers (`-XX:MaxDirectMemorySize`), e.g. `1Gi`. Takes precedence over `directMemoryPercentage`.
+	// +optional
+	DirectMemorySize *resource.Quantity `json:"directMemorySize,omitempty"`
+	// DirectMemoryPercentage is the direct memory size as a percentage of the Nexus container memory limit. Ignored if there's no memory limit.
+	// Defaults: 25
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=90
+	// +optional
+	DirectMemoryPercentage *int32 `json:"directMemoryPercentage,omitempty"`
+	// GarbageCollector selects the garbage collector: `G1`, `Parallel` or `Serial`. If left blank, the JVM picks one.
+	// +kubebuilder:validation:Enum=G1;Parallel;Serial
+	// +optional
+	GarbageCollector NexusGarbageCollector `json:"garbageCollector,omitempty"`
+	// HeapDumpPath enables the heap dumps when the JVM runs out of memory (`-XX:+HeapDumpOnOutOfMemoryError`), written to this directory.
+	// Use a directory in a persistent volume, such as `/nexus-data/log` or the mount path of an extra volume, so that the dumps survive the restart of the pod.
+	// +optional
+	HeapDumpPath string `json:"heapDumpPath,omitempty"`
+	// ExtraArgs are added to the JVM arguments after the ones set by the Operator, e.g. `-Dkaraf.log.console=INFO` or `-XX:ActiveProcessorCount=2`.
+	// +optional
+	// +listType=atomic
+	ExtraArgs []string `json:"extraArgs,omitempty"`
+}
+
+// NexusDatastore defines the database where the Nexus server stores its configuration and component metadata
+type NexusDatastore string
+
+const (
+	// OrientDBDatastore the embedded OrientDB databases
+	OrientDBDatastore NexusDatastore = "OrientDB"
+	// H2Datastore the embedded H2 database (`nexus.datastore.enabled`)
+	H2Datastore NexusDatastore = "H2"
+)
+
+// NexusConfiguration describes the common settings of the nexus.properties file, see https://help.sonatype.com/repomanager3/installation/configuring-the-runtime-environment
+// The fields left blank fall back to the same keys in `spec.properties`.
+type NexusConfiguration struct {
+	// HTTPPort is the port the Nexus server listens on for HTTP (`application-port`). The Nexus container runs as a non-root user, so it must be above 1023.
+	// Defaults: 8081
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HTTPPort int32 `json:"httpPort,omitempty"`
+	// AllowScriptCreation enables the creation of Groovy scripts through the REST API (`nexus.scripts.allowCreation`), disabled by default since Nexus 3.21.2.
+	// +optional
+	AllowScriptCreation bool `json:"allowScriptCreation,omitempty"`
+	// Datastore selects the database of the Nexus server: `OrientDB` or `H2`. The data isn't migrated when switching an existing instance to another datastore.
+	// Defaults: OrientDB
+	// +kubebuilder:validation:Enum=OrientDB;H2
+	// +optional
+	Datastore NexusDatastore `json:"datastore,omitempty"`
 }
 
 // NexusPersistence is the structure for the data persistent
@@ -134,6 +294,68 @@ type NexusPersistence struct {
 	ExtraVolumes []NexusVolume `json:"extraVolumes,omitempty"`
 }
 
+// NexusScheduling describes the constraints applied when scheduling the Nexus pods, see https://kubernetes.io/docs/concepts/scheduling-eviction/
+type NexusScheduling struct {
+	// NodeSelector restricts the Nexus pods to the nodes with these labels
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Affinity describes the node affinity, pod affinity and pod anti-affinity rules of the Nexus pods
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Tolerations allow the Nexus pods to run on nodes with matching taints
+	// +optional
+	// +listType=atomic
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// TopologySpreadConstraints describes how the Nexus pods are spread across the topology domains, such as zones
+	// +optional
+	// +listType=atomic
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the Nexus pods
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// ResourcePatchKind defines the kind of the resources targeted by a patch
+type ResourcePatchKind string
+
+const (
+	// DeploymentPatchKind the Nexus Deployment
+	DeploymentPatchKind ResourcePatchKind = "Deployment"
+	// ServicePatchKind the Nexus Service
+	ServicePatchKind ResourcePatchKind = "Service"
+	// IngressPatchKind the Ingress, if exposed via Ingress
+	IngressPatchKind ResourcePatchKind = "Ingress"
+	// RoutePatchKind the Routes, if exposed via Route
+	RoutePatchKind ResourcePatchKind = "Route"
+)
+
+// ResourcePatchType defines how a patch is merged into the resource
+type ResourcePatchType string
+
+const (
+	// StrategicMergePatchType Kubernetes strategic merge patch, lists such as containers, volumes or ports are merged by their key (e.g. `name`)
+	StrategicMergePatchType ResourcePatchType = "strategic"
+	// MergePatchType JSON merge patch (RFC 7386), lists are replaced as a whole
+	MergePatchType ResourcePatchType = "merge"
+)
+
+// ResourcePatch describes a patch applied to the resources generated by the Operator, see https://kubernetes.io/docs/tasks/manage-kubernetes-objects/update-api-object-kubectl-patch/
+type ResourcePatch struct {
+	// Kind of the patched resources: `Deployment`, `Service`, `Ingress` or `Route`.
+	// +kubebuilder:validation:Enum=Deployment;Service;Ingress;Route
+	Kind ResourcePatchKind `json:"kind"`
+	// Name of the patched resource. If left blank, all the resources of this kind are patched, e.g. all the Routes including the ones created for the Docker connectors.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Type of the patch: `strategic` or `merge`. Defaults to `strategic`.
+	// +kubebuilder:validation:Enum=strategic;merge
+	// +optional
+	Type ResourcePatchType `json:"type,omitempty"`
+	// Patch to apply, in YAML or JSON. For example: `{"spec": {"template": {"spec": {"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["repo.internal"]}]}}}}`
+	// +kubebuilder:validation:MinLength=1
+	Patch string `json:"patch"`
+}
+
 // NexusVolume embeds a Volume structure to represent a volume to be mounted in the Nexus pod at the specified MountPath
 type NexusVolume struct {
 	corev1.Volume `json:",inline"`
@@ -149,13 +371,59 @@ const (
 	NodePortExposeType NexusNetworkingExposeType = "NodePort"
 	// RouteExposeType On OpenShift, the service is exposed via a custom Route
 	RouteExposeType NexusNetworkingExposeType = "Route"
-	// IngressExposeType Supported on Kubernetes only, the service is exposed via NGINX Ingress
+	// IngressExposeType Supported on Kubernetes only, the service is exposed via Ingress
 	IngressExposeType NexusNetworkingExposeType = "Ingress"
+	// LoadBalancerExposeType The service is exposed via a load balancer provisioned by the cluster, e.g. by the cloud provider or MetalLB
+	LoadBalancerExposeType NexusNetworkingExposeType = "LoadBalancer"
+)
+
+// ContextPathProperty is the key in `nexus.properties` of the path under which the Nexus server is served
+const ContextPathProperty = "nexus-context-path"
+
+const (
+	// HTTPPortProperty is the key in `nexus.properties` of the port the Nexus server listens on for HTTP
+	HTTPPortProperty = "application-port"
+	// AllowScriptCreationProperty is the key in `nexus.properties` enabling the creation of scripts through the REST API
+	AllowScriptCreationProperty = "nexus.scripts.allowCreation"
+	// DatastoreEnabledProperty is the key in `nexus.properties` switching the Nexus server to the H2 datastore
+	DatastoreEnabledProperty = "nexus.datastore.enabled"
+	// DefaultNexusHTTPPort is the port the Nexus server listens on for HTTP unless set otherwise
+	DefaultNexusHTTPPort = int32(8081)
+)
+
+// NexusIngressProfile defines the Ingress controller specific tuning applied to the Ingress
+type NexusIngressProfile string
+
+const (
+	// GenericIngressProfile no controller specific annotations, works with any Ingress controller
+	GenericIngressProfile NexusIngressProfile = "generic"
+	// NginxIngressProfile the Kubernetes NGINX Ingress controller (k8s.io/ingress-nginx)
+	NginxIngressProfile NexusIngressProfile = "nginx"
+	// TraefikIngressProfile the Traefik Ingress controller (traefik.io/ingress-controller)
+	TraefikIngressProfile NexusIngressProfile = "traefik"
+	// HAProxyIngressProfile the HAProxy Ingress controller (haproxy-ingress.github.io/controller)
+	HAProxyIngressProfile NexusIngressProfile = "haproxy"
+	// ContourIngressProfile the Contour Ingress controller (projectcontour.io/ingress-controller)
+	ContourIngressProfile NexusIngressProfile = "contour"
+	// ALBIngressProfile the AWS Load Balancer controller (ingress.k8s.aws/alb)
+	ALBIngressProfile NexusIngressProfile = "alb"
+)
+
+// NexusTLSTermination defines where the TLS connections from the clients are terminated
+type NexusTLSTermination string
+
+const (
+	// EdgeTLSTermination the TLS connections are terminated by the Route or the Ingress controller, the Nexus server receives plain HTTP
+	EdgeTLSTermination NexusTLSTermination = "edge"
+	// ReencryptTLSTermination the TLS connections are terminated by the Route or the Ingress controller, which opens new ones to the Nexus server
+	ReencryptTLSTermination NexusTLSTermination = "reencrypt"
+	// PassthroughTLSTermination the TLS connections are terminated by the Nexus server itself, only available with Routes
+	PassthroughTLSTermination NexusTLSTermination = "passthrough"
 )
 
 // NexusNetworking is the base structure for Nexus networking information
 type NexusNetworking struct {
-	// Annotations that should be added to the Ingress/Route resource
+	// Annotations that should be added to the Ingress/Route resource, or to the Service if exposed via LoadBalancer
 	// +optional
 	// +nullable
 	Annotations map[string]string `json:"annotations,omitempty"`
@@ -165,14 +433,47 @@ type NexusNetworking struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// Set to `true` to expose the Nexus application. Defaults to `false`.
 	Expose bool `json:"expose,omitempty"`
-	// Type of networking exposure: NodePort, Route or Ingress. Defaults to Route on OpenShift and Ingress on Kubernetes.
+	// Type of networking exposure: NodePort, Route, Ingress or LoadBalancer. Defaults to Route on OpenShift and Ingress on Kubernetes.
 	// Routes are only available on Openshift and Ingresses are only available on Kubernetes.
-	// +kubebuilder:validation:Enum=NodePort;Route;Ingress
+	// +kubebuilder:validation:Enum=NodePort;Route;Ingress;LoadBalancer
 	ExposeAs NexusNetworkingExposeType `json:"exposeAs,omitempty"`
 	// Host where the Nexus service is exposed. This attribute is required if the service is exposed via Ingress.
 	Host string `json:"host,omitempty"`
+	// AdditionalHosts where the Nexus service is also exposed, e.g. both the internal and the external DNS names of the server.
+	// Only used if exposed via Route or Ingress.
+	// +listType=set
+	// +optional
+	AdditionalHosts []string `json:"additionalHosts,omitempty"`
+	// ContextPath is the path under which the Nexus server is served, e.g. `/nexus` to serve it at `https://tools.example.com/nexus`.
+	// Defaults to the `nexus-context-path` property in `spec.properties`, `/` if it's not set.
+	// +optional
+	ContextPath string `json:"contextPath,omitempty"`
 	// NodePort defined in the exposed service. Required if exposed via NodePort.
 	NodePort int32 `json:"nodePort,omitempty"`
+	// LoadBalancerIP requests a specific IP to the load balancer, if supported by the cloud provider or the load balancer implementation.
+	// Only used if exposed via LoadBalancer.
+	// +optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+	// LoadBalancerSourceRanges restricts the clients allowed to reach the load balancer to these CIDRs, e.g. `10.0.0.0/8`.
+	// Only used if exposed via LoadBalancer.
+	// +listType=set
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// IngressClassName is the name of the IngressClass of the controller serving the Ingress.
+	// Defaults to the default IngressClass of the cluster. If there's none, the legacy `kubernetes.io/ingress.class: nginx` annotation is added to `annotations` instead.
+	// Can't be set along with that annotation. Only used if exposed via Ingress.
+	// +optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// IngressProfile tunes the Ingress for the controller serving it, e.g. lifting the request body size limit so large artifacts can be uploaded.
+	// Possible values: `generic`, `nginx`, `traefik`, `haproxy`, `contour` or `alb`.
+	// Defaults to the profile matching the controller of the IngressClass, `generic` if there's none. Only used if exposed via Ingress.
+	// +kubebuilder:validation:Enum=generic;nginx;traefik;haproxy;contour;alb
+	// +optional
+	IngressProfile NexusIngressProfile `json:"ingressProfile,omitempty"`
+	// MaxBodySize is the maximum size of the requests accepted by the Ingress controller, e.g. `1g`. Defaults to no limit.
+	// Only used by the `nginx` and `haproxy` Ingress profiles.
+	// +optional
+	MaxBodySize string `json:"maxBodySize,omitempty"`
 	// TLS/SSL-related configuration
 	// +optional
 	TLS NexusNetworkingTLS `json:"tls,omitempty"`
@@ -220,20 +521,925 @@ type NexusNetworkingTLS struct {
 	// +optional
 	Mandatory bool `json:"mandatory,omitempty"`
 	// When exposing via Ingress, inform the name of the TLS secret containing certificate and private key for TLS encryption. It must be present in the same namespace as the Operator.
+	// When `certManager` is set, this is the Secret where cert-manager stores the issued certificate. Defaults to `<nexus name>-tls` in this case.
 	// +optional
 	SecretName string `json:"secretName,omitempty"`
+	// CertManager makes the Operator request a certificate for the exposed hosts to cert-manager (https://cert-manager.io) instead of expecting the TLS Secret to be provided.
+	// Requires cert-manager to be installed in the cluster.
+	// +optional
+	CertManager *CertManagerTLS `json:"certManager,omitempty"`
+	// Termination defines where the TLS connections are terminated. Defaults to `edge`, the traffic reaching the Nexus server in plain HTTP.
+	// With `reencrypt` the Route or the Ingress controller opens a new TLS connection to the Nexus server. With `passthrough`, only available with Routes, the Nexus server terminates the TLS connections itself.
+	// Both make the Nexus server serve HTTPS with the certificate from `serverSecretName`.
+	// +kubebuilder:validation:Enum=edge;reencrypt;passthrough
+	// +optional
+	Termination NexusTLSTermination `json:"termination,omitempty"`
+	// ServerSecretName is the name of the "kubernetes.io/tls" Secret with the certificate and private key served by the Nexus server when `termination` is `reencrypt` or `passthrough`.
+	// The Operator generates the server keystore from it. If the Secret has a "ca.crt" key, the re-encrypting Routes trust it to reach the server.
+	// Defaults to the Secret issued by cert-manager when using `certManager`, or to a service serving certificate on OpenShift with `reencrypt` (see `servingCertificate`).
+	// +optional
+	ServerSecretName string `json:"serverSecretName,omitempty"`
+	// ServingCertificate makes OpenShift issue the certificate served by the Nexus server into `serverSecretName` (service serving certificate). Only available on OpenShift.
+	// Re-encrypting Routes trust this certificate without further configuration.
+	// +optional
+	ServingCertificate bool `json:"servingCertificate,omitempty"`
+}
+
+// CertManagerTLS describes how the certificate for the exposed hosts is requested to cert-manager
+type CertManagerTLS struct {
+	// IssuerRef is the Issuer or ClusterIssuer that will issue the certificate
+	IssuerRef CertManagerIssuerRef `json:"issuerRef"`
+}
+
+// CertManagerIssuerRef references a cert-manager Issuer or ClusterIssuer
+type CertManagerIssuerRef struct {
+	// Name of the Issuer or ClusterIssuer. An Issuer must be in the same namespace as the Nexus CR.
+	Name string `json:"name"`
+	// Kind of the issuer, usually `Issuer` or `ClusterIssuer`. Defaults to `Issuer`.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Group of the issuer. Defaults to `cert-manager.io`, set it when using an external issuer.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// NexusSecurity describes how the users authenticate against the Nexus server
+type NexusSecurity struct {
+	// LDAP configures a LDAP (or Active Directory) server and enables the LDAP realm in the Nexus server
+	// +optional
+	LDAP *LDAPServer `json:"ldap,omitempty"`
+	// SAML configures the SAML single sign-on and enables the SAML realm in the Nexus server. Requires Nexus Repository Pro.
+	// +optional
+	SAML *SAML `json:"saml,omitempty"`
+	// RUTAuth enables the Remote User Token realm, trusting the user ID set in a HTTP header by a reverse proxy.
+	// Enabled by default along with `oauth2Proxy`.
+	// +optional
+	RUTAuth *RUTAuth `json:"rutAuth,omitempty"`
+	// OAuth2Proxy injects an oauth2-proxy sidecar in the Nexus pods, authenticating the traffic coming from the Ingress/Route
+	// against an OAuth/OpenID Connect provider before it reaches the Nexus server
+	// +optional
+	OAuth2Proxy *OAuth2Proxy `json:"oauth2Proxy,omitempty"`
+	// AnonymousAccess configures the access granted to the users that haven't signed in. Left as is in the server if not set.
+	// +optional
+	AnonymousAccess *AnonymousAccess `json:"anonymousAccess,omitempty"`
+	// Realms are the IDs of the active realms in the Nexus server, in the order they're tried, e.g. `NexusAuthenticatingRealm` and `DockerToken`.
+	// The realms not listed here are disabled, except the ones required by `ldap`, `saml` and `rutAuth`, which are appended if left out.
+	// `NexusAuthenticatingRealm` is always kept active, first if left out, since the operator signs in with a user stored in the server database.
+	// If not set, the active realms are left as is, only appending the required ones.
+	// +optional
+	// +listType=atomic
+	Realms []string `json:"realms,omitempty"`
+}
+
+const (
+	// NexusAuthenticatingRealm is the ID of the realm authenticating the users stored in the Nexus server database
+	NexusAuthenticatingRealm = "NexusAuthenticatingRealm"
+	// NexusAuthorizingRealm is the ID of the realm authorizing the users stored in the Nexus server database
+	NexusAuthorizingRealm = "NexusAuthorizingRealm"
+	// DockerTokenRealm is the ID of the Docker Bearer Token realm, required by anonymous Docker pulls
+	DockerTokenRealm = "DockerToken"
+	// NpmTokenRealm is the ID of the npm Bearer Token realm, required by `npm login`
+	NpmTokenRealm = "NpmToken"
+
+	// DefaultAnonymousUserID is the user whose privileges are granted to the anonymous users if none is given
+	DefaultAnonymousUserID = "anonymous"
+	// DefaultAnonymousRealm is the realm holding the anonymous user if none is given
+	DefaultAnonymousRealm = NexusAuthorizingRealm
+)
+
+// AnonymousAccess describes the access granted to the users that haven't signed in
+type AnonymousAccess struct {
+	// Enabled allows the users to browse and download from the Nexus server without signing in
+	Enabled bool `json:"enabled"`
+	// UserID is the user whose privileges are granted to the anonymous users. Defaults to `anonymous`.
+	// +optional
+	UserID string `json:"userID,omitempty"`
+	// Realm holding the anonymous user. Defaults to `NexusAuthorizingRealm`.
+	// +optional
+	Realm string `json:"realm,omitempty"`
+}
+
+const (
+	// DefaultLDAPServerName is the name of the LDAP server configuration in the Nexus server if none is given
+	DefaultLDAPServerName = "ldap"
+	// LDAPRealm is the ID of the LDAP realm in the Nexus server
+	LDAPRealm = "LdapRealm"
+)
+
+// LDAPProtocol is the protocol used to connect to a LDAP server
+type LDAPProtocol string
+
+const (
+	// LDAPProtocolLDAP connects to the LDAP server in plain text
+	LDAPProtocolLDAP LDAPProtocol = "ldap"
+	// LDAPProtocolLDAPS connects to the LDAP server using TLS
+	LDAPProtocolLDAPS LDAPProtocol = "ldaps"
+)
+
+// LDAPAuthScheme is the authentication method used to bind to a LDAP server
+type LDAPAuthScheme string
+
+const (
+	// LDAPAuthSchemeNone binds anonymously
+	LDAPAuthSchemeNone LDAPAuthScheme = "NONE"
+	// LDAPAuthSchemeSimple binds with a plain text password, better used along with `ldaps`
+	LDAPAuthSchemeSimple LDAPAuthScheme = "SIMPLE"
+	// LDAPAuthSchemeDigestMD5 binds using the DIGEST-MD5 SASL mechanism
+	LDAPAuthSchemeDigestMD5 LDAPAuthScheme = "DIGEST_MD5"
+	// LDAPAuthSchemeCramMD5 binds using the CRAM-MD5 SASL mechanism
+	LDAPAuthSchemeCramMD5 LDAPAuthScheme = "CRAM_MD5"
+)
+
+// LDAPGroupType is how the groups are represented in a LDAP server
+type LDAPGroupType string
+
+const (
+	// LDAPGroupTypeStatic groups are entries listing their members
+	LDAPGroupTypeStatic LDAPGroupType = "static"
+	// LDAPGroupTypeDynamic groups are listed by an attribute of the user entries, e.g. `memberOf`
+	LDAPGroupTypeDynamic LDAPGroupType = "dynamic"
+)
+
+// LDAPServer describes a LDAP server used to authenticate the users of the Nexus server
+type LDAPServer struct {
+	// Name of the LDAP server configuration in the Nexus server. Defaults to `ldap`.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Connection describes how to reach the LDAP server
+	Connection LDAPConnection `json:"connection"`
+	// UserMapping describes how the users are found in the LDAP server
+	UserMapping LDAPUserMapping `json:"userMapping"`
+	// GroupMapping describes how the groups are found in the LDAP server. If set, the LDAP groups are mapped to Nexus roles.
+	// +optional
+	GroupMapping *LDAPGroupMapping `json:"groupMapping,omitempty"`
+}
+
+// LDAPConnection describes how to reach a LDAP server
+type LDAPConnection struct {
+	// Protocol used to connect to the LDAP server: `ldap` or `ldaps`. Defaults to `ldap`.
+	// +kubebuilder:validation:Enum=ldap;ldaps
+	// +optional
+	Protocol LDAPProtocol `json:"protocol,omitempty"`
+	// Host of the LDAP server
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// Port of the LDAP server. Defaults to `389` for `ldap` and to `636` for `ldaps`.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// SearchBase is the LDAP location to be added to the connection URL, e.g. `dc=example,dc=com`
+	// +kubebuilder:validation:MinLength=1
+	SearchBase string `json:"searchBase"`
+	// AuthScheme is the authentication method used to bind to the LDAP server: `NONE`, `SIMPLE`, `DIGEST_MD5` or `CRAM_MD5`.
+	// Defaults to `SIMPLE` if `bindCredentialsSecret` is set, `NONE` otherwise.
+	// +kubebuilder:validation:Enum=NONE;SIMPLE;DIGEST_MD5;CRAM_MD5
+	// +optional
+	AuthScheme LDAPAuthScheme `json:"authScheme,omitempty"`
+	// AuthRealm is the SASL realm to bind to, used by the `DIGEST_MD5` and `CRAM_MD5` schemes
+	// +optional
+	AuthRealm string `json:"authRealm,omitempty"`
+	// BindCredentialsSecret references the Secret, in the same namespace of the Nexus CR, holding the username (usually a DN) and the password used to bind to the LDAP server.
+	// Required unless `authScheme` is `NONE`.
+	// +optional
+	BindCredentialsSecret *CredentialsSecret `json:"bindCredentialsSecret,omitempty"`
+	// CACertificate references the PEM encoded certificates of the authorities that signed the LDAP server certificate.
+	// They're added to the Nexus server truststore, which is then used to connect to the LDAP server.
+	// +optional
+	CACertificate *CertificateSource `json:"caCertificate,omitempty"`
+	// ConnectionTimeoutSeconds is how long to wait for the LDAP server to respond. Defaults to `30`.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	ConnectionTimeoutSeconds *int32 `json:"connectionTimeoutSeconds,omitempty"`
+	// RetryDelaySeconds is how long to wait before retrying a failed connection. Defaults to `300`.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryDelaySeconds *int32 `json:"retryDelaySeconds,omitempty"`
+	// MaxIncidentsCount is how many failed connections are tolerated before the LDAP server is blacklisted. Defaults to `3`.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxIncidentsCount *int32 `json:"maxIncidentsCount,omitempty"`
+}
+
+// CertificateSource references PEM encoded certificates stored either in a Secret or in a ConfigMap, in the same namespace of the Nexus CR
+type CertificateSource struct {
+	// SecretKeyRef selects a key of a Secret holding the certificates
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// ConfigMapKeyRef selects a key of a ConfigMap holding the certificates
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// LDAPUserMapping describes how the users are found in a LDAP server.
+// The defaults match the `inetOrgPerson` schema, Active Directory usually requires `objectClass: user` and `idAttribute: sAMAccountName`.
+type LDAPUserMapping struct {
+	// BaseDN is the location of the users relative to the search base, e.g. `ou=people`
+	// +optional
+	BaseDN string `json:"baseDN,omitempty"`
+	// Subtree enables searching the users in the whole subtree of `baseDN`. Defaults to `false`.
+	// +optional
+	Subtree bool `json:"subtree,omitempty"`
+	// ObjectClass of the user entries. Defaults to `inetOrgPerson`.
+	// +optional
+	ObjectClass string `json:"objectClass,omitempty"`
+	// LDAPFilter narrows the users found in the LDAP server, e.g. `(memberOf=cn=nexus,ou=groups,dc=example,dc=com)`
+	// +optional
+	LDAPFilter string `json:"ldapFilter,omitempty"`
+	// IDAttribute is the attribute holding the user ID. Defaults to `uid`.
+	// +optional
+	IDAttribute string `json:"idAttribute,omitempty"`
+	// RealNameAttribute is the attribute holding the user real name. Defaults to `cn`.
+	// +optional
+	RealNameAttribute string `json:"realNameAttribute,omitempty"`
+	// EmailAddressAttribute is the attribute holding the user email address. Defaults to `mail`.
+	// +optional
+	EmailAddressAttribute string `json:"emailAddressAttribute,omitempty"`
+	// PasswordAttribute is the attribute holding the user password. If not set, the users are authenticated by binding to the LDAP server.
+	// +optional
+	PasswordAttribute string `json:"passwordAttribute,omitempty"`
+}
+
+// LDAPGroupMapping describes how the groups are found in a LDAP server
+type LDAPGroupMapping struct {
+	// Type of the groups: `static` groups are entries listing their members, `dynamic` groups are listed in the user entries
+	// +kubebuilder:validation:Enum=static;dynamic
+	Type LDAPGroupType `json:"type"`
+	// BaseDN is the location of the static groups relative to the search base, e.g. `ou=groups`
+	// +optional
+	BaseDN string `json:"baseDN,omitempty"`
+	// Subtree enables searching the static groups in the whole subtree of `baseDN`. Defaults to `false`.
+	// +optional
+	Subtree bool `json:"subtree,omitempty"`
+	// ObjectClass of the static group entries. Defaults to `groupOfUniqueNames`.
+	// +optional
+	ObjectClass string `json:"objectClass,omitempty"`
+	// IDAttribute is the attribute holding the static group ID. Defaults to `cn`.
+	// +optional
+	IDAttribute string `json:"idAttribute,omitempty"`
+	// MemberAttribute is the attribute of the static groups holding their members. Defaults to `uniqueMember`.
+	// +optional
+	MemberAttribute string `json:"memberAttribute,omitempty"`
+	// MemberFormat is the format of the members in the static groups, e.g. `${username}` or `${dn}`. Defaults to `${dn}`.
+	// +optional
+	MemberFormat string `json:"memberFormat,omitempty"`
+	// MemberOfAttribute is the attribute of the user entries listing their dynamic groups. Defaults to `memberOf`.
+	// +optional
+	MemberOfAttribute string `json:"memberOfAttribute,omitempty"`
+}
+
+const (
+	// SAMLRealm is the ID of the SAML realm in the Nexus server
+	SAMLRealm = "SamlRealm"
+	// RUTAuthRealm is the ID of the Remote User Token realm in the Nexus server
+	RUTAuthRealm = "rutauth-realm"
+	// DefaultRUTAuthHeader is the HTTP header holding the user ID if none is given, the one set by oauth2-proxy
+	DefaultRUTAuthHeader = "X-Forwarded-User"
+)
+
+// SAML describes the SAML identity provider used for single sign-on. Requires Nexus Repository Pro.
+type SAML struct {
+	// IdPMetadata selects the key of a ConfigMap, in the same namespace of the Nexus CR, holding the identity provider metadata XML
+	IdPMetadata corev1.ConfigMapKeySelector `json:"idpMetadata"`
+	// EntityID is the URI identifying the Nexus server to the identity provider. Defaults to `<base URL>/service/rest/v1/security/saml/metadata`.
+	// +optional
+	EntityID string `json:"entityID,omitempty"`
+	// ValidateResponseSignature enables the validation of the SAML responses signature. Defaults to the identity provider metadata.
+	// +optional
+	ValidateResponseSignature *bool `json:"validateResponseSignature,omitempty"`
+	// ValidateAssertionSignature enables the validation of the SAML assertions signature. Defaults to the identity provider metadata.
+	// +optional
+	ValidateAssertionSignature *bool `json:"validateAssertionSignature,omitempty"`
+	// UsernameAttribute is the SAML attribute holding the user ID
+	// +kubebuilder:validation:MinLength=1
+	UsernameAttribute string `json:"usernameAttribute"`
+	// FirstNameAttribute is the SAML attribute holding the user first name
+	// +optional
+	FirstNameAttribute string `json:"firstNameAttribute,omitempty"`
+	// LastNameAttribute is the SAML attribute holding the user last name
+	// +optional
+	LastNameAttribute string `json:"lastNameAttribute,omitempty"`
+	// EmailAttribute is the SAML attribute holding the user email address
+	// +optional
+	EmailAttribute string `json:"emailAttribute,omitempty"`
+	// GroupsAttribute is the SAML attribute holding the user groups, mapped to Nexus roles
+	// +optional
+	GroupsAttribute string `json:"groupsAttribute,omitempty"`
+}
+
+// RUTAuth describes how the Remote User Token realm reads the ID of the users authenticated by a reverse proxy
+type RUTAuth struct {
+	// HeaderName is the HTTP header holding the user ID. Defaults to `X-Forwarded-User`.
+	// +optional
+	HeaderName string `json:"headerName,omitempty"`
+}
+
+const (
+	// OAuth2ProxyClientIDKey is the key holding the OAuth client ID in the oauth2-proxy Secret
+	OAuth2ProxyClientIDKey = "client-id"
+	// OAuth2ProxyClientSecretKey is the key holding the OAuth client secret in the oauth2-proxy Secret
+	OAuth2ProxyClientSecretKey = "client-secret"
+	// OAuth2ProxyCookieSecretKey is the key holding the seed for the secure cookies in the oauth2-proxy Secret
+	OAuth2ProxyCookieSecretKey = "cookie-secret"
+)
+
+// OAuth2Proxy describes the oauth2-proxy sidecar authenticating the traffic coming from the Ingress/Route before it reaches the Nexus server
+type OAuth2Proxy struct {
+	// Image of the oauth2-proxy sidecar. Defaults to `quay.io/oauth2-proxy/oauth2-proxy:v7.1.3`.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// ImagePullPolicy of the oauth2-proxy sidecar. If left blank behavior will be determined by the image tag.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// Resources of the oauth2-proxy sidecar
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Provider is the OAuth provider, see the oauth2-proxy `--provider` flag. Defaults to `oidc`.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// OIDCIssuerURL is the URL of the OpenID Connect issuer, e.g. `https://keycloak.example.com/auth/realms/example`
+	// +optional
+	OIDCIssuerURL string `json:"oidcIssuerURL,omitempty"`
+	// ClientSecretName is the name of the Secret, in the same namespace of the Nexus CR, holding the `client-id`, `client-secret` and `cookie-secret` keys
+	// +kubebuilder:validation:MinLength=1
+	ClientSecretName string `json:"clientSecretName"`
+	// EmailDomains are the email domains allowed to authenticate. Defaults to `*`, every domain.
+	// +optional
+	// +listType=atomic
+	EmailDomains []string `json:"emailDomains,omitempty"`
+	// ExtraArgs are appended to the oauth2-proxy command line, e.g. `--skip-auth-route=^/repository/`
+	// +optional
+	// +listType=atomic
+	ExtraArgs []string `json:"extraArgs,omitempty"`
 }
 
 // ServerOperationsOpts describes the options for the operations performed in the Nexus server deployed instance
 type ServerOperationsOpts struct {
-	// DisableRepositoryCreation disables the auto-creation of Apache, JBoss and Red Hat repositories and their addition to
-	// the Maven Public group in this Nexus instance.
-	// Defaults to `false` (always try to create the repos). Set this to `true` to not create them. Only works if `spec.generateRandomAdminPassword` is `false`.
+	// DisableRepositoryCreation disables the auto-creation of the community Maven proxies (Apache, JBoss and Red Hat unless set in `communityMavenProxies`)
+	// and their addition to the Maven Public group in this Nexus instance.
+	// Defaults to `false` (always try to create the repos). Set this to `true` to not create them.
 	DisableRepositoryCreation bool `json:"disableRepositoryCreation,omitempty"`
+	// CommunityMavenProxies describes which community Maven proxies are created and the group they're added to.
+	// Defaults to the Apache, JBoss and Red Hat proxies added to the `maven-public` group.
+	// +optional
+	CommunityMavenProxies *CommunityMavenProxies `json:"communityMavenProxies,omitempty"`
 	// DisableOperatorUserCreation disables the auto-creation of the `nexus-operator` user on the deployed server. This user performs
 	// all the operations on the server (such as creating the community repos). If disabled, the Operator will use the default `admin` user.
 	// Defaults to `false` (always create the user). Setting this to `true` is not recommended as it grants the Operator more privileges than it needs and it would not be possible to tell apart operations performed by the `admin` and the Operator.
 	DisableOperatorUserCreation bool `json:"disableOperatorUserCreation,omitempty"`
+	// AdminCredentialsSecret references the Secret holding the credentials of an administrator user in the Nexus server.
+	// These credentials are used to bootstrap the server operations, such as creating the operator user, instead of the default `admin` ones.
+	// +optional
+	AdminCredentialsSecret *AdminCredentialsSecret `json:"adminCredentialsSecret,omitempty"`
+	// CompleteOnboarding completes the onboarding wizard of a server whose admin password has been randomly generated (see `spec.generateRandomAdminPassword`).
+	// The generated password is replaced by a new random one, stored in the instance Secret, and the current anonymous access configuration is confirmed.
+	// Defaults to `false`.
+	// +optional
+	CompleteOnboarding bool `json:"completeOnboarding,omitempty"`
+	// CredentialRotation enables the periodic rotation of the operator user password.
+	// +optional
+	CredentialRotation *CredentialRotation `json:"credentialRotation,omitempty"`
+	// HTTPProxy configures the proxy servers used by the Nexus server for outbound requests, such as the ones made by proxy repositories.
+	// If not set, the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables of the Operator pod are used instead.
+	// +optional
+	HTTPProxy *HTTPProxy `json:"httpProxy,omitempty"`
+}
+
+// HTTPProxy describes the proxy servers used by the Nexus server for outbound requests
+type HTTPProxy struct {
+	// HTTP is the proxy server for outbound HTTP requests. Defaults to the `HTTP_PROXY` environment variable of the Operator pod.
+	// +optional
+	HTTP *ProxyServer `json:"http,omitempty"`
+	// HTTPS is the proxy server for outbound HTTPS requests. Defaults to the `HTTPS_PROXY` environment variable of the Operator pod.
+	// +optional
+	HTTPS *ProxyServer `json:"https,omitempty"`
+	// NonProxyHosts are the hosts reached without going through the proxy servers, e.g. `*.example.com`.
+	// Defaults to the `NO_PROXY` environment variable of the Operator pod.
+	// +optional
+	// +listType=atomic
+	NonProxyHosts []string `json:"nonProxyHosts,omitempty"`
+	// IgnoreEnvironment stops the Operator from reading the proxy settings not set here from its own environment variables. Defaults to `false`.
+	// +optional
+	IgnoreEnvironment bool `json:"ignoreEnvironment,omitempty"`
+}
+
+// ProxyServer describes a proxy server used for outbound requests
+type ProxyServer struct {
+	// Host of the proxy server
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// Port of the proxy server
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// CredentialsSecret references the Secret, in the same namespace of the Nexus CR, holding the credentials to authenticate against the proxy server
+	// +optional
+	CredentialsSecret *CredentialsSecret `json:"credentialsSecret,omitempty"`
+}
+
+const (
+	// DefaultCredentialsUsernameKey is the key holding the username in a credentials Secret if none is given
+	DefaultCredentialsUsernameKey = "username"
+	// DefaultCredentialsPasswordKey is the key holding the password in a credentials Secret if none is given
+	DefaultCredentialsPasswordKey = "password"
+)
+
+// CredentialsSecret references a Secret holding a username and a password, such as the ones of the `kubernetes.io/basic-auth` type
+type CredentialsSecret struct {
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// UsernameKey is the key in the Secret holding the username. Defaults to `username`.
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// PasswordKey is the key in the Secret holding the password. Defaults to `password`.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// CredentialRotation configures the periodic rotation of the operator user password
+type CredentialRotation struct {
+	// Period between two rotations of the operator user password, e.g. `2160h` for 90 days.
+	// The new password is stored in the Secret with the same name as the Nexus CR.
+	Period metav1.Duration `json:"period"`
+}
+
+// DefaultCommunityMavenGroupName is the group the community Maven proxies are added to if none is given
+const DefaultCommunityMavenGroupName = "maven-public"
+
+// CommunityMavenProxies describes the community Maven proxies created by the Operator
+type CommunityMavenProxies struct {
+	// Proxies to create in the server. Defaults to the Apache, JBoss and Red Hat proxies.
+	// Proxies already in the server are not changed.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Proxies []CommunityMavenProxy `json:"proxies,omitempty"`
+	// GroupName is the name of the Maven group the proxies are added to. Defaults to `maven-public`.
+	// The proxies aren't added to any group if it doesn't exist in the server.
+	// +optional
+	GroupName string `json:"groupName,omitempty"`
+}
+
+// CommunityMavenProxy describes a Maven proxy repository created by the Operator
+type CommunityMavenProxy struct {
+	// Name of the repository in the Nexus server
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// RemoteURL is the location of the remote repository being proxied. Only used when the repository is created.
+	// +kubebuilder:validation:MinLength=1
+	RemoteURL string `json:"remoteURL"`
+	// VersionPolicy defines which type of artifacts the repository stores. Possible values: `RELEASE`, `SNAPSHOT` or `MIXED`. Defaults to `RELEASE`.
+	// Only used when the repository is created, the Nexus server doesn't allow changing it afterwards.
+	// +kubebuilder:validation:Enum=RELEASE;SNAPSHOT;MIXED
+	// +optional
+	VersionPolicy MavenVersionPolicy `json:"versionPolicy,omitempty"`
+	// ContentMaxAge is how long (in minutes) to cache artifacts before rechecking the remote repository. Defaults to `-1` (cached forever).
+	// +optional
+	ContentMaxAge *int32 `json:"contentMaxAge,omitempty"`
+	// MetadataMaxAge is how long (in minutes) to cache metadata before rechecking the remote repository. Defaults to `1440`.
+	// +optional
+	MetadataMaxAge *int32 `json:"metadataMaxAge,omitempty"`
+	// NegativeCacheTTL is how long (in minutes) to cache the fact that a file was not found in the repository. Defaults to `1440`.
+	// +optional
+	NegativeCacheTTL *int32 `json:"negativeCacheTTL,omitempty"`
+	// BlobStoreName is the name of the blob store used by the repository. Defaults to `default`.
+	// Only used when the repository is created, the Nexus server doesn't allow changing it afterwards.
+	// +optional
+	BlobStoreName string `json:"blobStoreName,omitempty"`
+	// Authentication against the remote repository
+	// +optional
+	Authentication *RepositoryAuthentication `json:"authentication,omitempty"`
+}
+
+const (
+	// DefaultAdminCredentialsUsernameKey is the key holding the administrator username in the admin credentials Secret if none is given
+	DefaultAdminCredentialsUsernameKey = "username"
+	// DefaultAdminCredentialsPasswordKey is the key holding the administrator password in the admin credentials Secret if none is given
+	DefaultAdminCredentialsPasswordKey = "password"
+)
+
+// AdminCredentialsSecret references a Secret, in the same namespace of the Nexus CR, holding the credentials of an administrator user
+type AdminCredentialsSecret struct {
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// UsernameKey is the key in the Secret holding the username. Defaults to `username`.
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+	// PasswordKey is the key in the Secret holding the password. Defaults to `password`.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// RotateDefaultPassword changes the default `admin` password to the one in the Secret if the server still accepts the default one,
+	// which is the case on its first boot. Only works if the username in the Secret is `admin`. Defaults to `false`.
+	// +optional
+	RotateDefaultPassword bool `json:"rotateDefaultPassword,omitempty"`
+}
+
+// RepositoryFormat is the format of a repository in the Nexus server
+type RepositoryFormat string
+
+const (
+	// MavenRepositoryFormat Maven 2 repositories
+	MavenRepositoryFormat RepositoryFormat = "maven2"
+	// NpmRepositoryFormat npm registries
+	NpmRepositoryFormat RepositoryFormat = "npm"
+	// DockerRepositoryFormat Docker registries
+	DockerRepositoryFormat RepositoryFormat = "docker"
+	// PyPIRepositoryFormat Python package indexes
+	PyPIRepositoryFormat RepositoryFormat = "pypi"
+	// RawRepositoryFormat plain files
+	RawRepositoryFormat RepositoryFormat = "raw"
+	// HelmRepositoryFormat Helm charts. Group repositories are not supported for this format.
+	HelmRepositoryFormat RepositoryFormat = "helm"
+	// GoRepositoryFormat Go modules. Hosted repositories are not supported for this format.
+	GoRepositoryFormat RepositoryFormat = "go"
+)
+
+// RepositoryType is the type of a repository in the Nexus server
+type RepositoryType string
+
+const (
+	// HostedRepositoryType repositories storing components published to the Nexus server
+	HostedRepositoryType RepositoryType = "hosted"
+	// ProxyRepositoryType repositories caching components from a remote repository
+	ProxyRepositoryType RepositoryType = "proxy"
+	// GroupRepositoryType repositories aggregating other repositories under a single URL
+	GroupRepositoryType RepositoryType = "group"
+)
+
+// RepositoryWritePolicy controls if deployments and updates of artifacts are allowed in a hosted repository
+type RepositoryWritePolicy string
+
+const (
+	// AllowWritePolicy allows redeploying artifacts
+	AllowWritePolicy RepositoryWritePolicy = "ALLOW"
+	// AllowOnceWritePolicy disables redeploying artifacts
+	AllowOnceWritePolicy RepositoryWritePolicy = "ALLOW_ONCE"
+	// DenyWritePolicy makes the repository read-only
+	DenyWritePolicy RepositoryWritePolicy = "DENY"
+)
+
+// Repository describes a repository managed by the Operator in the Nexus server
+type Repository struct {
+	// Name of the repository in the Nexus server. Can't be changed once the repository is created.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Format of the repository. Can't be changed once the repository is created.
+	// Possible values: `maven2`, `npm`, `docker`, `pypi`, `raw`, `helm` or `go`.
+	// +kubebuilder:validation:Enum=maven2;npm;docker;pypi;raw;helm;go
+	Format RepositoryFormat `json:"format"`
+	// Type of the repository. Can't be changed once the repository is created.
+	// Possible values: `hosted`, `proxy` or `group`.
+	// +kubebuilder:validation:Enum=hosted;proxy;group
+	Type RepositoryType `json:"type"`
+	// Online defines if the repository accepts incoming requests. Defaults to `true`.
+	// +optional
+	Online *bool `json:"online,omitempty"`
+	// Storage configuration for this repository
+	// +optional
+	Storage RepositoryStorage `json:"storage,omitempty"`
+	// Proxy configuration. Required if `type` is `proxy`.
+	// +optional
+	Proxy *RepositoryProxy `json:"proxy,omitempty"`
+	// Group configuration. Required if `type` is `group`.
+	// +optional
+	Group *RepositoryGroup `json:"group,omitempty"`
+	// Maven specific configuration. Only used if `format` is `maven2`.
+	// +optional
+	Maven *RepositoryMaven `json:"maven,omitempty"`
+	// Docker specific configuration. Only used if `format` is `docker`.
+	// +optional
+	Docker *RepositoryDocker `json:"docker,omitempty"`
+	// Cleanup configuration. Not supported by group repositories.
+	// +optional
+	Cleanup *RepositoryCleanup `json:"cleanup,omitempty"`
+	// HTTPClient configuration used to reach the remote repository. Only used if `type` is `proxy`.
+	// +optional
+	HTTPClient *RepositoryHTTPClient `json:"httpClient,omitempty"`
+}
+
+// RepositoryHTTPClient describes how a proxy repository reaches the remote repository
+type RepositoryHTTPClient struct {
+	// Authentication against the remote repository
+	// +optional
+	Authentication *RepositoryAuthentication `json:"authentication,omitempty"`
+}
+
+// RepositoryAuthenticationType is the authentication scheme used against a remote repository
+type RepositoryAuthenticationType string
+
+const (
+	// UsernameAuthenticationType authenticates with a username and a password
+	UsernameAuthenticationType RepositoryAuthenticationType = "username"
+	// NTLMAuthenticationType authenticates with a username and a password using the Windows NTLM scheme
+	NTLMAuthenticationType RepositoryAuthenticationType = "ntlm"
+)
+
+// RepositoryAuthentication describes the credentials used to authenticate against a remote repository
+type RepositoryAuthentication struct {
+	// Type of authentication. Possible values: `username` or `ntlm`. Defaults to `username`.
+	// +kubebuilder:validation:Enum=username;ntlm
+	// +optional
+	Type RepositoryAuthenticationType `json:"type,omitempty"`
+	// CredentialsSecret references the Secret, in the same namespace of the Nexus CR, holding the username and password
+	CredentialsSecret CredentialsSecret `json:"credentialsSecret"`
+	// NTLMHost is the Windows NT Lan Manager host. Only used by the `ntlm` type.
+	// +optional
+	NTLMHost string `json:"ntlmHost,omitempty"`
+	// NTLMDomain is the Windows NT Lan Manager domain. Only used by the `ntlm` type.
+	// +optional
+	NTLMDomain string `json:"ntlmDomain,omitempty"`
+}
+
+// RepositoryCleanup describes the cleanup policies applied to a repository
+type RepositoryCleanup struct {
+	// PolicyNames are the names of the cleanup policies applied to the repository by the "Admin - Cleanup repositories using their associated policies" task.
+	// The policies must match the repository format.
+	// +listType=atomic
+	PolicyNames []string `json:"policyNames"`
+}
+
+// RepositoryStorage describes how a repository stores its components
+type RepositoryStorage struct {
+	// BlobStoreName is the name of the blob store used by the repository. Defaults to `default`.
+	// +optional
+	BlobStoreName string `json:"blobStoreName,omitempty"`
+	// StrictContentTypeValidation validates that all content uploaded to this repository is of a MIME type appropriate for the repository format.
+	// Defaults to `true`.
+	// +optional
+	StrictContentTypeValidation *bool `json:"strictContentTypeValidation,omitempty"`
+	// WritePolicy controls if deployments of and updates to artifacts are allowed. Only used by hosted repositories.
+	// Possible values: `ALLOW`, `ALLOW_ONCE` or `DENY`. Defaults to `ALLOW_ONCE`.
+	// +kubebuilder:validation:Enum=ALLOW;ALLOW_ONCE;DENY
+	// +optional
+	WritePolicy RepositoryWritePolicy `json:"writePolicy,omitempty"`
+}
+
+// RepositoryProxy describes the remote repository proxied by a proxy repository
+type RepositoryProxy struct {
+	// RemoteURL is the location of the remote repository being proxied
+	// +kubebuilder:validation:MinLength=1
+	RemoteURL string `json:"remoteURL"`
+	// ContentMaxAge is how long (in minutes) to cache artifacts before rechecking the remote repository. Defaults to `1440`.
+	// +optional
+	ContentMaxAge *int32 `json:"contentMaxAge,omitempty"`
+	// MetadataMaxAge is how long (in minutes) to cache metadata before rechecking the remote repository. Defaults to `1440`.
+	// +optional
+	MetadataMaxAge *int32 `json:"metadataMaxAge,omitempty"`
+	// NegativeCacheEnabled caches responses for content not present in the proxied repository. Defaults to `true`.
+	// +optional
+	NegativeCacheEnabled *bool `json:"negativeCacheEnabled,omitempty"`
+	// NegativeCacheTTL is how long (in minutes) to cache the fact that a file was not found in the repository. Defaults to `1440`.
+	// +optional
+	NegativeCacheTTL *int32 `json:"negativeCacheTTL,omitempty"`
+}
+
+// RepositoryGroup describes the members of a group repository
+type RepositoryGroup struct {
+	// MemberNames are the names of the repositories aggregated by this group, in order of resolution
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	MemberNames []string `json:"memberNames"`
+}
+
+// MavenVersionPolicy defines which type of artifacts a Maven repository stores
+type MavenVersionPolicy string
+
+const (
+	// ReleaseVersionPolicy Maven release artifacts
+	ReleaseVersionPolicy MavenVersionPolicy = "RELEASE"
+	// SnapshotVersionPolicy Maven snapshot artifacts
+	SnapshotVersionPolicy MavenVersionPolicy = "SNAPSHOT"
+	// MixedVersionPolicy both Maven release and snapshot artifacts
+	MixedVersionPolicy MavenVersionPolicy = "MIXED"
+)
+
+// MavenLayoutPolicy defines how strictly paths are validated in a Maven repository
+type MavenLayoutPolicy string
+
+const (
+	// StrictLayoutPolicy only accepts paths following the Maven layout
+	StrictLayoutPolicy MavenLayoutPolicy = "STRICT"
+	// PermissiveLayoutPolicy accepts any path
+	PermissiveLayoutPolicy MavenLayoutPolicy = "PERMISSIVE"
+)
+
+// RepositoryMaven describes the configuration specific to Maven repositories
+type RepositoryMaven struct {
+	// VersionPolicy defines what type of artifacts this repository stores.
+	// Possible values: `RELEASE`, `SNAPSHOT` or `MIXED`. Defaults to `RELEASE`.
+	// +kubebuilder:validation:Enum=RELEASE;SNAPSHOT;MIXED
+	// +optional
+	VersionPolicy MavenVersionPolicy `json:"versionPolicy,omitempty"`
+	// LayoutPolicy validates that all paths are Maven artifacts or metadata paths.
+	// Possible values: `STRICT` or `PERMISSIVE`. Defaults to `STRICT`.
+	// +kubebuilder:validation:Enum=STRICT;PERMISSIVE
+	// +optional
+	LayoutPolicy MavenLayoutPolicy `json:"layoutPolicy,omitempty"`
+}
+
+// RepositoryDocker describes the configuration specific to Docker repositories
+type RepositoryDocker struct {
+	// V1Enabled allows clients to use the V1 API to interact with this repository. Defaults to `false`.
+	// +optional
+	V1Enabled bool `json:"v1Enabled,omitempty"`
+	// ForceBasicAuth disables the Docker Bearer Token Realm for this repository. Defaults to `true`.
+	// +optional
+	ForceBasicAuth *bool `json:"forceBasicAuth,omitempty"`
+	// HTTPPort is the port of the HTTP connector created by the Nexus server for this repository
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HTTPPort *int32 `json:"httpPort,omitempty"`
+	// Host is the dedicated host exposing the HTTP connector when `spec.networking.expose` is `true`, e.g. `registry.example.com`.
+	// Only used if `httpPort` is set. Required to expose the connector through an Ingress.
+	// Routes without a host are given one generated by the cluster.
+	// Connectors of repositories managed by NexusRepository resources aren't exposed by the Operator.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// IndexType is the type of index used by Docker proxy repositories.
+	// Possible values: `REGISTRY`, `HUB` or `CUSTOM`. Defaults to `REGISTRY`.
+	// +kubebuilder:validation:Enum=REGISTRY;HUB;CUSTOM
+	// +optional
+	IndexType string `json:"indexType,omitempty"`
+	// IndexURL is the URL of the index used by Docker proxy repositories. Required if `indexType` is `CUSTOM`.
+	// +optional
+	IndexURL string `json:"indexURL,omitempty"`
+}
+
+// BlobStoreType is the type of a blob store in the Nexus server
+type BlobStoreType string
+
+const (
+	// FileBlobStoreType blob stores keeping the blobs in the file system of the Nexus server
+	FileBlobStoreType BlobStoreType = "File"
+	// S3BlobStoreType blob stores keeping the blobs in an AWS S3 (or compatible) bucket
+	S3BlobStoreType BlobStoreType = "S3"
+)
+
+// BlobStoreSoftQuotaType is the type of a blob store soft quota
+type BlobStoreSoftQuotaType string
+
+const (
+	// SpaceRemainingQuotaType is violated when the space available to the blob store is lower than the limit
+	SpaceRemainingQuotaType BlobStoreSoftQuotaType = "spaceRemainingQuota"
+	// SpaceUsedQuotaType is violated when the space used by the blob store is greater than the limit
+	SpaceUsedQuotaType BlobStoreSoftQuotaType = "spaceUsedQuota"
+)
+
+// BlobStore describes a blob store managed by the Operator in the Nexus server
+type BlobStore struct {
+	// Name of the blob store in the Nexus server, referenced by the repositories in `storage.blobStoreName`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Type of the blob store. Possible values: `File` or `S3`. Can't be changed once the blob store is created.
+	// +kubebuilder:validation:Enum=File;S3
+	Type BlobStoreType `json:"type"`
+	// File holds the configuration of `File` blob stores
+	// +optional
+	File *FileBlobStore `json:"file,omitempty"`
+	// S3 holds the configuration of `S3` blob stores. Required if the type is `S3`.
+	// +optional
+	S3 *S3BlobStore `json:"s3,omitempty"`
+	// SoftQuota raises an alert in the Nexus server when the blob store usage crosses the limit. No writes are prevented.
+	// +optional
+	SoftQuota *BlobStoreSoftQuota `json:"softQuota,omitempty"`
+}
+
+// FileBlobStore describes where a `File` blob store keeps its blobs
+type FileBlobStore struct {
+	// ExtraVolume is the name of one of the volumes in `spec.persistence.extraVolumes` to keep the blobs in.
+	// If not set, the blobs are kept in the Nexus data volume.
+	// +optional
+	ExtraVolume string `json:"extraVolume,omitempty"`
+	// Path where the blobs are kept. If relative, it's resolved against the mount path of the extra volume
+	// or, if no extra volume is given, against the Nexus blobs directory. Defaults to the blob store name.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// S3BlobStore describes the bucket used by a `S3` blob store
+type S3BlobStore struct {
+	// Bucket is the name of the S3 bucket, created by the Nexus server if it doesn't exist
+	// +kubebuilder:validation:MinLength=3
+	Bucket string `json:"bucket"`
+	// Region of the bucket. Defaults to `DEFAULT`, which lets the Nexus server resolve it.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// Prefix of the objects stored in the bucket
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Expiration is how many days to wait before deleting blobs marked as deleted. A negative value disables the deletion.
+	// Defaults to `3`.
+	// +optional
+	Expiration *int32 `json:"expiration,omitempty"`
+	// Endpoint is the URL of a S3 compatible storage. Defaults to AWS.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// ForcePathStyle uses path-style access to the bucket, often required by S3 compatible storages. Defaults to `false`.
+	// +optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+	// CredentialsSecret references the Secret, in the same namespace of the Nexus CR, holding the credentials to access the bucket.
+	// If not set, the Nexus server relies on the default AWS credentials chain (e.g. an IAM role).
+	// +optional
+	CredentialsSecret *S3CredentialsSecret `json:"credentialsSecret,omitempty"`
+}
+
+const (
+	// DefaultS3AccessKeyIDKey is the key holding the access key ID in the S3 credentials Secret if none is given
+	DefaultS3AccessKeyIDKey = "accessKeyId"
+	// DefaultS3SecretAccessKeyKey is the key holding the secret access key in the S3 credentials Secret if none is given
+	DefaultS3SecretAccessKeyKey = "secretAccessKey"
+)
+
+// S3CredentialsSecret references a Secret holding the credentials to access a S3 bucket
+type S3CredentialsSecret struct {
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// AccessKeyIDKey is the key in the Secret holding the access key ID. Defaults to `accessKeyId`.
+	// +optional
+	AccessKeyIDKey string `json:"accessKeyIdKey,omitempty"`
+	// SecretAccessKeyKey is the key in the Secret holding the secret access key. Defaults to `secretAccessKey`.
+	// +optional
+	SecretAccessKeyKey string `json:"secretAccessKeyKey,omitempty"`
+	// SessionTokenKey is the key in the Secret holding the session token, if any
+	// +optional
+	SessionTokenKey string `json:"sessionTokenKey,omitempty"`
+}
+
+// BlobStoreSoftQuota describes the limit of a blob store soft quota
+type BlobStoreSoftQuota struct {
+	// Type of the quota. Possible values: `spaceRemainingQuota` or `spaceUsedQuota`.
+	// +kubebuilder:validation:Enum=spaceRemainingQuota;spaceUsedQuota
+	Type BlobStoreSoftQuotaType `json:"type"`
+	// Limit of the quota, e.g. `10Gi`. Rounded down to megabytes.
+	Limit resource.Quantity `json:"limit"`
+}
+
+// CleanupReleaseType is the type of Maven or npm components deleted by a cleanup policy
+type CleanupReleaseType string
+
+const (
+	// ReleasesCleanupReleaseType only deletes releases
+	ReleasesCleanupReleaseType CleanupReleaseType = "RELEASES"
+	// PrereleasesCleanupReleaseType only deletes pre-releases (e.g. Maven snapshots)
+	PrereleasesCleanupReleaseType CleanupReleaseType = "PRERELEASES"
+)
+
+// CleanupPolicy describes a cleanup policy managed by the Operator in the Nexus server.
+// Components matching every criteria set are deleted when the policy runs.
+type CleanupPolicy struct {
+	// Name of the cleanup policy in the Nexus server. Can't be changed once the policy is created.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Notes describing the policy
+	// +optional
+	Notes string `json:"notes,omitempty"`
+	// Format of the repositories the policy applies to.
+	// Possible values: `maven2`, `npm`, `docker`, `pypi`, `raw`, `helm` or `go`.
+	// +kubebuilder:validation:Enum=maven2;npm;docker;pypi;raw;helm;go
+	Format RepositoryFormat `json:"format"`
+	// LastBlobUpdatedDays deletes components published more than the given number of days ago
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LastBlobUpdatedDays *int32 `json:"lastBlobUpdatedDays,omitempty"`
+	// LastDownloadedDays deletes components last downloaded more than the given number of days ago
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LastDownloadedDays *int32 `json:"lastDownloadedDays,omitempty"`
+	// ReleaseType restricts the policy to release or pre-release components. Only used by `maven2` and `npm` policies.
+	// Possible values: `RELEASES` or `PRERELEASES`.
+	// +kubebuilder:validation:Enum=RELEASES;PRERELEASES
+	// +optional
+	ReleaseType CleanupReleaseType `json:"releaseType,omitempty"`
+	// AssetRegex deletes components with at least one asset whose path matches the given regular expression
+	// +optional
+	AssetRegex string `json:"assetRegex,omitempty"`
+}
+
+// Task describes a scheduled task managed by the Operator in the Nexus server
+type Task struct {
+	// Name of the task in the Nexus server
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Type of the task. Can't be changed once the task is created.
+	// For example: `blobstore.compact`, `repository.cleanup`, `repository.maven.rebuild-metadata`, `repository.rebuild-index` or `repository.docker.gc`.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+	// Cron expression scheduling the task, in the format used by the Nexus server (e.g. `0 0 1 * * ?` runs it daily at 1 AM).
+	// If not set, the task only runs when triggered manually.
+	// +optional
+	Cron string `json:"cron,omitempty"`
+	// Enabled defines if the task runs when scheduled. Defaults to `true`.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Properties of the task, specific to its type. For example: `blobstoreName` for `blobstore.compact` tasks or `repositoryName` for `repository.maven.rebuild-metadata` tasks.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
 }
 
 // NexusAutomaticUpdate defines configuration for automatic updates
@@ -261,9 +1467,18 @@ type NexusStatus struct {
 	// Gives more information about a failure status
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	Reason string `json:"reason,omitempty"`
-	// Route for external service access
+	// Route for external service access, the first of the exposed URLs
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
 	NexusRoute string `json:"nexusRoute,omitempty"`
+	// URLs where the Nexus server is exposed, one per host
+	// +listType=atomic
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	URLs []string `json:"urls,omitempty"`
+	// TLSCertificateReady is `true` once cert-manager has issued the certificate requested with `spec.networking.tls.certManager`
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +optional
+	TLSCertificateReady bool `json:"tlsCertificateReady,omitempty"`
 	// Conditions reached during an update
 	// +listType=atomic
 	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
@@ -281,6 +1496,127 @@ type OperationsStatus struct {
 	MavenCentralUpdated          bool   `json:"mavenCentralUpdated,omitempty"`
 	Reason                       string `json:"reason,omitempty"`
 	MavenPublicURL               string `json:"mavenPublicURL,omitempty"`
+	// OnboardingCompleted is `true` once the Operator has completed the onboarding wizard, see `spec.serverOperations.completeOnboarding`
+	OnboardingCompleted bool `json:"onboardingCompleted,omitempty"`
+	// OperatorUserPasswordRotatedAt is when the operator user password has been rotated for the last time, see `spec.serverOperations.credentialRotation`
+	// +optional
+	OperatorUserPasswordRotatedAt *metav1.Time `json:"operatorUserPasswordRotatedAt,omitempty"`
+	// Repositories describes the status of each repository declared in `spec.repositories`
+	// +optional
+	// +listType=atomic
+	Repositories []RepositoryStatus `json:"repositories,omitempty"`
+	// BlobStores describes the status of each blob store declared in `spec.blobStores`
+	// +optional
+	// +listType=atomic
+	BlobStores []BlobStoreStatus `json:"blobStores,omitempty"`
+	// CleanupPolicies describes the status of each cleanup policy declared in `spec.cleanupPolicies`
+	// +optional
+	// +listType=atomic
+	CleanupPolicies []CleanupPolicyStatus `json:"cleanupPolicies,omitempty"`
+	// Tasks describes the status of each task declared in `spec.tasks`
+	// +optional
+	// +listType=atomic
+	Tasks []TaskStatus `json:"tasks,omitempty"`
+	// LDAPServerConfigured is `true` once the LDAP server declared in `spec.security.ldap` matches the one configured in the Nexus server
+	LDAPServerConfigured bool `json:"ldapServerConfigured,omitempty"`
+	// LDAPRealmEnabled is `true` once the LDAP realm is active in the Nexus server
+	LDAPRealmEnabled bool `json:"ldapRealmEnabled,omitempty"`
+	// SAMLConfigured is `true` once the SAML configuration declared in `spec.security.saml` matches the one in the Nexus server
+	SAMLConfigured bool `json:"samlConfigured,omitempty"`
+	// SAMLRealmEnabled is `true` once the SAML realm is active in the Nexus server
+	SAMLRealmEnabled bool `json:"samlRealmEnabled,omitempty"`
+	// RUTAuthConfigured is `true` once the Remote User Token header declared in `spec.security.rutAuth` matches the one in the Nexus server
+	RUTAuthConfigured bool `json:"rutAuthConfigured,omitempty"`
+	// RUTAuthRealmEnabled is `true` once the Remote User Token realm is active in the Nexus server
+	RUTAuthRealmEnabled bool `json:"rutAuthRealmEnabled,omitempty"`
+	// ActiveRealms are the IDs of the realms active in the Nexus server, in order, once they've been enforced by the Operator
+	// +optional
+	// +listType=atomic
+	ActiveRealms []string `json:"activeRealms,omitempty"`
+	// AnonymousAccessConfigured is `true` once the anonymous access declared in `spec.security.anonymousAccess` matches the one in the Nexus server
+	AnonymousAccessConfigured bool `json:"anonymousAccessConfigured,omitempty"`
+}
+
+// CleanupPolicyStatus describes the status of a cleanup policy managed by the Operator in the Nexus server
+type CleanupPolicyStatus struct {
+	// Name of the cleanup policy in the Nexus server
+	Name string `json:"name"`
+	// Ready is `true` when the cleanup policy in the Nexus server matches its desired state
+	Ready bool `json:"ready,omitempty"`
+	// Reason gives more information about a cleanup policy that is not ready
+	Reason string `json:"reason,omitempty"`
+	// Created is `true` when the cleanup policy was created by the Operator.
+	// Only these cleanup policies are removed from the server once they're removed from `spec.cleanupPolicies`.
+	Created bool `json:"created,omitempty"`
+}
+
+// TaskStatus describes the status of a task managed by the Operator in the Nexus server
+type TaskStatus struct {
+	// Name of the task in the Nexus server
+	Name string `json:"name"`
+	// Type of the task
+	Type string `json:"type,omitempty"`
+	// ID of the task in the Nexus server
+	ID string `json:"id,omitempty"`
+	// Ready is `true` when the task in the Nexus server matches its desired state
+	Ready bool `json:"ready,omitempty"`
+	// Reason gives more information about a task that is not ready
+	Reason string `json:"reason,omitempty"`
+	// Created is `true` when the task was created by the Operator.
+	// Only these tasks are removed from the server once they're removed from `spec.tasks`.
+	Created bool `json:"created,omitempty"`
+	// ConfigurationHash is the hash of the task configuration last sent to the server, used to tell when the task must be updated
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+	// CurrentState of the task in the Nexus server, e.g. `WAITING` or `RUNNING`
+	CurrentState string `json:"currentState,omitempty"`
+	// LastRun is when the task last ran
+	// +optional
+	LastRun *metav1.Time `json:"lastRun,omitempty"`
+	// LastRunResult is the result of the last run, e.g. `OK` or `FAILED`
+	LastRunResult string `json:"lastRunResult,omitempty"`
+	// NextRun is when the task is scheduled to run next
+	// +optional
+	NextRun *metav1.Time `json:"nextRun,omitempty"`
+}
+
+// BlobStoreStatus describes the status of a blob store managed by the Operator in the Nexus server
+type BlobStoreStatus struct {
+	// Name of the blob store in the Nexus server
+	Name string `json:"name"`
+	// Type of the blob store
+	Type BlobStoreType `json:"type,omitempty"`
+	// Ready is `true` when the blob store in the Nexus server matches its desired state
+	Ready bool `json:"ready,omitempty"`
+	// Reason gives more information about a blob store that is not ready
+	Reason string `json:"reason,omitempty"`
+	// Created is `true` when the blob store was created by the Operator.
+	// Only these blob stores are removed from the server once they're removed from `spec.blobStores`.
+	Created bool `json:"created,omitempty"`
+	// BlobCount is the number of blobs in the blob store
+	BlobCount int64 `json:"blobCount,omitempty"`
+	// TotalSizeInBytes is the space used by the blob store
+	TotalSizeInBytes int64 `json:"totalSizeInBytes,omitempty"`
+	// AvailableSpaceInBytes is the space still available to the blob store
+	AvailableSpaceInBytes int64 `json:"availableSpaceInBytes,omitempty"`
+}
+
+// RepositoryStatus describes the status of a repository managed by the Operator in the Nexus server
+type RepositoryStatus struct {
+	// Name of the repository in the Nexus server
+	Name string `json:"name"`
+	// Format of the repository
+	Format RepositoryFormat `json:"format,omitempty"`
+	// Type of the repository
+	Type RepositoryType `json:"type,omitempty"`
+	// URL to reach the repository from within the cluster
+	URL string `json:"url,omitempty"`
+	// Ready is `true` when the repository in the Nexus server matches its desired state
+	Ready bool `json:"ready,omitempty"`
+	// Reason gives more information about a repository that is not ready
+	Reason string `json:"reason,omitempty"`
+	// Created is `true` when the repository was created by the Operator.
+	// Only these repositories are removed from the server once they're removed from `spec.repositories`.
+	Created bool `json:"created,omitempty"`
 }
 
 type NexusStatusType string
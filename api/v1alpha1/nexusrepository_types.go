@@ -0,0 +1,94 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NexusRepositorySpec defines the desired state of a repository managed in a Nexus server
+type NexusRepositorySpec struct {
+	// NexusName is the name of the Nexus CR, in the same namespace, whose server holds this repository
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Nexus Name"
+	// +kubebuilder:validation:MinLength=1
+	NexusName string `json:"nexusName"`
+
+	// Repository is the desired state of the repository in the Nexus server
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=false
+	Repository `json:",inline"`
+}
+
+const (
+	// NexusRepositoryConditionReady is `True` when the repository in the Nexus server matches its desired state
+	NexusRepositoryConditionReady = "Ready"
+
+	// NexusRepositoryFinalizer is the finalizer used to remove the repository from the Nexus server before deleting the CR
+	NexusRepositoryFinalizer = "nexusrepository.apps.m88i.io/finalizer"
+)
+
+// NexusRepositoryStatus defines the observed state of a NexusRepository
+type NexusRepositoryStatus struct {
+	// URL to reach the repository from within the cluster
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="URL"
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Created is `true` when the repository was created by the Operator.
+	// Only these repositories are removed from the server when the NexusRepository is deleted.
+	// +optional
+	Created bool `json:"created,omitempty"`
+	// ObservedGeneration is the last generation of this NexusRepository handled by the Operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the current state of the repository in the Nexus server
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Conditions"
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NexusRepository custom resource to manage a repository in a Nexus server deployed by the Operator
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=nexusrepositories,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Nexus",type="string",JSONPath=".spec.nexusName",description="Nexus instance holding the repository"
+// +kubebuilder:printcolumn:name="Format",type="string",JSONPath=".spec.format",description="Repository format"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type",description="Repository type"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the repository matches its desired state"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="Internal repository URL"
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Nexus Repository"
+type NexusRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NexusRepositorySpec   `json:"spec,omitempty"`
+	Status NexusRepositoryStatus `json:"status,omitempty"`
+}
+
+// NexusRepositoryList contains a list of NexusRepository
+// +kubebuilder:object:root=true
+type NexusRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NexusRepository `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NexusRepository{}, &NexusRepositoryList{})
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NexusRoleSpec defines the desired state of a role managed in a Nexus server
+type NexusRoleSpec struct {
+	// NexusName is the name of the Nexus CR, in the same namespace, whose server holds this role
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Nexus Name"
+	// +kubebuilder:validation:MinLength=1
+	NexusName string `json:"nexusName"`
+	// RoleID is the role identifier in the Nexus server. Defaults to the NexusRole name. Can't be changed once the role is created.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Role ID"
+	// +optional
+	RoleID string `json:"roleID,omitempty"`
+	// Name of the role displayed in the Nexus server. Defaults to the role ID.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Description of the role
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Privileges granted to this role, e.g. `nx-repository-view-maven2-*-read`
+	// +optional
+	// +listType=set
+	Privileges []string `json:"privileges,omitempty"`
+	// Roles contained in this role, whose privileges are also granted to it
+	// +optional
+	// +listType=set
+	Roles []string `json:"roles,omitempty"`
+}
+
+const (
+	// NexusRoleConditionReady is `True` when the role in the Nexus server matches its desired state
+	NexusRoleConditionReady = "Ready"
+
+	// NexusRoleFinalizer is the finalizer used to remove the role from the Nexus server before deleting the CR
+	NexusRoleFinalizer = "nexusrole.apps.m88i.io/finalizer"
+)
+
+// NexusRoleStatus defines the observed state of a NexusRole
+type NexusRoleStatus struct {
+	// Created is `true` when the role was created by the Operator.
+	// Only these roles are removed from the server when the NexusRole is deleted.
+	// +optional
+	Created bool `json:"created,omitempty"`
+	// ObservedGeneration is the last generation of this NexusRole handled by the Operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the current state of the role in the Nexus server
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Conditions"
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NexusRole custom resource to manage a role in a Nexus server deployed by the Operator
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=nexusroles,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Nexus",type="string",JSONPath=".spec.nexusName",description="Nexus instance holding the role"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the role matches its desired state"
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Nexus Role"
+type NexusRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NexusRoleSpec   `json:"spec,omitempty"`
+	Status NexusRoleStatus `json:"status,omitempty"`
+}
+
+// NexusRoleList contains a list of NexusRole
+// +kubebuilder:object:root=true
+type NexusRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NexusRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NexusRole{}, &NexusRoleList{})
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultUserSource is the source of the users stored in the Nexus server database
+	DefaultUserSource = "default"
+	// DefaultUserPasswordSecretKey is the key used to read/write the user password in the password Secret if none is given
+	DefaultUserPasswordSecretKey = "password"
+)
+
+// NexusUserSpec defines the desired state of a user managed in a Nexus server
+type NexusUserSpec struct {
+	// NexusName is the name of the Nexus CR, in the same namespace, whose server holds this user
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="Nexus Name"
+	// +kubebuilder:validation:MinLength=1
+	NexusName string `json:"nexusName"`
+	// UserID is the user identifier in the Nexus server. Defaults to the NexusUser name. Can't be changed once the user is created.
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.specDescriptors.displayName="User ID"
+	// +optional
+	UserID string `json:"userID,omitempty"`
+	// Source is the realm holding this user. Defaults to `default`, the Nexus server database.
+	// Users from other sources (e.g. LDAP) must already exist in the server, in this case only their roles are managed by the Operator.
+	// +optional
+	Source string `json:"source,omitempty"`
+	// FirstName of the user. Required if the source is `default`.
+	// +optional
+	FirstName string `json:"firstName,omitempty"`
+	// LastName of the user. Required if the source is `default`.
+	// +optional
+	LastName string `json:"lastName,omitempty"`
+	// Email of the user. Required if the source is `default`.
+	// +optional
+	Email string `json:"email,omitempty"`
+	// Disabled users can't log in the Nexus server. Defaults to `false`.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Roles are the IDs of the roles granted to this user
+	// +optional
+	// +listType=set
+	Roles []string `json:"roles,omitempty"`
+	// PasswordSecret references the Secret, in the same namespace, holding the user password. Required if the source is `default`.
+	// +optional
+	PasswordSecret *NexusUserPasswordSecret `json:"passwordSecret,omitempty"`
+}
+
+// NexusUserPasswordSecret references a Secret holding the password of a user
+type NexusUserPasswordSecret struct {
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key in the Secret holding the password. Defaults to `password`.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Generate a random password and write it back into the Secret if the Secret or the key don't exist. Defaults to `false`.
+	// +optional
+	Generate bool `json:"generate,omitempty"`
+}
+
+const (
+	// NexusUserConditionReady is `True` when the user in the Nexus server matches its desired state
+	NexusUserConditionReady = "Ready"
+
+	// NexusUserFinalizer is the finalizer used to remove the user from the Nexus server before deleting the CR
+	NexusUserFinalizer = "nexususer.apps.m88i.io/finalizer"
+)
+
+// NexusUserStatus defines the observed state of a NexusUser
+type NexusUserStatus struct {
+	// Created is `true` when the user was created by the Operator.
+	// Only these users are removed from the server when the NexusUser is deleted.
+	// +optional
+	Created bool `json:"created,omitempty"`
+	// ObservedGeneration is the last generation of this NexusUser handled by the Operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the current state of the user in the Nexus server
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors=true
+	// +operator-sdk:gen-csv:customresourcedefinitions.statusDescriptors.displayName="Conditions"
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NexusUser custom resource to manage a user in a Nexus server deployed by the Operator
+// +kubebuilder:object:root=true
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=nexususers,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Nexus",type="string",JSONPath=".spec.nexusName",description="Nexus instance holding the user"
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".spec.source",description="Realm holding the user"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Whether the user matches its desired state"
+// +operator-sdk:gen-csv:customresourcedefinitions.displayName="Nexus User"
+// +operator-sdk:gen-csv:customresourcedefinitions.resources="Secret,v1,\"A Kubernetes Secret\""
+type NexusUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NexusUserSpec   `json:"spec,omitempty"`
+	Status NexusUserStatus `json:"status,omitempty"`
+}
+
+// NexusUserList contains a list of NexusUser
+// +kubebuilder:object:root=true
+type NexusUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NexusUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NexusUser{}, &NexusUserList{})
+}
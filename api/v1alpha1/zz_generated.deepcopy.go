@@ -19,256 +19,1616 @@
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdminCredentialsSecret) DeepCopyInto(out *AdminCredentialsSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminCredentialsSecret.
+func (in *AdminCredentialsSecret) DeepCopy() *AdminCredentialsSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(AdminCredentialsSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnonymousAccess) DeepCopyInto(out *AnonymousAccess) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnonymousAccess.
+func (in *AnonymousAccess) DeepCopy() *AnonymousAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(AnonymousAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlobStore) DeepCopyInto(out *BlobStore) {
+	*out = *in
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileBlobStore)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BlobStore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SoftQuota != nil {
+		in, out := &in.SoftQuota, &out.SoftQuota
+		*out = new(BlobStoreSoftQuota)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobStore.
+func (in *BlobStore) DeepCopy() *BlobStore {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlobStoreSoftQuota) DeepCopyInto(out *BlobStoreSoftQuota) {
+	*out = *in
+	out.Limit = in.Limit.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobStoreSoftQuota.
+func (in *BlobStoreSoftQuota) DeepCopy() *BlobStoreSoftQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobStoreSoftQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlobStoreStatus) DeepCopyInto(out *BlobStoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobStoreStatus.
+func (in *BlobStoreStatus) DeepCopy() *BlobStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BlobStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerTLS) DeepCopyInto(out *CertManagerTLS) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerTLS.
+func (in *CertManagerTLS) DeepCopy() *CertManagerTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSource) DeepCopyInto(out *CertificateSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSource.
+func (in *CertificateSource) DeepCopy() *CertificateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupPolicy) DeepCopyInto(out *CleanupPolicy) {
+	*out = *in
+	if in.LastBlobUpdatedDays != nil {
+		in, out := &in.LastBlobUpdatedDays, &out.LastBlobUpdatedDays
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastDownloadedDays != nil {
+		in, out := &in.LastDownloadedDays, &out.LastDownloadedDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicy.
+func (in *CleanupPolicy) DeepCopy() *CleanupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupPolicyStatus) DeepCopyInto(out *CleanupPolicyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupPolicyStatus.
+func (in *CleanupPolicyStatus) DeepCopy() *CleanupPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommunityMavenProxies) DeepCopyInto(out *CommunityMavenProxies) {
+	*out = *in
+	if in.Proxies != nil {
+		in, out := &in.Proxies, &out.Proxies
+		*out = make([]CommunityMavenProxy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommunityMavenProxies.
+func (in *CommunityMavenProxies) DeepCopy() *CommunityMavenProxies {
+	if in == nil {
+		return nil
+	}
+	out := new(CommunityMavenProxies)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommunityMavenProxy) DeepCopyInto(out *CommunityMavenProxy) {
+	*out = *in
+	if in.ContentMaxAge != nil {
+		in, out := &in.ContentMaxAge, &out.ContentMaxAge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MetadataMaxAge != nil {
+		in, out := &in.MetadataMaxAge, &out.MetadataMaxAge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NegativeCacheTTL != nil {
+		in, out := &in.NegativeCacheTTL, &out.NegativeCacheTTL
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(RepositoryAuthentication)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommunityMavenProxy.
+func (in *CommunityMavenProxy) DeepCopy() *CommunityMavenProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(CommunityMavenProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotation) DeepCopyInto(out *CredentialRotation) {
+	*out = *in
+	out.Period = in.Period
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotation.
+func (in *CredentialRotation) DeepCopy() *CredentialRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsSecret) DeepCopyInto(out *CredentialsSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsSecret.
+func (in *CredentialsSecret) DeepCopy() *CredentialsSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileBlobStore) DeepCopyInto(out *FileBlobStore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileBlobStore.
+func (in *FileBlobStore) DeepCopy() *FileBlobStore {
+	if in == nil {
+		return nil
+	}
+	out := new(FileBlobStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProxy) DeepCopyInto(out *HTTPProxy) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(ProxyServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPS != nil {
+		in, out := &in.HTTPS, &out.HTTPS
+		*out = new(ProxyServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NonProxyHosts != nil {
+		in, out := &in.NonProxyHosts, &out.NonProxyHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProxy.
+func (in *HTTPProxy) DeepCopy() *HTTPProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConnection) DeepCopyInto(out *LDAPConnection) {
+	*out = *in
+	if in.BindCredentialsSecret != nil {
+		in, out := &in.BindCredentialsSecret, &out.BindCredentialsSecret
+		*out = new(CredentialsSecret)
+		**out = **in
+	}
+	if in.CACertificate != nil {
+		in, out := &in.CACertificate, &out.CACertificate
+		*out = new(CertificateSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionTimeoutSeconds != nil {
+		in, out := &in.ConnectionTimeoutSeconds, &out.ConnectionTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryDelaySeconds != nil {
+		in, out := &in.RetryDelaySeconds, &out.RetryDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxIncidentsCount != nil {
+		in, out := &in.MaxIncidentsCount, &out.MaxIncidentsCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConnection.
+func (in *LDAPConnection) DeepCopy() *LDAPConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPGroupMapping) DeepCopyInto(out *LDAPGroupMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPGroupMapping.
+func (in *LDAPGroupMapping) DeepCopy() *LDAPGroupMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPGroupMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPServer) DeepCopyInto(out *LDAPServer) {
+	*out = *in
+	in.Connection.DeepCopyInto(&out.Connection)
+	out.UserMapping = in.UserMapping
+	if in.GroupMapping != nil {
+		in, out := &in.GroupMapping, &out.GroupMapping
+		*out = new(LDAPGroupMapping)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPServer.
+func (in *LDAPServer) DeepCopy() *LDAPServer {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPUserMapping) DeepCopyInto(out *LDAPUserMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPUserMapping.
+func (in *LDAPUserMapping) DeepCopy() *LDAPUserMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPUserMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Nexus) DeepCopyInto(out *Nexus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nexus.
+func (in *Nexus) DeepCopy() *Nexus {
+	if in == nil {
+		return nil
+	}
+	out := new(Nexus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Nexus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusAutomaticUpdate) DeepCopyInto(out *NexusAutomaticUpdate) {
+	*out = *in
+	if in.MinorVersion != nil {
+		in, out := &in.MinorVersion, &out.MinorVersion
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusAutomaticUpdate.
+func (in *NexusAutomaticUpdate) DeepCopy() *NexusAutomaticUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusAutomaticUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusConfiguration) DeepCopyInto(out *NexusConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusConfiguration.
+func (in *NexusConfiguration) DeepCopy() *NexusConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusJVM) DeepCopyInto(out *NexusJVM) {
+	*out = *in
+	if in.HeapSize != nil {
+		in, out := &in.HeapSize, &out.HeapSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.HeapPercentage != nil {
+		in, out := &in.HeapPercentage, &out.HeapPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DirectMemorySize != nil {
+		in, out := &in.DirectMemorySize, &out.DirectMemorySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DirectMemoryPercentage != nil {
+		in, out := &in.DirectMemoryPercentage, &out.DirectMemoryPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusJVM.
+func (in *NexusJVM) DeepCopy() *NexusJVM {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusJVM)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusList) DeepCopyInto(out *NexusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Nexus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusList.
+func (in *NexusList) DeepCopy() *NexusList {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusNetworking) DeepCopyInto(out *NexusNetworking) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalHosts != nil {
+		in, out := &in.AdditionalHosts, &out.AdditionalHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.TLS.DeepCopyInto(&out.TLS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusNetworking.
+func (in *NexusNetworking) DeepCopy() *NexusNetworking {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusNetworking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusNetworkingTLS) DeepCopyInto(out *NexusNetworkingTLS) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerTLS)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusNetworkingTLS.
+func (in *NexusNetworkingTLS) DeepCopy() *NexusNetworkingTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusNetworkingTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusPersistence) DeepCopyInto(out *NexusPersistence) {
+	*out = *in
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]NexusVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusPersistence.
+func (in *NexusPersistence) DeepCopy() *NexusPersistence {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusPersistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusProbe) DeepCopyInto(out *NexusProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusProbe.
+func (in *NexusProbe) DeepCopy() *NexusProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRepository) DeepCopyInto(out *NexusRepository) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRepository.
+func (in *NexusRepository) DeepCopy() *NexusRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusRepository) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRepositoryList) DeepCopyInto(out *NexusRepositoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NexusRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRepositoryList.
+func (in *NexusRepositoryList) DeepCopy() *NexusRepositoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRepositoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusRepositoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRepositorySpec) DeepCopyInto(out *NexusRepositorySpec) {
+	*out = *in
+	in.Repository.DeepCopyInto(&out.Repository)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRepositorySpec.
+func (in *NexusRepositorySpec) DeepCopy() *NexusRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRepositoryStatus) DeepCopyInto(out *NexusRepositoryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRepositoryStatus.
+func (in *NexusRepositoryStatus) DeepCopy() *NexusRepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRole) DeepCopyInto(out *NexusRole) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRole.
+func (in *NexusRole) DeepCopy() *NexusRole {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusRole) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRoleList) DeepCopyInto(out *NexusRoleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NexusRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRoleList.
+func (in *NexusRoleList) DeepCopy() *NexusRoleList {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRoleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusRoleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRoleSpec) DeepCopyInto(out *NexusRoleSpec) {
+	*out = *in
+	if in.Privileges != nil {
+		in, out := &in.Privileges, &out.Privileges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRoleSpec.
+func (in *NexusRoleSpec) DeepCopy() *NexusRoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusRoleStatus) DeepCopyInto(out *NexusRoleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusRoleStatus.
+func (in *NexusRoleStatus) DeepCopy() *NexusRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusScheduling) DeepCopyInto(out *NexusScheduling) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusScheduling.
+func (in *NexusScheduling) DeepCopy() *NexusScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusSecurity) DeepCopyInto(out *NexusSecurity) {
+	*out = *in
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPServer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(SAML)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RUTAuth != nil {
+		in, out := &in.RUTAuth, &out.RUTAuth
+		*out = new(RUTAuth)
+		**out = **in
+	}
+	if in.OAuth2Proxy != nil {
+		in, out := &in.OAuth2Proxy, &out.OAuth2Proxy
+		*out = new(OAuth2Proxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AnonymousAccess != nil {
+		in, out := &in.AnonymousAccess, &out.AnonymousAccess
+		*out = new(AnonymousAccess)
+		**out = **in
+	}
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusSecurity.
+func (in *NexusSecurity) DeepCopy() *NexusSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusSpec) DeepCopyInto(out *NexusSpec) {
+	*out = *in
+	in.AutomaticUpdate.DeepCopyInto(&out.AutomaticUpdate)
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.JVM.DeepCopyInto(&out.JVM)
+	in.Persistence.DeepCopyInto(&out.Persistence)
+	in.Networking.DeepCopyInto(&out.Networking)
+	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ResourcePatch, len(*in))
+		copy(*out, *in)
+	}
+	in.Security.DeepCopyInto(&out.Security)
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(NexusProbe)
+		**out = **in
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(NexusProbe)
+		**out = **in
+	}
+	in.ServerOperations.DeepCopyInto(&out.ServerOperations)
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Configuration = in.Configuration
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]Repository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BlobStores != nil {
+		in, out := &in.BlobStores, &out.BlobStores
+		*out = make([]BlobStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CleanupPolicies != nil {
+		in, out := &in.CleanupPolicies, &out.CleanupPolicies
+		*out = make([]CleanupPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]Task, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusSpec.
+func (in *NexusSpec) DeepCopy() *NexusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusStatus) DeepCopyInto(out *NexusStatus) {
+	*out = *in
+	in.DeploymentStatus.DeepCopyInto(&out.DeploymentStatus)
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdateConditions != nil {
+		in, out := &in.UpdateConditions, &out.UpdateConditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ServerOperationsStatus.DeepCopyInto(&out.ServerOperationsStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusStatus.
+func (in *NexusStatus) DeepCopy() *NexusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusUser) DeepCopyInto(out *NexusUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusUser.
+func (in *NexusUser) DeepCopy() *NexusUser {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusUserList) DeepCopyInto(out *NexusUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NexusUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusUserList.
+func (in *NexusUserList) DeepCopy() *NexusUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NexusUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusUserPasswordSecret) DeepCopyInto(out *NexusUserPasswordSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusUserPasswordSecret.
+func (in *NexusUserPasswordSecret) DeepCopy() *NexusUserPasswordSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusUserPasswordSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusUserSpec) DeepCopyInto(out *NexusUserSpec) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(NexusUserPasswordSecret)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusUserSpec.
+func (in *NexusUserSpec) DeepCopy() *NexusUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusUserStatus) DeepCopyInto(out *NexusUserStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusUserStatus.
+func (in *NexusUserStatus) DeepCopy() *NexusUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NexusVolume) DeepCopyInto(out *NexusVolume) {
+	*out = *in
+	in.Volume.DeepCopyInto(&out.Volume)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusVolume.
+func (in *NexusVolume) DeepCopy() *NexusVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(NexusVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2Proxy) DeepCopyInto(out *OAuth2Proxy) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.EmailDomains != nil {
+		in, out := &in.EmailDomains, &out.EmailDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2Proxy.
+func (in *OAuth2Proxy) DeepCopy() *OAuth2Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationsStatus) DeepCopyInto(out *OperationsStatus) {
+	*out = *in
+	if in.OperatorUserPasswordRotatedAt != nil {
+		in, out := &in.OperatorUserPasswordRotatedAt, &out.OperatorUserPasswordRotatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]RepositoryStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlobStores != nil {
+		in, out := &in.BlobStores, &out.BlobStores
+		*out = make([]BlobStoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.CleanupPolicies != nil {
+		in, out := &in.CleanupPolicies, &out.CleanupPolicies
+		*out = make([]CleanupPolicyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tasks != nil {
+		in, out := &in.Tasks, &out.Tasks
+		*out = make([]TaskStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveRealms != nil {
+		in, out := &in.ActiveRealms, &out.ActiveRealms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Nexus.
-func (in *Nexus) DeepCopy() *Nexus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationsStatus.
+func (in *OperationsStatus) DeepCopy() *OperationsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Nexus)
+	out := new(OperationsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Nexus) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyServer) DeepCopyInto(out *ProxyServer) {
+	*out = *in
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(CredentialsSecret)
+		**out = **in
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyServer.
+func (in *ProxyServer) DeepCopy() *ProxyServer {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyServer)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusAutomaticUpdate) DeepCopyInto(out *NexusAutomaticUpdate) {
+func (in *RUTAuth) DeepCopyInto(out *RUTAuth) {
 	*out = *in
-	if in.MinorVersion != nil {
-		in, out := &in.MinorVersion, &out.MinorVersion
-		*out = new(int)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RUTAuth.
+func (in *RUTAuth) DeepCopy() *RUTAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(RUTAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Repository) DeepCopyInto(out *Repository) {
+	*out = *in
+	if in.Online != nil {
+		in, out := &in.Online, &out.Online
+		*out = new(bool)
+		**out = **in
+	}
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(RepositoryProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(RepositoryGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Maven != nil {
+		in, out := &in.Maven, &out.Maven
+		*out = new(RepositoryMaven)
 		**out = **in
 	}
+	if in.Docker != nil {
+		in, out := &in.Docker, &out.Docker
+		*out = new(RepositoryDocker)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(RepositoryCleanup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPClient != nil {
+		in, out := &in.HTTPClient, &out.HTTPClient
+		*out = new(RepositoryHTTPClient)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusAutomaticUpdate.
-func (in *NexusAutomaticUpdate) DeepCopy() *NexusAutomaticUpdate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Repository.
+func (in *Repository) DeepCopy() *Repository {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusAutomaticUpdate)
+	out := new(Repository)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusList) DeepCopyInto(out *NexusList) {
+func (in *RepositoryAuthentication) DeepCopyInto(out *RepositoryAuthentication) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Nexus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.CredentialsSecret = in.CredentialsSecret
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusList.
-func (in *NexusList) DeepCopy() *NexusList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryAuthentication.
+func (in *RepositoryAuthentication) DeepCopy() *RepositoryAuthentication {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusList)
+	out := new(RepositoryAuthentication)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NexusList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryCleanup) DeepCopyInto(out *RepositoryCleanup) {
+	*out = *in
+	if in.PolicyNames != nil {
+		in, out := &in.PolicyNames, &out.PolicyNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryCleanup.
+func (in *RepositoryCleanup) DeepCopy() *RepositoryCleanup {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryCleanup)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusNetworking) DeepCopyInto(out *NexusNetworking) {
+func (in *RepositoryDocker) DeepCopyInto(out *RepositoryDocker) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.ForceBasicAuth != nil {
+		in, out := &in.ForceBasicAuth, &out.ForceBasicAuth
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(int32)
+		**out = **in
 	}
-	out.TLS = in.TLS
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusNetworking.
-func (in *NexusNetworking) DeepCopy() *NexusNetworking {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryDocker.
+func (in *RepositoryDocker) DeepCopy() *RepositoryDocker {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusNetworking)
+	out := new(RepositoryDocker)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusNetworkingTLS) DeepCopyInto(out *NexusNetworkingTLS) {
+func (in *RepositoryGroup) DeepCopyInto(out *RepositoryGroup) {
 	*out = *in
+	if in.MemberNames != nil {
+		in, out := &in.MemberNames, &out.MemberNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusNetworkingTLS.
-func (in *NexusNetworkingTLS) DeepCopy() *NexusNetworkingTLS {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryGroup.
+func (in *RepositoryGroup) DeepCopy() *RepositoryGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusNetworkingTLS)
+	out := new(RepositoryGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusPersistence) DeepCopyInto(out *NexusPersistence) {
+func (in *RepositoryHTTPClient) DeepCopyInto(out *RepositoryHTTPClient) {
 	*out = *in
-	if in.ExtraVolumes != nil {
-		in, out := &in.ExtraVolumes, &out.ExtraVolumes
-		*out = make([]NexusVolume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(RepositoryAuthentication)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusPersistence.
-func (in *NexusPersistence) DeepCopy() *NexusPersistence {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryHTTPClient.
+func (in *RepositoryHTTPClient) DeepCopy() *RepositoryHTTPClient {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusPersistence)
+	out := new(RepositoryHTTPClient)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusProbe) DeepCopyInto(out *NexusProbe) {
+func (in *RepositoryMaven) DeepCopyInto(out *RepositoryMaven) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusProbe.
-func (in *NexusProbe) DeepCopy() *NexusProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryMaven.
+func (in *RepositoryMaven) DeepCopy() *RepositoryMaven {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusProbe)
+	out := new(RepositoryMaven)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusSpec) DeepCopyInto(out *NexusSpec) {
+func (in *RepositoryProxy) DeepCopyInto(out *RepositoryProxy) {
 	*out = *in
-	in.AutomaticUpdate.DeepCopyInto(&out.AutomaticUpdate)
-	in.Resources.DeepCopyInto(&out.Resources)
-	in.Persistence.DeepCopyInto(&out.Persistence)
-	in.Networking.DeepCopyInto(&out.Networking)
-	if in.LivenessProbe != nil {
-		in, out := &in.LivenessProbe, &out.LivenessProbe
-		*out = new(NexusProbe)
+	if in.ContentMaxAge != nil {
+		in, out := &in.ContentMaxAge, &out.ContentMaxAge
+		*out = new(int32)
 		**out = **in
 	}
-	if in.ReadinessProbe != nil {
-		in, out := &in.ReadinessProbe, &out.ReadinessProbe
-		*out = new(NexusProbe)
+	if in.MetadataMaxAge != nil {
+		in, out := &in.MetadataMaxAge, &out.MetadataMaxAge
+		*out = new(int32)
 		**out = **in
 	}
-	out.ServerOperations = in.ServerOperations
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.NegativeCacheEnabled != nil {
+		in, out := &in.NegativeCacheEnabled, &out.NegativeCacheEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NegativeCacheTTL != nil {
+		in, out := &in.NegativeCacheTTL, &out.NegativeCacheTTL
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusSpec.
-func (in *NexusSpec) DeepCopy() *NexusSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryProxy.
+func (in *RepositoryProxy) DeepCopy() *RepositoryProxy {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusSpec)
+	out := new(RepositoryProxy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusStatus) DeepCopyInto(out *NexusStatus) {
+func (in *RepositoryStatus) DeepCopyInto(out *RepositoryStatus) {
 	*out = *in
-	in.DeploymentStatus.DeepCopyInto(&out.DeploymentStatus)
-	if in.UpdateConditions != nil {
-		in, out := &in.UpdateConditions, &out.UpdateConditions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStatus.
+func (in *RepositoryStatus) DeepCopy() *RepositoryStatus {
+	if in == nil {
+		return nil
 	}
-	out.ServerOperationsStatus = in.ServerOperationsStatus
+	out := new(RepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusStatus.
-func (in *NexusStatus) DeepCopy() *NexusStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryStorage) DeepCopyInto(out *RepositoryStorage) {
+	*out = *in
+	if in.StrictContentTypeValidation != nil {
+		in, out := &in.StrictContentTypeValidation, &out.StrictContentTypeValidation
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryStorage.
+func (in *RepositoryStorage) DeepCopy() *RepositoryStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusStatus)
+	out := new(RepositoryStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NexusVolume) DeepCopyInto(out *NexusVolume) {
+func (in *ResourcePatch) DeepCopyInto(out *ResourcePatch) {
 	*out = *in
-	in.Volume.DeepCopyInto(&out.Volume)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NexusVolume.
-func (in *NexusVolume) DeepCopy() *NexusVolume {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePatch.
+func (in *ResourcePatch) DeepCopy() *ResourcePatch {
 	if in == nil {
 		return nil
 	}
-	out := new(NexusVolume)
+	out := new(ResourcePatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperationsStatus) DeepCopyInto(out *OperationsStatus) {
+func (in *S3BlobStore) DeepCopyInto(out *S3BlobStore) {
 	*out = *in
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(S3CredentialsSecret)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationsStatus.
-func (in *OperationsStatus) DeepCopy() *OperationsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BlobStore.
+func (in *S3BlobStore) DeepCopy() *S3BlobStore {
 	if in == nil {
 		return nil
 	}
-	out := new(OperationsStatus)
+	out := new(S3BlobStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3CredentialsSecret) DeepCopyInto(out *S3CredentialsSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3CredentialsSecret.
+func (in *S3CredentialsSecret) DeepCopy() *S3CredentialsSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(S3CredentialsSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAML) DeepCopyInto(out *SAML) {
+	*out = *in
+	in.IdPMetadata.DeepCopyInto(&out.IdPMetadata)
+	if in.ValidateResponseSignature != nil {
+		in, out := &in.ValidateResponseSignature, &out.ValidateResponseSignature
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ValidateAssertionSignature != nil {
+		in, out := &in.ValidateAssertionSignature, &out.ValidateAssertionSignature
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAML.
+func (in *SAML) DeepCopy() *SAML {
+	if in == nil {
+		return nil
+	}
+	out := new(SAML)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -276,6 +1636,26 @@ func (in *OperationsStatus) DeepCopy() *OperationsStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerOperationsOpts) DeepCopyInto(out *ServerOperationsOpts) {
 	*out = *in
+	if in.CommunityMavenProxies != nil {
+		in, out := &in.CommunityMavenProxies, &out.CommunityMavenProxies
+		*out = new(CommunityMavenProxies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminCredentialsSecret != nil {
+		in, out := &in.AdminCredentialsSecret, &out.AdminCredentialsSecret
+		*out = new(AdminCredentialsSecret)
+		**out = **in
+	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotation)
+		**out = **in
+	}
+	if in.HTTPProxy != nil {
+		in, out := &in.HTTPProxy, &out.HTTPProxy
+		*out = new(HTTPProxy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerOperationsOpts.
@@ -287,3 +1667,53 @@ func (in *ServerOperationsOpts) DeepCopy() *ServerOperationsOpts {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Task) DeepCopyInto(out *Task) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Task.
+func (in *Task) DeepCopy() *Task {
+	if in == nil {
+		return nil
+	}
+	out := new(Task)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskStatus) DeepCopyInto(out *TaskStatus) {
+	*out = *in
+	if in.LastRun != nil {
+		in, out := &in.LastRun, &out.LastRun
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRun != nil {
+		in, out := &in.NextRun, &out.NextRun
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStatus.
+func (in *TaskStatus) DeepCopy() *TaskStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskStatus)
+	in.DeepCopyInto(out)
+	return out
+}
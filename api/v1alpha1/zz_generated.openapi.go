@@ -28,8 +28,11 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 	return map[string]common.OpenAPIDefinition{
 		"./api/v1alpha1.NexusPersistence": schema__api_v1alpha1_NexusPersistence(ref),
 		"./api/v1alpha1.NexusProbe":       schema__api_v1alpha1_NexusProbe(ref),
+		"./api/v1alpha1.NexusRepository":  schema__api_v1alpha1_NexusRepository(ref),
+		"./api/v1alpha1.NexusRole":        schema__api_v1alpha1_NexusRole(ref),
 		"./api/v1alpha1.NexusSpec":        schema__api_v1alpha1_NexusSpec(ref),
 		"./api/v1alpha1.NexusStatus":      schema__api_v1alpha1_NexusStatus(ref),
+		"./api/v1alpha1.NexusUser":        schema__api_v1alpha1_NexusUser(ref),
 	}
 }
 
@@ -136,6 +139,94 @@ func schema__api_v1alpha1_NexusProbe(ref common.ReferenceCallback) common.OpenAP
 	}
 }
 
+func schema__api_v1alpha1_NexusRepository(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NexusRepository custom resource to manage a repository in a Nexus server deployed by the Operator",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./api/v1alpha1.NexusRepositorySpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./api/v1alpha1.NexusRepositoryStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./api/v1alpha1.NexusRepositorySpec", "./api/v1alpha1.NexusRepositoryStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema__api_v1alpha1_NexusRole(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NexusRole custom resource to manage a role in a Nexus server deployed by the Operator",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./api/v1alpha1.NexusRoleSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./api/v1alpha1.NexusRoleStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./api/v1alpha1.NexusRoleSpec", "./api/v1alpha1.NexusRoleStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
 func schema__api_v1alpha1_NexusSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -176,6 +267,12 @@ func schema__api_v1alpha1_NexusSpec(ref common.ReferenceCallback) common.OpenAPI
 							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
 						},
 					},
+					"jvm": {
+						SchemaProps: spec.SchemaProps{
+							Description: "JVM describes the tuning of the Java virtual machine running the Nexus server, such as its memory sizing",
+							Ref:         ref("./api/v1alpha1.NexusJVM"),
+						},
+					},
 					"persistence": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Persistence definition",
@@ -191,7 +288,7 @@ func schema__api_v1alpha1_NexusSpec(ref common.ReferenceCallback) common.OpenAPI
 					},
 					"generateRandomAdminPassword": {
 						SchemaProps: spec.SchemaProps{
-							Description: "GenerateRandomAdminPassword enables the random password generation. Defaults to `false`: the default password for a newly created instance is 'admin123', which should be changed in the first login. If set to `true`, you must use the automatically generated 'admin' password, stored in the container's file system at `/nexus-data/admin.password`. The operator uses the default credentials to create a user for itself to create default repositories. If set to `true`, the repositories won't be created since the operator won't fetch for the random password.",
+							Description: "GenerateRandomAdminPassword enables the random password generation. Defaults to `false`: the default password for a newly created instance is 'admin123', which should be changed in the first login. If set to `true`, you must use the automatically generated 'admin' password, stored in the container's file system at `/nexus-data/admin.password`. The operator uses the default credentials to create a user for itself to create default repositories. If set to `true`, the operator reads the generated password from the server pod and stores it in the instance Secret to perform the server operations, unless `spec.serverOperations.adminCredentialsSecret` is set.",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
@@ -209,6 +306,78 @@ func schema__api_v1alpha1_NexusSpec(ref common.ReferenceCallback) common.OpenAPI
 							Format:      "",
 						},
 					},
+					"scheduling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Scheduling describes where the Nexus pods may run, such as dedicated nodes",
+							Ref:         ref("./api/v1alpha1.NexusScheduling"),
+						},
+					},
+					"sidecars": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Sidecars are additional containers run in the Nexus pods next to the Nexus server, such as a log shipper or a backup agent. Their names must differ from the ones of the containers managed by the Operator: `nexus-server` and `oauth2-proxy`.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Container"),
+									},
+								},
+							},
+						},
+					},
+					"initContainers": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "InitContainers are run in order in the Nexus pods before the Nexus server starts, e.g. to fix the permissions of the data volume",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.Container"),
+									},
+								},
+							},
+						},
+					},
+					"patches": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Patches are applied to the resources generated by the Operator before they're created or updated in the cluster, e.g. to add a sidecar container to the Deployment or a field not covered by this spec. Fields changed by the patches are kept in sync with the deployed resources.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./api/v1alpha1.ResourcePatch"),
+									},
+								},
+							},
+						},
+					},
+					"security": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Security describes how the users authenticate against the Nexus server, such as the LDAP server to use",
+							Ref:         ref("./api/v1alpha1.NexusSecurity"),
+						},
+					},
 					"livenessProbe": {
 						SchemaProps: spec.SchemaProps{
 							Description: "LivenessProbe describes how the Nexus container liveness probe should work",
@@ -242,12 +411,102 @@ func schema__api_v1alpha1_NexusSpec(ref common.ReferenceCallback) common.OpenAPI
 							},
 						},
 					},
+					"configuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configuration describes the common settings of the nexus.properties file. They take precedence over the same keys in `properties`.",
+							Ref:         ref("./api/v1alpha1.NexusConfiguration"),
+						},
+					},
+					"repositories": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Repositories describes the repositories managed by the Operator in the Nexus server. Repositories created from this list are removed from the server once they're removed from here.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./api/v1alpha1.Repository"),
+									},
+								},
+							},
+						},
+					},
+					"blobStores": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "BlobStores describes the blob stores managed by the Operator in the Nexus server. Blob stores created from this list are removed from the server once they're removed from here.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./api/v1alpha1.BlobStore"),
+									},
+								},
+							},
+						},
+					},
+					"cleanupPolicies": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "CleanupPolicies describes the cleanup policies managed by the Operator in the Nexus server, referenced by the repositories in `cleanup.policyNames`. Cleanup policies created from this list are removed from the server once they're removed from here.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./api/v1alpha1.CleanupPolicy"),
+									},
+								},
+							},
+						},
+					},
+					"tasks": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Tasks describes the scheduled tasks managed by the Operator in the Nexus server. Tasks created from this list are removed from the server once they're removed from here.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./api/v1alpha1.Task"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"replicas", "persistence", "useRedHatImage"},
 			},
 		},
 		Dependencies: []string{
-			"./api/v1alpha1.NexusAutomaticUpdate", "./api/v1alpha1.NexusNetworking", "./api/v1alpha1.NexusPersistence", "./api/v1alpha1.NexusProbe", "./api/v1alpha1.ServerOperationsOpts", "k8s.io/api/core/v1.ResourceRequirements"},
+			"./api/v1alpha1.BlobStore", "./api/v1alpha1.CleanupPolicy", "./api/v1alpha1.NexusAutomaticUpdate", "./api/v1alpha1.NexusConfiguration", "./api/v1alpha1.NexusJVM", "./api/v1alpha1.NexusNetworking", "./api/v1alpha1.NexusPersistence", "./api/v1alpha1.NexusProbe", "./api/v1alpha1.NexusScheduling", "./api/v1alpha1.NexusSecurity", "./api/v1alpha1.Repository", "./api/v1alpha1.ResourcePatch", "./api/v1alpha1.ServerOperationsOpts", "./api/v1alpha1.Task", "k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.ResourceRequirements"},
 	}
 }
 
@@ -280,11 +539,37 @@ func schema__api_v1alpha1_NexusStatus(ref common.ReferenceCallback) common.OpenA
 					},
 					"nexusRoute": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Route for external service access",
+							Description: "Route for external service access, the first of the exposed URLs",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"urls": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "URLs where the Nexus server is exposed, one per host",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"tlsCertificateReady": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSCertificateReady is `true` once cert-manager has issued the certificate requested with `spec.networking.tls.certManager`",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"updateConditions": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
@@ -317,3 +602,47 @@ func schema__api_v1alpha1_NexusStatus(ref common.ReferenceCallback) common.OpenA
 			"./api/v1alpha1.OperationsStatus", "k8s.io/api/apps/v1.DeploymentStatus"},
 	}
 }
+
+func schema__api_v1alpha1_NexusUser(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NexusUser custom resource to manage a user in a Nexus server deployed by the Operator",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./api/v1alpha1.NexusUserSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("./api/v1alpha1.NexusUserStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./api/v1alpha1.NexusUserSpec", "./api/v1alpha1.NexusUserStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
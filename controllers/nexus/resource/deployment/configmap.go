@@ -15,6 +15,9 @@
 package deployment
 
 import (
+	"strconv"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/m88i/nexus-operator/pkg/util"
@@ -23,13 +26,70 @@ import (
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
 )
 
-const nexusPropertiesFilename = "nexus.properties"
+const (
+	nexusPropertiesFilename = "nexus.properties"
+
+	httpsPortProperty = "application-port-ssl"
+	sslDirProperty    = "ssl.etc"
+	nexusArgsProperty = "nexus-args"
+	// defaultNexusArgs are the Jetty configuration files loaded by default, see nexus-default.properties in the Nexus server
+	defaultNexusArgs = "${jetty.etc}/jetty.xml,${jetty.etc}/jetty-http.xml,${jetty.etc}/jetty-requestlog.xml"
+	jettyHTTPSConfig = "${jetty.etc}/jetty-https.xml"
+)
 
 func newConfigMap(nexus *v1alpha1.Nexus) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: meta.DefaultObjectMeta(nexus),
 		Data: map[string]string{
-			nexusPropertiesFilename: util.FromMapToJavaProperties(nexus.Spec.Properties),
+			nexusPropertiesFilename: util.FromMapToJavaProperties(nexusProperties(nexus)),
 		},
 	}
 }
+
+// nexusProperties returns the properties set in the Nexus CR along with the ones derived from its other fields, which take precedence
+func nexusProperties(nexus *v1alpha1.Nexus) map[string]string {
+	properties := make(map[string]string, len(nexus.Spec.Properties)+2)
+	for key, value := range nexus.Spec.Properties {
+		properties[key] = value
+	}
+	if contextPath := nexus.ContextPath(); len(contextPath) > 0 {
+		properties[v1alpha1.ContextPathProperty] = contextPath
+	} else {
+		delete(properties, v1alpha1.ContextPathProperty)
+	}
+	// the default port is left to the image, so that the file, and with it the pods, don't change when upgrading the Operator
+	if port := nexus.HTTPPort(); port != v1alpha1.DefaultNexusHTTPPort {
+		properties[v1alpha1.HTTPPortProperty] = strconv.Itoa(int(port))
+	} else {
+		delete(properties, v1alpha1.HTTPPortProperty)
+	}
+	if nexus.ScriptCreationAllowed() {
+		properties[v1alpha1.AllowScriptCreationProperty] = strconv.FormatBool(true)
+	} else {
+		delete(properties, v1alpha1.AllowScriptCreationProperty)
+	}
+	if nexus.Datastore() == v1alpha1.H2Datastore {
+		properties[v1alpha1.DatastoreEnabledProperty] = strconv.FormatBool(true)
+	} else {
+		delete(properties, v1alpha1.DatastoreEnabledProperty)
+	}
+	addHTTPSProperties(nexus, properties)
+	return properties
+}
+
+// addHTTPSProperties enables the HTTPS connector of the Nexus server, keeping the Jetty configuration files set in the Nexus CR if any
+func addHTTPSProperties(nexus *v1alpha1.Nexus, properties map[string]string) {
+	if !ServerTLSEnabled(nexus) {
+		return
+	}
+	properties[httpsPortProperty] = strconv.Itoa(nexusContainerHTTPSPort)
+	properties[sslDirProperty] = nexusSSLDir
+	nexusArgs := properties[nexusArgsProperty]
+	if len(nexusArgs) == 0 {
+		nexusArgs = defaultNexusArgs
+	}
+	if !strings.Contains(nexusArgs, jettyHTTPSConfig) {
+		nexusArgs = nexusArgs + "," + jettyHTTPSConfig
+	}
+	properties[nexusArgsProperty] = nexusArgs
+}
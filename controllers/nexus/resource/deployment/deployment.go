@@ -16,7 +16,6 @@ package deployment
 
 import (
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -35,31 +34,40 @@ import (
 const (
 	// JvmArgsEnvKey is they env var for JVM args
 	JvmArgsEnvKey = "INSTALL4J_ADD_VM_PARAMS"
-	/*
-		1. Xms
-		2. Xmx
-		3. MaxDirectMemorySize
-	*/
+
 	jvmArgsXms                 = "-Xms"
 	jvmArgsXmx                 = "-Xmx"
 	jvmArgsMaxMemSize          = "-XX:MaxDirectMemorySize"
 	jvmArgsUserRoot            = "-Djava.util.prefs.userRoot"
 	jvmArgRandomPassword       = "-Dnexus.security.randompassword"
+	jvmArgHeapDumpOnOOM        = "-XX:+HeapDumpOnOutOfMemoryError"
+	jvmArgHeapDumpPath         = "-XX:HeapDumpPath"
+	javaPrefsUserRoot          = "${NEXUS_DATA}/javaprefs"
 	heapSizeDefault            = "1718m"
 	maxDirectMemorySizeDefault = "2148m"
-	nexusDataDir               = "/nexus-data"
+	mebibyte                   = 1024 * 1024
+	statusPath                 = "/service/rest/v1/status"
+	// previous versions of the Operator gave the heap 80% of the memory limit in megabytes and the direct memory all of it
+	legacyHeapRatio = 0.8
 	// see: https://help.sonatype.com/repomanager3/installation/configuring-the-runtime-environment
-	nexusConfigFileMountPath = nexusDataDir + "/etc/" + nexusPropertiesFilename
-	nexusContainerName       = "nexus-server"
+	nexusConfigFileMountPath = v1alpha1.NexusDataDir + "/etc/" + nexusPropertiesFilename
+	// see: https://help.sonatype.com/repomanager3/system-configuration/configuring-ssl#ConfiguringSSL-ServingSSLDirectly
+	nexusSSLDir = v1alpha1.NexusDataDir + "/etc/ssl"
+	// KeystoreFilename is the name of the keystore file read by the Nexus server to serve HTTPS, also its key in the keystore Secret
+	KeystoreFilename = "keystore.jks"
+	// KeystorePassword is the password of the keystore, hardcoded in the Jetty configuration shipped with the Nexus server.
+	// The keystore is as sensitive as the Secret it's generated from: access control to the Secret is what protects it.
+	KeystorePassword = "password"
+	// KeystoreAlias is the alias of the certificate and private key in the keystore
+	KeystoreAlias      = "jetty"
+	keystoreNameFormat = "%s-keystore" // nexus name
 )
 
 var (
-	jvmArgsMap = map[string]string{
-		jvmArgsXms:           heapSizeDefault,
-		jvmArgsXmx:           heapSizeDefault,
-		jvmArgsMaxMemSize:    maxDirectMemorySizeDefault,
-		jvmArgsUserRoot:      "${NEXUS_DATA}/javaprefs",
-		jvmArgRandomPassword: "false",
+	garbageCollectorArgs = map[v1alpha1.NexusGarbageCollector]string{
+		v1alpha1.G1GarbageCollector:       "-XX:+UseG1GC",
+		v1alpha1.ParallelGarbageCollector: "-XX:+UseParallelGC",
+		v1alpha1.SerialGarbageCollector:   "-XX:+UseSerialGC",
 	}
 
 	nexusUID = int64(200)
@@ -82,11 +90,11 @@ func newDeployment(nexus *v1alpha1.Nexus) *appsv1.Deployment {
 					ServiceAccountName: nexus.Spec.ServiceAccountName,
 					Containers: []corev1.Container{
 						{
-							Name: nexusContainerName,
+							Name: v1alpha1.NexusContainerName,
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          NexusPortName,
-									ContainerPort: nexusContainerPort,
+									ContainerPort: nexus.HTTPPort(),
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
@@ -104,25 +112,162 @@ func newDeployment(nexus *v1alpha1.Nexus) *appsv1.Deployment {
 	applyJVMArgs(nexus, deployment)
 	applySecurityContext(nexus, deployment)
 	applyPullPolicy(nexus, deployment)
+	applyScheduling(nexus, deployment)
+	addOAuth2ProxySidecar(nexus, deployment)
+	addDockerPorts(nexus, deployment)
+	addHTTPSPort(nexus, deployment)
+	addExtraContainers(nexus, deployment)
 
 	return deployment
 }
 
+// addExtraContainers adds the sidecars and init containers declared in the Nexus CR, the sidecars running after the ones managed by the Operator
+func addExtraContainers(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	podSpec := &deployment.Spec.Template.Spec
+	for _, sidecar := range nexus.Spec.Sidecars {
+		podSpec.Containers = append(podSpec.Containers, *sidecar.DeepCopy())
+	}
+	for _, initContainer := range nexus.Spec.InitContainers {
+		podSpec.InitContainers = append(podSpec.InitContainers, *initContainer.DeepCopy())
+	}
+}
+
+// KeystoreSecretName is the name of the Secret holding the keystore generated for the Nexus server
+func KeystoreSecretName(nexus *v1alpha1.Nexus) string {
+	return fmt.Sprintf(keystoreNameFormat, nexus.Name)
+}
+
+// addHTTPSPort opens the HTTPS port of the Nexus server, the keystore it requires is mounted by addKeystoreVolume
+func addHTTPSPort(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	if !ServerTLSEnabled(nexus) {
+		return
+	}
+	container := nexusContainer(deployment)
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		Name:          NexusHTTPSPortName,
+		ContainerPort: nexusContainerHTTPSPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+}
+
+// addDockerPorts adds the ports of the HTTP connectors opened by the Nexus server for the Docker repositories
+func addDockerPorts(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	container := nexusContainer(deployment)
+	for _, connector := range DockerConnectors(nexus) {
+		container.Ports = append(container.Ports, corev1.ContainerPort{
+			Name:          connector.PortName,
+			ContainerPort: connector.Port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+}
+
+// addOAuth2ProxySidecar adds the oauth2-proxy container authenticating the traffic before sending it to the Nexus server along with the user ID
+func addOAuth2ProxySidecar(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	oauth2Proxy := nexus.Spec.Security.OAuth2Proxy
+	if oauth2Proxy == nil {
+		return
+	}
+	args := []string{
+		fmt.Sprintf("--http-address=0.0.0.0:%d", OAuth2ProxyPort),
+		fmt.Sprintf("--upstream=http://127.0.0.1:%d", nexus.HTTPPort()),
+		fmt.Sprintf("--provider=%s", oauth2Proxy.Provider),
+		"--reverse-proxy=true",
+		"--pass-user-headers=true",
+		"--skip-provider-button=true",
+	}
+	if contextPath := nexus.ContextPath(); len(contextPath) > 0 {
+		// the sign in endpoints must be reachable under the path routed to the server
+		args = append(args, fmt.Sprintf("--proxy-prefix=%s/oauth2", contextPath))
+	}
+	if len(oauth2Proxy.OIDCIssuerURL) > 0 {
+		args = append(args, fmt.Sprintf("--oidc-issuer-url=%s", oauth2Proxy.OIDCIssuerURL))
+	}
+	for _, domain := range oauth2Proxy.EmailDomains {
+		args = append(args, fmt.Sprintf("--email-domain=%s", domain))
+	}
+	args = append(args, oauth2Proxy.ExtraArgs...)
+
+	deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{
+		Name:            v1alpha1.OAuth2ProxyContainerName,
+		Image:           oauth2Proxy.Image,
+		ImagePullPolicy: oauth2Proxy.ImagePullPolicy,
+		Args:            args,
+		Env: []corev1.EnvVar{
+			oauth2ProxySecretEnv("OAUTH2_PROXY_CLIENT_ID", oauth2Proxy.ClientSecretName, v1alpha1.OAuth2ProxyClientIDKey),
+			oauth2ProxySecretEnv("OAUTH2_PROXY_CLIENT_SECRET", oauth2Proxy.ClientSecretName, v1alpha1.OAuth2ProxyClientSecretKey),
+			oauth2ProxySecretEnv("OAUTH2_PROXY_COOKIE_SECRET", oauth2Proxy.ClientSecretName, v1alpha1.OAuth2ProxyCookieSecretKey),
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          OAuth2ProxyPortName,
+				ContainerPort: OAuth2ProxyPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path:   "/ping",
+					Port:   intstr.FromInt(OAuth2ProxyPort),
+					Scheme: corev1.URISchemeHTTP,
+				},
+			},
+		},
+		Resources: oauth2Proxy.Resources,
+	})
+}
+
+func oauth2ProxySecretEnv(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// applyScheduling sets the scheduling constraints from the Nexus CR, leaving the empty ones unset as the API server would
+func applyScheduling(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	scheduling := nexus.Spec.Scheduling
+	podSpec := &deployment.Spec.Template.Spec
+	if len(scheduling.NodeSelector) > 0 {
+		podSpec.NodeSelector = scheduling.NodeSelector
+	}
+	podSpec.Affinity = scheduling.Affinity
+	if len(scheduling.Tolerations) > 0 {
+		podSpec.Tolerations = scheduling.Tolerations
+	}
+	if len(scheduling.TopologySpreadConstraints) > 0 {
+		podSpec.TopologySpreadConstraints = scheduling.TopologySpreadConstraints
+	}
+	podSpec.PriorityClassName = scheduling.PriorityClassName
+}
+
 func applyPullPolicy(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 	if len(nexus.Spec.ImagePullPolicy) > 0 {
-		deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy = nexus.Spec.ImagePullPolicy
+		nexusContainer(deployment).ImagePullPolicy = nexus.Spec.ImagePullPolicy
 	}
 }
 
 func addProbes(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	// when serving HTTPS, probe it to make sure the keystore has been loaded
+	probePort, probeScheme := nexus.HTTPPort(), corev1.URISchemeHTTP
+	if ServerTLSEnabled(nexus) {
+		probePort, probeScheme = nexusContainerHTTPSPort, corev1.URISchemeHTTPS
+	}
+
 	livenessProbe := &corev1.Probe{
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
-				Path: "/service/rest/v1/status",
+				Path: nexus.ContextPath() + statusPath,
 				Port: intstr.IntOrString{
-					IntVal: nexusContainerPort,
+					IntVal: probePort,
 				},
-				Scheme: corev1.URISchemeHTTP,
+				Scheme: probeScheme,
 			},
 		},
 		InitialDelaySeconds: nexus.Spec.LivenessProbe.InitialDelaySeconds,
@@ -135,11 +280,11 @@ func addProbes(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 	readinessProbe := &corev1.Probe{
 		Handler: corev1.Handler{
 			HTTPGet: &corev1.HTTPGetAction{
-				Path: "/service/rest/v1/status",
+				Path: nexus.ContextPath() + statusPath,
 				Port: intstr.IntOrString{
-					IntVal: nexusContainerPort,
+					IntVal: probePort,
 				},
-				Scheme: corev1.URISchemeHTTP,
+				Scheme: probeScheme,
 			},
 		},
 		InitialDelaySeconds: nexus.Spec.ReadinessProbe.InitialDelaySeconds,
@@ -149,8 +294,9 @@ func addProbes(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 		SuccessThreshold:    nexus.Spec.ReadinessProbe.SuccessThreshold,
 	}
 
-	deployment.Spec.Template.Spec.Containers[0].LivenessProbe = livenessProbe
-	deployment.Spec.Template.Spec.Containers[0].ReadinessProbe = readinessProbe
+	container := nexusContainer(deployment)
+	container.LivenessProbe = livenessProbe
+	container.ReadinessProbe = readinessProbe
 }
 
 func addVolumes(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
@@ -159,6 +305,39 @@ func addVolumes(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 	}
 	addExtraVolumes(nexus, deployment)
 	addConfigMapVolume(nexus, deployment)
+	addKeystoreVolume(nexus, deployment)
+}
+
+// addKeystoreVolume mounts the keystore generated by the Operator where the Nexus server expects it
+func addKeystoreVolume(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
+	if !ServerTLSEnabled(nexus) {
+		return
+	}
+	volumeName := KeystoreSecretName(nexus)
+	deployment.Spec.Template.Spec.Volumes =
+		append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: KeystoreSecretName(nexus),
+					Items: []corev1.KeyToPath{
+						{
+							Key:  KeystoreFilename,
+							Path: KeystoreFilename,
+						},
+					},
+					DefaultMode: &framework.ReadOnlyPermission,
+				},
+			},
+		})
+	container := nexusContainer(deployment)
+	container.VolumeMounts =
+		append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: nexusSSLDir + "/" + KeystoreFilename,
+			ReadOnly:  true,
+			SubPath:   KeystoreFilename,
+		})
 }
 
 func addInstallationVolume(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
@@ -173,10 +352,10 @@ func addInstallationVolume(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment)
 			},
 		},
 	}
-	deployment.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
+	nexusContainer(deployment).VolumeMounts = []corev1.VolumeMount{
 		{
 			Name:      fmt.Sprintf("%s-data", nexus.Name),
-			MountPath: nexusDataDir,
+			MountPath: v1alpha1.NexusDataDir,
 		},
 	}
 }
@@ -189,7 +368,8 @@ func addExtraVolumes(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 			Name:      extraVolume.Name,
 			MountPath: extraVolume.MountPath,
 		}
-		deployment.Spec.Template.Spec.Containers[0].VolumeMounts = append(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, vm)
+		container := nexusContainer(deployment)
+		container.VolumeMounts = append(container.VolumeMounts, vm)
 	}
 }
 
@@ -213,8 +393,9 @@ func addConfigMapVolume(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 				},
 			},
 		})
-	deployment.Spec.Template.Spec.Containers[0].VolumeMounts =
-		append(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+	container := nexusContainer(deployment)
+	container.VolumeMounts =
+		append(container.VolumeMounts, corev1.VolumeMount{
 			Name:      volumeName,
 			MountPath: nexusConfigFileMountPath,
 			ReadOnly:  false,
@@ -222,54 +403,65 @@ func addConfigMapVolume(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
 		})
 }
 
+// applyJVMArgs sets the JVM arguments of the Nexus server, the extra ones from the Nexus CR coming last so they take precedence
 func applyJVMArgs(nexus *v1alpha1.Nexus, deployment *appsv1.Deployment) {
-	jvmMemory, directMemSize := calculateJVMMemory(deployment.Spec.Template.Spec.Containers[0].Resources.Limits)
-	jvmArgsMap[jvmArgsXms] = jvmMemory
-	jvmArgsMap[jvmArgsXmx] = jvmMemory
-	jvmArgsMap[jvmArgsMaxMemSize] = directMemSize
-	jvmArgsMap[jvmArgRandomPassword] = strconv.FormatBool(nexus.Spec.GenerateRandomAdminPassword)
-
-	// we cannot guarantee the key order when transforming the map into a single string.
-	// this might create different strings across the reconciliation loop, causing the comparator to accuse the deployment to be different
-	// that's why we have to sort the map every time.
-	var jvmArgs strings.Builder
-	var jvmArgsKeys []string
-	for key := range jvmArgsMap {
-		jvmArgsKeys = append(jvmArgsKeys, key)
+	container := nexusContainer(deployment)
+	jvm := nexus.Spec.JVM
+	heapSize, directMemSize := calculateJVMMemory(jvm, container.Resources.Limits)
+	jvmArgs := []string{
+		jvmArgsUserRoot + "=" + javaPrefsUserRoot,
+		jvmArgRandomPassword + "=" + strconv.FormatBool(nexus.Spec.GenerateRandomAdminPassword),
+		jvmArgsMaxMemSize + "=" + directMemSize,
+		jvmArgsXms + heapSize,
+		jvmArgsXmx + heapSize,
 	}
-	sort.Strings(jvmArgsKeys)
-	for _, key := range jvmArgsKeys {
-		jvmArgs.WriteString(key)
-		if key != jvmArgsXms && key != jvmArgsXmx {
-			jvmArgs.WriteString("=")
-		}
-		jvmArgs.WriteString(jvmArgsMap[key])
-		jvmArgs.WriteString(" ")
+	if gcArg, ok := garbageCollectorArgs[jvm.GarbageCollector]; ok {
+		jvmArgs = append(jvmArgs, gcArg)
 	}
+	if len(jvm.HeapDumpPath) > 0 {
+		jvmArgs = append(jvmArgs, jvmArgHeapDumpOnOOM, jvmArgHeapDumpPath+"="+jvm.HeapDumpPath)
+	}
+	jvmArgs = append(jvmArgs, jvm.ExtraArgs...)
 
-	deployment.Spec.Template.Spec.Containers[0].Env =
-		append(deployment.Spec.Template.Spec.Containers[0].Env,
+	container.Env =
+		append(container.Env,
 			corev1.EnvVar{
 				Name:  JvmArgsEnvKey,
-				Value: jvmArgs.String(),
+				Value: strings.Join(jvmArgs, " "),
 			})
 }
 
-func calculateJVMMemory(limits corev1.ResourceList) (jvmMemory, directMemSize string) {
-	if limits != nil {
-		memoryLimit := limits.Memory()
-		if memoryLimit != nil {
-			limitValue := memoryLimit.ScaledValue(resource.Mega)
-			jvmMemory = fmt.Sprintf("%.0fm", float64(limitValue)*0.8)
-			directMemSize = fmt.Sprintf("%dm", limitValue)
-			return
+// calculateJVMMemory sizes the heap and the direct memory, the absolute sizes taking precedence over the percentages of the memory limit.
+// Without percentages, as for the servers deployed by previous versions of the Operator, the memory limit is split as those versions did.
+func calculateJVMMemory(jvm v1alpha1.NexusJVM, limits corev1.ResourceList) (heapSize, directMemSize string) {
+	heapSize, directMemSize = heapSizeDefault, maxDirectMemorySizeDefault
+	if memoryLimit, ok := limits[corev1.ResourceMemory]; ok {
+		legacyLimit := memoryLimit.ScaledValue(resource.Mega)
+		if jvm.HeapPercentage != nil {
+			heapSize = toJVMSize(memoryLimit.Value() * int64(*jvm.HeapPercentage) / 100)
+		} else {
+			heapSize = fmt.Sprintf("%.0fm", float64(legacyLimit)*legacyHeapRatio)
+		}
+		if jvm.DirectMemoryPercentage != nil {
+			directMemSize = toJVMSize(memoryLimit.Value() * int64(*jvm.DirectMemoryPercentage) / 100)
+		} else {
+			directMemSize = fmt.Sprintf("%dm", legacyLimit)
 		}
 	}
-	jvmMemory = heapSizeDefault
-	directMemSize = maxDirectMemorySizeDefault
+	if jvm.HeapSize != nil {
+		heapSize = toJVMSize(jvm.HeapSize.Value())
+	}
+	if jvm.DirectMemorySize != nil {
+		directMemSize = toJVMSize(jvm.DirectMemorySize.Value())
+	}
 	return
 }
 
+// toJVMSize formats the size in bytes in mebibytes, as understood by the JVM
+func toJVMSize(bytes int64) string {
+	return fmt.Sprintf("%dm", bytes/mebibyte)
+}
+
 // see: https://catalog.redhat.com/software/containers/sonatype/nexus-repository-manager/594c281c1fbe9847af657690?container-tabs=overview
 // Even RH image requires user 200 in the security context if no PV is set to be able to write to the ephemeral pod directory, quoting the link above:
 // "A persistent directory, /nexus-data, is used for configuration,  logs, and storage. This directory needs to be writable by the Nexus process, which runs as UID 200."
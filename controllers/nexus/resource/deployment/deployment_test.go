@@ -55,8 +55,8 @@ func Test_newDeployment_WithoutPersistence(t *testing.T) {
 	assert.Equal(t, validation.NexusCommunityImage, deployment.Spec.Template.Spec.Containers[0].Image)
 	assert.Equal(t, int32(1), *deployment.Spec.Replicas)
 
-	assert.Equal(t, int32(nexusContainerPort), deployment.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Port.IntVal)
-	assert.Equal(t, int32(nexusContainerPort), deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, v1alpha1.DefaultNexusHTTPPort, deployment.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, v1alpha1.DefaultNexusHTTPPort, deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Port.IntVal)
 
 	assert.Len(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts, 1)
 	assert.Len(t, deployment.Spec.Template.Spec.Volumes, 1)
@@ -91,12 +91,18 @@ func Test_newDeployment_WithPersistence(t *testing.T) {
 	assert.Len(t, deployment.Spec.Template.Spec.Containers, 1)
 	assert.Len(t, deployment.Spec.Template.Spec.Containers[0].VolumeMounts, 2)
 	assert.Len(t, deployment.Spec.Template.Spec.Volumes, 2)
-	assert.Equal(t, nexusDataDir, deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath)
+	assert.Equal(t, v1alpha1.NexusDataDir, deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath)
 }
 
-// see: https://stackoverflow.com/questions/50804915/kubernetes-size-definitions-whats-the-difference-of-gi-and-g
 func Test_calculateJVMMemory(t *testing.T) {
+	percentage := func(value int32) *int32 { return &value }
+	size := func(value string) *resource.Quantity {
+		quantity := resource.MustParse(value)
+		return &quantity
+	}
+	defaultPercentages := v1alpha1.NexusJVM{HeapPercentage: percentage(50), DirectMemoryPercentage: percentage(25)}
 	type args struct {
+		jvm    v1alpha1.NexusJVM
 		limits corev1.ResourceList
 	}
 	tests := []struct {
@@ -105,34 +111,52 @@ func Test_calculateJVMMemory(t *testing.T) {
 		wantJvmMemory     string
 		wantDirectMemSize string
 	}{
+		{
+			"No memory limit",
+			args{jvm: defaultPercentages},
+			heapSizeDefault,
+			maxDirectMemorySizeDefault,
+		},
+		{
+			"2 Gibi",
+			args{jvm: defaultPercentages, limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("2Gi")}},
+			"1024m",
+			"512m",
+		},
 		{
 			"2 Giga",
-			args{limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("2G")}},
-			"1600m",
-			"2000m",
+			args{jvm: defaultPercentages, limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("2G")}},
+			"953m",
+			"476m",
+		},
+		{
+			"2 Gibi without percentages",
+			args{limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("2Gi")}},
+			"1718m",
+			"2148m",
 		},
 		{
-			"1.4 Mega",
-			args{limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("1400M")}},
-			"1120m",
-			"1400m",
+			"10 Gibi with custom percentages",
+			args{jvm: v1alpha1.NexusJVM{HeapPercentage: percentage(60), DirectMemoryPercentage: percentage(30)}, limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("10Gi")}},
+			"6144m",
+			"3072m",
 		},
 		{
-			"0.4 Mega",
-			args{limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("400M")}},
-			"320m",
-			"400m",
+			"Absolute heap size",
+			args{jvm: v1alpha1.NexusJVM{HeapSize: size("3Gi"), HeapPercentage: percentage(50), DirectMemoryPercentage: percentage(25)}, limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("8Gi")}},
+			"3072m",
+			"2048m",
 		},
 		{
-			"10 Giga",
-			args{limits: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("10G")}},
-			"8000m",
-			"10000m",
+			"Absolute sizes without memory limit",
+			args{jvm: v1alpha1.NexusJVM{HeapSize: size("2703Mi"), DirectMemorySize: size("1.5Gi")}},
+			"2703m",
+			"1536m",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotJvmMemory, gotDirectMemSize := calculateJVMMemory(tt.args.limits)
+			gotJvmMemory, gotDirectMemSize := calculateJVMMemory(tt.args.jvm, tt.args.limits)
 			if gotJvmMemory != tt.wantJvmMemory {
 				t.Errorf("calculateJVMMemory() gotJvmMemory = %v, want %v", gotJvmMemory, tt.wantJvmMemory)
 			}
@@ -227,6 +251,31 @@ func Test_applyJVMArgs_withDefaultValues(t *testing.T) {
 	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env[0].Value, strings.Join([]string{jvmArgsXmx, heapSizeDefault}, ""))
 }
 
+func Test_applyJVMArgs_withJVMTuning(t *testing.T) {
+	heapPercentage, directMemoryPercentage := int32(60), int32(20)
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Resources = corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}}
+	nexus.Spec.JVM = v1alpha1.NexusJVM{
+		HeapPercentage:         &heapPercentage,
+		DirectMemoryPercentage: &directMemoryPercentage,
+		GarbageCollector:       v1alpha1.G1GarbageCollector,
+		HeapDumpPath:           "/nexus-data/log",
+		ExtraArgs:              []string{"-XX:ActiveProcessorCount=2", "-Dkaraf.log.console=INFO"},
+	}
+	deployment := newDeployment(nexus)
+
+	assert.Equal(t, JvmArgsEnvKey, nexusContainer(deployment).Env[0].Name)
+	assert.Equal(t, "-Djava.util.prefs.userRoot=${NEXUS_DATA}/javaprefs -Dnexus.security.randompassword=false -XX:MaxDirectMemorySize=819m -Xms2457m -Xmx2457m "+
+		"-XX:+UseG1GC -XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=/nexus-data/log -XX:ActiveProcessorCount=2 -Dkaraf.log.console=INFO", nexusContainer(deployment).Env[0].Value)
+
+	// the arguments of each instance are computed on their own
+	other := allDefaultsCommunityNexus.DeepCopy()
+	other.Spec.GenerateRandomAdminPassword = true
+	otherDeployment := newDeployment(other)
+	assert.NotContains(t, nexusContainer(otherDeployment).Env[0].Value, "-XX:+UseG1GC")
+	assert.Equal(t, nexusContainer(deployment).Env[0].Value, nexusContainer(newDeployment(nexus)).Env[0].Value)
+}
+
 func Test_newDeployment_WithExtraVolumes(t *testing.T) {
 	nexus := &v1alpha1.Nexus{
 		ObjectMeta: metav1.ObjectMeta{Name: "nexus-test", Namespace: t.Name()},
@@ -276,3 +325,210 @@ func deploymentContainsNexusVolume(deployment *appsv1.Deployment, nexusVolume v1
 	}
 	return false
 }
+
+func Test_newDeployment_WithOAuth2ProxySidecar(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{
+		Image:            validation.DefaultOAuth2ProxyImage,
+		Provider:         "oidc",
+		OIDCIssuerURL:    "https://keycloak.example.com/auth/realms/example",
+		ClientSecretName: "oauth2-proxy",
+		EmailDomains:     []string{"example.com"},
+		ExtraArgs:        []string{"--skip-auth-route=^/repository/"},
+	}
+	deployment := newDeployment(nexus)
+
+	assert.Len(t, deployment.Spec.Template.Spec.Containers, 2)
+	assert.Equal(t, v1alpha1.NexusContainerName, deployment.Spec.Template.Spec.Containers[0].Name)
+	sidecar := deployment.Spec.Template.Spec.Containers[1]
+	assert.Equal(t, v1alpha1.OAuth2ProxyContainerName, sidecar.Name)
+	assert.Equal(t, validation.DefaultOAuth2ProxyImage, sidecar.Image)
+	assert.Equal(t, []string{
+		"--http-address=0.0.0.0:4180",
+		"--upstream=http://127.0.0.1:8081",
+		"--provider=oidc",
+		"--reverse-proxy=true",
+		"--pass-user-headers=true",
+		"--skip-provider-button=true",
+		"--oidc-issuer-url=https://keycloak.example.com/auth/realms/example",
+		"--email-domain=example.com",
+		"--skip-auth-route=^/repository/",
+	}, sidecar.Args)
+	assert.Len(t, sidecar.Env, 3)
+	for _, env := range sidecar.Env {
+		assert.Equal(t, "oauth2-proxy", env.ValueFrom.SecretKeyRef.Name)
+	}
+	assert.Equal(t, int32(OAuth2ProxyPort), sidecar.Ports[0].ContainerPort)
+}
+
+func Test_newDeployment_WithDockerConnectors(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	port := int32(5000)
+	nexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port}},
+		{Name: "docker-proxy", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.ProxyRepositoryType, Docker: &v1alpha1.RepositoryDocker{}},
+	}
+	deployment := newDeployment(nexus)
+
+	ports := deployment.Spec.Template.Spec.Containers[0].Ports
+	assert.Len(t, ports, 2)
+	assert.Equal(t, "docker-5000", ports[1].Name)
+	assert.Equal(t, port, ports[1].ContainerPort)
+}
+
+func Test_newDeployment_WithContextPath(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Networking.ContextPath = "/nexus"
+	nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{Image: validation.DefaultOAuth2ProxyImage, Provider: "oidc", ClientSecretName: "oauth2-proxy"}
+	deployment := newDeployment(nexus)
+
+	nexusContainer := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "/nexus/service/rest/v1/status", nexusContainer.LivenessProbe.HTTPGet.Path)
+	assert.Equal(t, "/nexus/service/rest/v1/status", nexusContainer.ReadinessProbe.HTTPGet.Path)
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[1].Args, "--proxy-prefix=/nexus/oauth2")
+}
+
+func Test_nexusProperties(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Properties: map[string]string{"nexus.conan.hosted.enabled": "true", v1alpha1.ContextPathProperty: "/other"}}}
+	nexus.Spec.Networking.ContextPath = "/nexus"
+	assert.Equal(t, map[string]string{"nexus.conan.hosted.enabled": "true", v1alpha1.ContextPathProperty: "/nexus"}, nexusProperties(nexus))
+	// the Nexus CR is left untouched
+	assert.Equal(t, "/other", nexus.Spec.Properties[v1alpha1.ContextPathProperty])
+
+	// the property is used as is if the field isn't set
+	nexus.Spec.Networking.ContextPath = ""
+	assert.Equal(t, map[string]string{"nexus.conan.hosted.enabled": "true", v1alpha1.ContextPathProperty: "/other"}, nexusProperties(nexus))
+
+	nexus.Spec.Properties[v1alpha1.ContextPathProperty] = "/"
+	assert.Equal(t, map[string]string{"nexus.conan.hosted.enabled": "true"}, nexusProperties(nexus))
+}
+
+func Test_nexusProperties_WithConfiguration(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Properties: map[string]string{
+		v1alpha1.HTTPPortProperty:            "9000",
+		v1alpha1.AllowScriptCreationProperty: "true",
+		v1alpha1.DatastoreEnabledProperty:    "true",
+	}}}
+	nexus.Spec.Configuration = v1alpha1.NexusConfiguration{HTTPPort: 8082, Datastore: v1alpha1.OrientDBDatastore}
+	// the script creation is enabled by either the field or the property
+	assert.Equal(t, map[string]string{v1alpha1.HTTPPortProperty: "8082", v1alpha1.AllowScriptCreationProperty: "true"}, nexusProperties(nexus))
+
+	// the default port is left to the image
+	nexus.Spec.Configuration = v1alpha1.NexusConfiguration{}
+	nexus.Spec.Properties = map[string]string{v1alpha1.HTTPPortProperty: "8081", v1alpha1.DatastoreEnabledProperty: "true"}
+	assert.Equal(t, map[string]string{v1alpha1.DatastoreEnabledProperty: "true"}, nexusProperties(nexus))
+
+	nexus.Spec.Configuration = v1alpha1.NexusConfiguration{HTTPPort: 8082, AllowScriptCreation: true, Datastore: v1alpha1.H2Datastore}
+	assert.Equal(t, map[string]string{
+		v1alpha1.HTTPPortProperty:            "8082",
+		v1alpha1.AllowScriptCreationProperty: "true",
+		v1alpha1.DatastoreEnabledProperty:    "true",
+	}, nexusProperties(nexus))
+
+	nexus = allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Configuration.HTTPPort = 8082
+	deployment := newDeployment(nexus)
+	assert.Equal(t, int32(8082), deployment.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)
+	assert.Equal(t, int32(8082), deployment.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Port.IntVal)
+	assert.Equal(t, int32(8082), newService(nexus).Spec.Ports[0].TargetPort.IntVal)
+}
+
+func Test_newConfigMap(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Properties: map[string]string{"nexus.conan.hosted.enabled": "true", "nexus.onboarding.enabled": "false"}}}
+	nexus.Spec.Networking.ContextPath = "/nexus"
+	configMap := newConfigMap(nexus)
+	assert.Equal(t, "nexus-context-path=/nexus\nnexus.conan.hosted.enabled=true\nnexus.onboarding.enabled=false\n", configMap.Data[nexusPropertiesFilename])
+	// the content doesn't depend on the order of the map
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, configMap.Data, newConfigMap(nexus).Data)
+	}
+}
+
+func Test_newDeployment_WithServerTLS(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Networking = v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "nexus3-server-tls"}}
+	deployment := newDeployment(nexus)
+
+	nexusContainer := deployment.Spec.Template.Spec.Containers[0]
+	assert.Len(t, nexusContainer.Ports, 2)
+	assert.Equal(t, NexusHTTPSPortName, nexusContainer.Ports[1].Name)
+	assert.Equal(t, int32(nexusContainerHTTPSPort), nexusContainer.Ports[1].ContainerPort)
+	assert.Equal(t, corev1.URISchemeHTTPS, nexusContainer.LivenessProbe.HTTPGet.Scheme)
+	assert.Equal(t, int32(nexusContainerHTTPSPort), nexusContainer.ReadinessProbe.HTTPGet.Port.IntVal)
+
+	keystoreVolume := deployment.Spec.Template.Spec.Volumes[len(deployment.Spec.Template.Spec.Volumes)-1]
+	assert.Equal(t, KeystoreSecretName(nexus), keystoreVolume.Secret.SecretName)
+	keystoreMount := nexusContainer.VolumeMounts[len(nexusContainer.VolumeMounts)-1]
+	assert.Equal(t, keystoreVolume.Name, keystoreMount.Name)
+	assert.Equal(t, "/nexus-data/etc/ssl/keystore.jks", keystoreMount.MountPath)
+	assert.True(t, keystoreMount.ReadOnly)
+
+	// edge termination keeps the server on plain HTTP
+	nexus.Spec.Networking.TLS.Termination = v1alpha1.EdgeTLSTermination
+	deployment = newDeployment(nexus)
+	assert.Len(t, deployment.Spec.Template.Spec.Containers[0].Ports, 1)
+	assert.Equal(t, corev1.URISchemeHTTP, deployment.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Scheme)
+}
+
+func Test_nexusProperties_WithServerTLS(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.PassthroughTLSTermination}}}}
+	properties := nexusProperties(nexus)
+	assert.Equal(t, "8443", properties[httpsPortProperty])
+	assert.Equal(t, "/nexus-data/etc/ssl", properties[sslDirProperty])
+	assert.Equal(t, defaultNexusArgs+","+jettyHTTPSConfig, properties[nexusArgsProperty])
+
+	// the Jetty configuration files from the Nexus CR are kept
+	nexus.Spec.Properties = map[string]string{nexusArgsProperty: "${jetty.etc}/jetty.xml,${jetty.etc}/jetty-http.xml"}
+	assert.Equal(t, "${jetty.etc}/jetty.xml,${jetty.etc}/jetty-http.xml,"+jettyHTTPSConfig, nexusProperties(nexus)[nexusArgsProperty])
+}
+
+func Test_newDeployment_WithScheduling(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Scheduling = v1alpha1.NexusScheduling{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/storage": ""},
+		Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}}},
+		}},
+		Tolerations:               []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "storage", Effect: corev1.TaintEffectNoSchedule}},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway}},
+		PriorityClassName:         "high-priority",
+	}
+	podSpec := newDeployment(nexus).Spec.Template.Spec
+
+	assert.Equal(t, nexus.Spec.Scheduling.NodeSelector, podSpec.NodeSelector)
+	assert.Equal(t, nexus.Spec.Scheduling.Affinity, podSpec.Affinity)
+	assert.Equal(t, nexus.Spec.Scheduling.Tolerations, podSpec.Tolerations)
+	assert.Equal(t, nexus.Spec.Scheduling.TopologySpreadConstraints, podSpec.TopologySpreadConstraints)
+	assert.Equal(t, "high-priority", podSpec.PriorityClassName)
+
+	// empty constraints are left unset, as the API server would return them
+	nexus.Spec.Scheduling = v1alpha1.NexusScheduling{NodeSelector: map[string]string{}, Tolerations: []corev1.Toleration{}}
+	podSpec = newDeployment(nexus).Spec.Template.Spec
+	assert.Nil(t, podSpec.NodeSelector)
+	assert.Nil(t, podSpec.Tolerations)
+}
+
+func Test_newDeployment_WithExtraContainers(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{Image: validation.DefaultOAuth2ProxyImage, ClientSecretName: "oauth2-proxy"}
+	nexus.Spec.Sidecars = []corev1.Container{{Name: "log-shipper", Image: "fluent/fluent-bit:1.7"}}
+	nexus.Spec.InitContainers = []corev1.Container{{Name: "fix-permissions", Image: "busybox", Command: []string{"chown", "-R", "200:200", "/nexus-data"}}}
+	podSpec := newDeployment(nexus).Spec.Template.Spec
+
+	// the sidecars run after the containers managed by the Operator
+	assert.Len(t, podSpec.Containers, 3)
+	assert.Equal(t, v1alpha1.NexusContainerName, podSpec.Containers[0].Name)
+	assert.Equal(t, v1alpha1.OAuth2ProxyContainerName, podSpec.Containers[1].Name)
+	assert.Equal(t, nexus.Spec.Sidecars[0], podSpec.Containers[2])
+	assert.Equal(t, nexus.Spec.InitContainers, podSpec.InitContainers)
+}
+
+func Test_nexusContainer(t *testing.T) {
+	deployment := newDeployment(allDefaultsCommunityNexus)
+	sidecar := corev1.Container{Name: "log-shipper"}
+	deployment.Spec.Template.Spec.Containers = append([]corev1.Container{sidecar}, deployment.Spec.Template.Spec.Containers...)
+	assert.Equal(t, v1alpha1.NexusContainerName, nexusContainer(deployment).Name)
+
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{sidecar}
+	assert.Nil(t, nexusContainer(deployment))
+}
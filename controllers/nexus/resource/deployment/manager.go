@@ -28,16 +28,21 @@ import (
 	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/patch"
 	"github.com/m88i/nexus-operator/pkg/framework"
 	"github.com/m88i/nexus-operator/pkg/framework/kind"
 	"github.com/m88i/nexus-operator/pkg/logger"
 )
 
-const configMapHashAnnotationKey = "config-map-property-hash"
+const (
+	configMapHashAnnotationKey = "config-map-property-hash"
+	keystoreHashAnnotationKey  = "keystore-hash"
+)
 
 var managedObjectsRef = map[string]resource.KubernetesResource{
 	kind.DeploymentKind: &appsv1.Deployment{},
@@ -45,6 +50,12 @@ var managedObjectsRef = map[string]resource.KubernetesResource{
 	kind.ConfigMapKind:  &corev1.ConfigMap{},
 }
 
+// managedContainers are the containers set up by the Operator, compared on the fields it sets
+var managedContainers = map[string]bool{
+	v1alpha1.NexusContainerName:       true,
+	v1alpha1.OAuth2ProxyContainerName: true,
+}
+
 // Manager is responsible for creating deployment-related resources, fetching deployed ones and comparing them
 // Use with zero values will result in a panic. Use the NewManager function to get a properly initialized manager
 type Manager struct {
@@ -72,7 +83,16 @@ func (m *Manager) GetRequiredResources() ([]resource.KubernetesResource, error)
 	if err := m.applyConfigMapPropertiesHash(deployment); err != nil {
 		return nil, err
 	}
-	return []resource.KubernetesResource{newConfigMap(m.nexus), deployment, newService(m.nexus)}, nil
+	if err := m.applyKeystoreHash(deployment); err != nil {
+		return nil, err
+	}
+	service := newService(m.nexus)
+	for _, res := range []resource.KubernetesResource{deployment, service} {
+		if err := patch.Apply(m.nexus, res); err != nil {
+			return nil, err
+		}
+	}
+	return []resource.KubernetesResource{newConfigMap(m.nexus), deployment, service}, nil
 }
 
 // GetDeployedResources returns the deployment-related resources deployed on the cluster
@@ -92,11 +112,14 @@ func (m *Manager) GetDeployedResources() ([]resource.KubernetesResource, error)
 // Returns nil if there is none
 func (m *Manager) GetCustomComparator(t reflect.Type) func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
 	if t == reflect.TypeOf(&appsv1.Deployment{}) {
-		return deploymentEqual
+		return patch.Comparator(m.nexus, t.Elem(), deploymentEqual)
 	}
 	if t == reflect.TypeOf(&corev1.ConfigMap{}) {
 		return configMapEqual
 	}
+	if t == reflect.TypeOf(&corev1.Service{}) {
+		return patch.Comparator(m.nexus, t.Elem(), serviceEqual)
+	}
 	return nil
 }
 
@@ -105,10 +128,31 @@ func (m *Manager) GetCustomComparator(t reflect.Type) func(deployed resource.Kub
 func (m *Manager) GetCustomComparators() map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
 	deploymentType := reflect.TypeOf(appsv1.Deployment{})
 	configMapType := reflect.TypeOf(corev1.ConfigMap{})
+	serviceType := reflect.TypeOf(corev1.Service{})
 	return map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool{
-		deploymentType: deploymentEqual,
+		deploymentType: patch.Comparator(m.nexus, deploymentType, deploymentEqual),
 		configMapType:  configMapEqual,
+		serviceType:    patch.Comparator(m.nexus, serviceType, serviceEqual),
+	}
+}
+
+// serviceEqual ignores the node ports allocated by the cluster, NodePort and LoadBalancer services get one for each port
+func serviceEqual(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
+	depService := deployed.(*corev1.Service)
+	reqService := requested.(*corev1.Service).DeepCopy()
+	if depService.Spec.Type == reqService.Spec.Type {
+		for i := range reqService.Spec.Ports {
+			if reqService.Spec.Ports[i].NodePort != 0 {
+				continue
+			}
+			for _, depPort := range depService.Spec.Ports {
+				if depPort.Name == reqService.Spec.Ports[i].Name {
+					reqService.Spec.Ports[i].NodePort = depPort.NodePort
+				}
+			}
+		}
 	}
+	return compare.DefaultComparator().GetComparator(reflect.TypeOf(corev1.Service{}))(depService, reqService)
 }
 
 func configMapEqual(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
@@ -142,16 +186,17 @@ func deploymentEqual(deployed resource.KubernetesResource, requested resource.Ku
 	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Volumes, reqDeployment.Spec.Template.Spec.Volumes})
 	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.ServiceAccountName, reqDeployment.Spec.Template.Spec.ServiceAccountName})
 	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.SecurityContext, reqDeployment.Spec.Template.Spec.SecurityContext})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].Name, reqDeployment.Spec.Template.Spec.Containers[0].Name})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].Ports, reqDeployment.Spec.Template.Spec.Containers[0].Ports})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].Resources, reqDeployment.Spec.Template.Spec.Containers[0].Resources})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].Image, reqDeployment.Spec.Template.Spec.Containers[0].Image})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].LivenessProbe, reqDeployment.Spec.Template.Spec.Containers[0].LivenessProbe})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe, reqDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe})
-	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Containers[0].Env, reqDeployment.Spec.Template.Spec.Containers[0].Env})
+	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.NodeSelector, reqDeployment.Spec.Template.Spec.NodeSelector})
+	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Affinity, reqDeployment.Spec.Template.Spec.Affinity})
+	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.Tolerations, reqDeployment.Spec.Template.Spec.Tolerations})
+	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.TopologySpreadConstraints, reqDeployment.Spec.Template.Spec.TopologySpreadConstraints})
+	pairs = append(pairs, [2]interface{}{depDeployment.Spec.Template.Spec.PriorityClassName, reqDeployment.Spec.Template.Spec.PriorityClassName})
 
 	equal := compare.EqualPairs(pairs)
+	equal = equal && equalNexusContainers(depDeployment, reqDeployment)
 	equal = equal && equalPullPolicies(depDeployment, reqDeployment)
+	equal = equal && equalOAuth2ProxySidecars(depDeployment, reqDeployment)
+	equal = equal && equalExtraContainers(depDeployment, reqDeployment)
 
 	if !equal {
 		logger.GetLogger("deployment_manager").Info("Resources are not equal", "deployed", deployed, "requested", requested)
@@ -160,17 +205,169 @@ func deploymentEqual(deployed resource.KubernetesResource, requested resource.Ku
 	return equal
 }
 
+// equalNexusContainers compares the fields we set in the Nexus server container
+func equalNexusContainers(depDeployment, reqDeployment *appsv1.Deployment) bool {
+	depContainer := nexusContainer(depDeployment)
+	reqContainer := nexusContainer(reqDeployment)
+	if depContainer == nil || reqContainer == nil {
+		return depContainer == reqContainer
+	}
+
+	var pairs [][2]interface{}
+	pairs = append(pairs, [2]interface{}{depContainer.Ports, reqContainer.Ports})
+	pairs = append(pairs, [2]interface{}{depContainer.Resources, reqContainer.Resources})
+	pairs = append(pairs, [2]interface{}{depContainer.Image, reqContainer.Image})
+	pairs = append(pairs, [2]interface{}{depContainer.LivenessProbe, reqContainer.LivenessProbe})
+	pairs = append(pairs, [2]interface{}{depContainer.ReadinessProbe, reqContainer.ReadinessProbe})
+	pairs = append(pairs, [2]interface{}{depContainer.Env, reqContainer.Env})
+	return compare.EqualPairs(pairs)
+}
+
 func equalPullPolicies(depDeployment, reqDeployment *appsv1.Deployment) bool {
-	if len(reqDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy) > 0 {
-		return reqDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy == depDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy
+	depContainer := nexusContainer(depDeployment)
+	reqContainer := nexusContainer(reqDeployment)
+	if depContainer == nil || reqContainer == nil {
+		return depContainer == reqContainer
 	}
 
-	reqImageParts := strings.Split(reqDeployment.Spec.Template.Spec.Containers[0].Image, ":")
+	if len(reqContainer.ImagePullPolicy) > 0 {
+		return reqContainer.ImagePullPolicy == depContainer.ImagePullPolicy
+	}
+
+	reqImageParts := strings.Split(reqContainer.Image, ":")
 	if len(reqImageParts) == 1 || reqImageParts[1] == "latest" {
-		return depDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy == corev1.PullAlways
+		return depContainer.ImagePullPolicy == corev1.PullAlways
+	}
+
+	return depContainer.ImagePullPolicy == corev1.PullIfNotPresent
+}
+
+// equalOAuth2ProxySidecars compares the fields we set in the oauth2-proxy sidecar, leaving out the ones defaulted by the API server
+func equalOAuth2ProxySidecars(depDeployment, reqDeployment *appsv1.Deployment) bool {
+	depSidecar := findContainer(depDeployment, v1alpha1.OAuth2ProxyContainerName)
+	reqSidecar := findContainer(reqDeployment, v1alpha1.OAuth2ProxyContainerName)
+	if depSidecar == nil || reqSidecar == nil {
+		return depSidecar == reqSidecar
 	}
 
-	return depDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy == corev1.PullIfNotPresent
+	var pairs [][2]interface{}
+	pairs = append(pairs, [2]interface{}{depSidecar.Image, reqSidecar.Image})
+	pairs = append(pairs, [2]interface{}{depSidecar.Args, reqSidecar.Args})
+	pairs = append(pairs, [2]interface{}{depSidecar.Env, reqSidecar.Env})
+	pairs = append(pairs, [2]interface{}{depSidecar.Ports, reqSidecar.Ports})
+	pairs = append(pairs, [2]interface{}{depSidecar.Resources, reqSidecar.Resources})
+	equal := compare.EqualPairs(pairs)
+	if len(reqSidecar.ImagePullPolicy) > 0 {
+		equal = equal && depSidecar.ImagePullPolicy == reqSidecar.ImagePullPolicy
+	}
+	return equal
+}
+
+// equalExtraContainers compares the init containers and the containers not managed by the Operator, such as the sidecars from the Nexus CR.
+// The containers must be the same and in the same order, the fields left blank in the requested ones being defaulted by the API server.
+func equalExtraContainers(depDeployment, reqDeployment *appsv1.Deployment) bool {
+	depPodSpec := &depDeployment.Spec.Template.Spec
+	reqPodSpec := &reqDeployment.Spec.Template.Spec
+	return equalContainers(depPodSpec.InitContainers, reqPodSpec.InitContainers) && equalContainers(depPodSpec.Containers, reqPodSpec.Containers)
+}
+
+func equalContainers(depContainers, reqContainers []corev1.Container) bool {
+	if len(depContainers) != len(reqContainers) {
+		return false
+	}
+	for i := range reqContainers {
+		if depContainers[i].Name != reqContainers[i].Name {
+			return false
+		}
+		if managedContainers[reqContainers[i].Name] {
+			// compared on their own, the Operator knows which fields it sets
+			continue
+		}
+		if !equality.Semantic.DeepEqual(depContainers[i], withAPIDefaults(reqContainers[i], depContainers[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// withAPIDefaults returns a copy of the requested container with the fields defaulted by the API server taken from the deployed one, if left blank
+func withAPIDefaults(req, dep corev1.Container) corev1.Container {
+	container := *req.DeepCopy()
+	if len(container.TerminationMessagePath) == 0 {
+		container.TerminationMessagePath = dep.TerminationMessagePath
+	}
+	if len(container.TerminationMessagePolicy) == 0 {
+		container.TerminationMessagePolicy = dep.TerminationMessagePolicy
+	}
+	if len(container.ImagePullPolicy) == 0 {
+		container.ImagePullPolicy = dep.ImagePullPolicy
+	}
+	// the requests default to the limits
+	for name := range container.Resources.Limits {
+		if _, ok := container.Resources.Requests[name]; ok {
+			continue
+		}
+		if request, ok := dep.Resources.Requests[name]; ok {
+			if container.Resources.Requests == nil {
+				container.Resources.Requests = corev1.ResourceList{}
+			}
+			container.Resources.Requests[name] = request
+		}
+	}
+	if len(container.Ports) == len(dep.Ports) {
+		for i := range container.Ports {
+			if len(container.Ports[i].Protocol) == 0 {
+				container.Ports[i].Protocol = dep.Ports[i].Protocol
+			}
+		}
+	}
+	if len(container.Env) == len(dep.Env) {
+		for i := range container.Env {
+			reqSource, depSource := container.Env[i].ValueFrom, dep.Env[i].ValueFrom
+			if reqSource != nil && depSource != nil && reqSource.FieldRef != nil && depSource.FieldRef != nil && len(reqSource.FieldRef.APIVersion) == 0 {
+				reqSource.FieldRef.APIVersion = depSource.FieldRef.APIVersion
+			}
+		}
+	}
+	withProbeDefaults(container.LivenessProbe, dep.LivenessProbe)
+	withProbeDefaults(container.ReadinessProbe, dep.ReadinessProbe)
+	withProbeDefaults(container.StartupProbe, dep.StartupProbe)
+	return container
+}
+
+func withProbeDefaults(req, dep *corev1.Probe) {
+	if req == nil || dep == nil {
+		return
+	}
+	if req.TimeoutSeconds == 0 {
+		req.TimeoutSeconds = dep.TimeoutSeconds
+	}
+	if req.PeriodSeconds == 0 {
+		req.PeriodSeconds = dep.PeriodSeconds
+	}
+	if req.SuccessThreshold == 0 {
+		req.SuccessThreshold = dep.SuccessThreshold
+	}
+	if req.FailureThreshold == 0 {
+		req.FailureThreshold = dep.FailureThreshold
+	}
+	if req.HTTPGet != nil && dep.HTTPGet != nil && len(req.HTTPGet.Scheme) == 0 {
+		req.HTTPGet.Scheme = dep.HTTPGet.Scheme
+	}
+}
+
+// nexusContainer returns the Nexus server container, which isn't necessarily the first one of the pods
+func nexusContainer(deployment *appsv1.Deployment) *corev1.Container {
+	return findContainer(deployment, v1alpha1.NexusContainerName)
+}
+
+func findContainer(deployment *appsv1.Deployment, name string) *corev1.Container {
+	for i := range deployment.Spec.Template.Spec.Containers {
+		if deployment.Spec.Template.Spec.Containers[i].Name == name {
+			return &deployment.Spec.Template.Spec.Containers[i]
+		}
+	}
+	return nil
 }
 
 // see: https://github.com/m88i/nexus-operator/issues/156
@@ -188,7 +385,23 @@ func (m *Manager) applyConfigMapPropertiesHash(deployment *appsv1.Deployment) er
 	if err := framework.Fetch(m.client, types.NamespacedName{Namespace: m.nexus.Namespace, Name: m.nexus.Name}, configMap, kind.ConfigMapKind); err != nil && !errors.IsNotFound(err) {
 		return err
 	}
-	contentHash := fmt.Sprintf("%x", md5.Sum([]byte(configMap.Data[nexusPropertiesFilename])))
+	// hash the properties rather than the file, so that formatting changes such as the order or the comments don't restart the pods
+	properties := util.FromMapToJavaProperties(util.FromJavaPropertiesToMap(configMap.Data[nexusPropertiesFilename]))
+	contentHash := fmt.Sprintf("%x", md5.Sum([]byte(properties)))
 	deployment.Spec.Template.Annotations = util.AppendToStringMap(deployment.Spec.Template.Annotations, configMapHashAnnotationKey, contentHash)
 	return nil
 }
+
+// applyKeystoreHash makes the pods restart when the keystore changes, the Nexus server only reads it on startup
+func (m *Manager) applyKeystoreHash(deployment *appsv1.Deployment) error {
+	if !ServerTLSEnabled(m.nexus) {
+		return nil
+	}
+	keystore := &corev1.Secret{}
+	if err := framework.Fetch(m.client, types.NamespacedName{Namespace: m.nexus.Namespace, Name: KeystoreSecretName(m.nexus)}, keystore, kind.SecretKind); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	contentHash := fmt.Sprintf("%x", md5.Sum(keystore.Data[KeystoreFilename]))
+	deployment.Spec.Template.Annotations = util.AppendToStringMap(deployment.Spec.Template.Annotations, keystoreHashAnnotationKey, contentHash)
+	return nil
+}
@@ -24,7 +24,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/validation"
@@ -77,6 +79,34 @@ func TestManager_GetRequiredResources(t *testing.T) {
 	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&corev1.ConfigMap{})))
 }
 
+func TestManager_GetRequiredResources_WithPatches(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Patches = []v1alpha1.ResourcePatch{
+		{Kind: v1alpha1.DeploymentPatchKind, Patch: `{"spec": {"template": {"spec": {"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["repo.internal"]}]}}}}`},
+		{Kind: v1alpha1.ServicePatchKind, Patch: `{"metadata": {"annotations": {"team": "platform"}}}`},
+	}
+	mgr := &Manager{
+		nexus:  nexus,
+		client: test.NewFakeClientBuilder().Build(),
+		log:    logger.GetLoggerWithResource("test", nexus),
+	}
+	resources, err := mgr.GetRequiredResources()
+	assert.Nil(t, err)
+
+	for _, res := range resources {
+		switch r := res.(type) {
+		case *appsv1.Deployment:
+			assert.Equal(t, []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"repo.internal"}}}, r.Spec.Template.Spec.HostAliases)
+			// the patched fields are compared on top of the ones set by the Operator
+			deployed := r.DeepCopy()
+			deployed.Spec.Template.Spec.HostAliases = nil
+			assert.False(t, mgr.GetCustomComparators()[reflect.TypeOf(appsv1.Deployment{})](deployed, r))
+		case *corev1.Service:
+			assert.Equal(t, "platform", r.Annotations["team"])
+		}
+	}
+}
+
 func TestManager_GetDeployedResources(t *testing.T) {
 	// first no deployed resources
 	fakeClient := test.NewFakeClientBuilder().Build()
@@ -117,11 +147,13 @@ func TestManager_GetCustomComparator(t *testing.T) {
 	// comparator functions offered by the manager
 	mgr := &Manager{}
 
-	// there is a custom comparator function for deployments, but not services
+	// there is a custom comparator function for deployments and services, but not persistent volume claims
 	deploymentComp := mgr.GetCustomComparator(reflect.TypeOf(&appsv1.Deployment{}))
 	assert.NotNil(t, deploymentComp)
 	svcComp := mgr.GetCustomComparator(reflect.TypeOf(&corev1.Service{}))
-	assert.Nil(t, svcComp)
+	assert.NotNil(t, svcComp)
+	pvcComp := mgr.GetCustomComparator(reflect.TypeOf(&corev1.PersistentVolumeClaim{}))
+	assert.Nil(t, pvcComp)
 }
 
 func TestManager_GetCustomComparators(t *testing.T) {
@@ -129,9 +161,9 @@ func TestManager_GetCustomComparators(t *testing.T) {
 	// comparator functions offered by the manager
 	mgr := &Manager{}
 
-	// there is a custom comparator for deployments
+	// there are custom comparators for deployments, config maps and services
 	comparators := mgr.GetCustomComparators()
-	assert.Len(t, comparators, 2)
+	assert.Len(t, comparators, 3)
 }
 
 func Test_deploymentEqual(t *testing.T) {
@@ -240,6 +272,56 @@ func Test_deploymentEqual(t *testing.T) {
 			baseDeployment.DeepCopy(),
 			false,
 		},
+		{
+			"Different node selector",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.NodeSelector = map[string]string{"node-role.kubernetes.io/storage": ""}
+				return d
+			}(),
+			baseDeployment.DeepCopy(),
+			false,
+		},
+		{
+			"Different affinity",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+				return d
+			}(),
+			baseDeployment.DeepCopy(),
+			false,
+		},
+		{
+			"Different tolerations",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+				return d
+			}(),
+			baseDeployment.DeepCopy(),
+			false,
+		},
+		{
+			"Different topology spread constraints",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone"}}
+				return d
+			}(),
+			baseDeployment.DeepCopy(),
+			false,
+		},
+		{
+			"Different priority class name",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.PriorityClassName = "high-priority"
+				return d
+			}(),
+			baseDeployment.DeepCopy(),
+			false,
+		},
 		{
 			"Different container name",
 			func() *appsv1.Deployment {
@@ -310,6 +392,151 @@ func Test_deploymentEqual(t *testing.T) {
 			baseDeployment.DeepCopy(),
 			false,
 		},
+		{
+			"oauth2-proxy sidecar removed",
+			baseDeployment.DeepCopy(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: v1alpha1.OAuth2ProxyContainerName, Image: "oauth2-proxy"})
+				return d
+			}(),
+			false,
+		},
+		{
+			"Different oauth2-proxy sidecar args",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: v1alpha1.OAuth2ProxyContainerName, Args: []string{"--provider=github"}})
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: v1alpha1.OAuth2ProxyContainerName, Args: []string{"--provider=oidc"}})
+				return d
+			}(),
+			false,
+		},
+		{
+			"oauth2-proxy sidecar with defaulted fields",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: v1alpha1.OAuth2ProxyContainerName})
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: v1alpha1.OAuth2ProxyContainerName, ImagePullPolicy: corev1.PullIfNotPresent, TerminationMessagePath: "/dev/termination-log"})
+				return d
+			}(),
+			true,
+		},
+		{
+			"Nexus container not the first one",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append([]corev1.Container{{Name: "log-shipper", Image: "fluent-bit"}}, d.Spec.Template.Spec.Containers...)
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append([]corev1.Container{{Name: "log-shipper", Image: "fluent-bit"}}, d.Spec.Template.Spec.Containers...)
+				return d
+			}(),
+			true,
+		},
+		{
+			"Sidecar removed",
+			baseDeployment.DeepCopy(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "log-shipper", Image: "fluent-bit"})
+				return d
+			}(),
+			false,
+		},
+		{
+			"Different sidecar env",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "log-shipper", Image: "fluent-bit", Env: []corev1.EnvVar{{Name: "LEVEL", Value: "debug"}}})
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "log-shipper", Image: "fluent-bit"})
+				return d
+			}(),
+			false,
+		},
+		{
+			"Sidecar with defaulted fields",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{
+					Name:           "log-shipper",
+					Image:          "fluent-bit",
+					Ports:          []corev1.ContainerPort{{ContainerPort: 2020}},
+					Resources:      corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")}},
+					LivenessProbe:  &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(2020)}}},
+					ReadinessProbe: &corev1.Probe{Handler: corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"true"}}}, PeriodSeconds: 5},
+				})
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{
+					Name:                     "log-shipper",
+					Image:                    "fluent-bit",
+					Ports:                    []corev1.ContainerPort{{ContainerPort: 2020, Protocol: corev1.ProtocolTCP}},
+					Resources:                corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")}, Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")}},
+					LivenessProbe:            &corev1.Probe{Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/", Port: intstr.FromInt(2020), Scheme: corev1.URISchemeHTTP}}, TimeoutSeconds: 1, PeriodSeconds: 10, SuccessThreshold: 1, FailureThreshold: 3},
+					ReadinessProbe:           &corev1.Probe{Handler: corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"true"}}}, TimeoutSeconds: 1, PeriodSeconds: 5, SuccessThreshold: 1, FailureThreshold: 3},
+					ImagePullPolicy:          corev1.PullAlways,
+					TerminationMessagePath:   corev1.TerminationMessagePathDefault,
+					TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+				})
+				return d
+			}(),
+			true,
+		},
+		{
+			"Different sidecar probe",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "log-shipper", ReadinessProbe: &corev1.Probe{PeriodSeconds: 5}})
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Name: "log-shipper", ReadinessProbe: &corev1.Probe{PeriodSeconds: 10}})
+				return d
+			}(),
+			false,
+		},
+		{
+			"Init container added",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "fix-permissions", Image: "busybox", Command: []string{"chown", "-R", "200:200", "/nexus-data"}}}
+				return d
+			}(),
+			baseDeployment.DeepCopy(),
+			false,
+		},
+		{
+			"Different init container command",
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "fix-permissions", Image: "busybox", Command: []string{"chown", "-R", "200:200", "/nexus-data"}}}
+				return d
+			}(),
+			func() *appsv1.Deployment {
+				d := baseDeployment.DeepCopy()
+				d.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "fix-permissions", Image: "busybox", Command: []string{"chown", "-R", "200", "/nexus-data"}}}
+				return d
+			}(),
+			false,
+		},
 		{
 			"Different field we don't care about (deployment strategy)",
 			func() *appsv1.Deployment {
@@ -381,3 +608,69 @@ func Test_configMapHash(t *testing.T) {
 	configMap := resources[0].(*corev1.ConfigMap)
 	assert.NotEmpty(t, configMap.Data[nexusPropertiesFilename])
 }
+
+func Test_configMapHash_IgnoresFormatting(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	hash := func(properties string) string {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: nexus.Name, Namespace: nexus.Namespace},
+			Data:       map[string]string{nexusPropertiesFilename: properties},
+		}
+		mgr := &Manager{nexus: nexus, client: test.NewFakeClientBuilder(nexus, configMap).Build()}
+		deployment := &appsv1.Deployment{}
+		assert.NoError(t, mgr.applyConfigMapPropertiesHash(deployment))
+		return deployment.Spec.Template.Annotations[configMapHashAnnotationKey]
+	}
+
+	original := hash("a=1\nb=2\n")
+	assert.Equal(t, original, hash("# reordered\nb : 2\na=1"))
+	assert.NotEqual(t, original, hash("a=1\nb=3\n"))
+}
+
+func Test_keystoreHash(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Networking = v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "nexus3-server-tls"}}
+	keystore := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: KeystoreSecretName(nexus), Namespace: nexus.Namespace},
+		Data:       map[string][]byte{KeystoreFilename: []byte("keystore")},
+	}
+	mgr := &Manager{
+		nexus:  nexus,
+		client: test.NewFakeClientBuilder(nexus).Build(),
+		log:    logger.GetLoggerWithResource("test", nexus),
+	}
+
+	// without the keystore yet
+	resources, err := mgr.GetRequiredResources()
+	assert.NoError(t, err)
+	withoutKeystore := resources[1].(*appsv1.Deployment).Spec.Template.Annotations[keystoreHashAnnotationKey]
+	assert.NotEmpty(t, withoutKeystore)
+
+	mgr.client = test.NewFakeClientBuilder(nexus, keystore).Build()
+	resources, err = mgr.GetRequiredResources()
+	assert.NoError(t, err)
+	withKeystore := resources[1].(*appsv1.Deployment).Spec.Template.Annotations[keystoreHashAnnotationKey]
+	assert.NotEqual(t, withoutKeystore, withKeystore)
+}
+
+func Test_serviceEqual(t *testing.T) {
+	nexus := allDefaultsCommunityNexus.DeepCopy()
+	nexus.Spec.Networking = v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType}
+	reqService := newService(nexus)
+
+	// the cluster allocates a node port for each port
+	depService := reqService.DeepCopy()
+	depService.Spec.ClusterIP = "10.0.0.1"
+	depService.Spec.Ports[0].NodePort = 31031
+	assert.True(t, serviceEqual(depService, reqService))
+	// the requested service is left untouched
+	assert.Equal(t, int32(0), reqService.Spec.Ports[0].NodePort)
+
+	// but it's replaced when switching to another type
+	nexus.Spec.Networking.ExposeAs = v1alpha1.RouteExposeType
+	assert.False(t, serviceEqual(depService, newService(nexus)))
+
+	// and the node port informed in the Nexus CR is still compared
+	nexus.Spec.Networking = v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType, NodePort: 31032}
+	assert.False(t, serviceEqual(depService, newService(nexus)))
+}
@@ -15,21 +15,73 @@
 package deployment
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/util"
 )
 
 const (
 	// NexusPortName is the name of the port on the service
 	NexusPortName = "http"
 	// DefaultHTTPPort is the default HTTP port
-	DefaultHTTPPort    = 80
-	nexusContainerPort = 8081
+	DefaultHTTPPort = 80
+	// NexusHTTPSPortName is the name of the HTTPS port on the service, opened when the Nexus server serves HTTPS
+	NexusHTTPSPortName = "https"
+	// DefaultHTTPSPort is the HTTPS port on the service
+	DefaultHTTPSPort        = 443
+	nexusContainerHTTPSPort = 8443
+	// OAuth2ProxyPortName is the name of the oauth2-proxy sidecar port on the service, targeted by the Ingress/Route when the sidecar is enabled
+	OAuth2ProxyPortName = "oauth2-proxy"
+	// OAuth2ProxyPort is the port of the oauth2-proxy sidecar, both in the container and on the service
+	OAuth2ProxyPort      = 4180
+	dockerPortNameFormat = "docker-%d" // port
+
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// Traefik and Contour learn from the service that the backend speaks HTTPS, not from the Ingress
+	traefikServersSchemeAnnotation = "traefik.ingress.kubernetes.io/service.serversscheme"
+	contourUpstreamTLSAnnotation   = "projectcontour.io/upstream-protocol.tls"
 )
 
+// ServerTLSEnabled checks if the Nexus server serves HTTPS, which is the case when the TLS connections are re-encrypted or passed through
+func ServerTLSEnabled(nexus *v1alpha1.Nexus) bool {
+	termination := nexus.Spec.Networking.TLS.Termination
+	return nexus.Spec.Networking.Expose && (termination == v1alpha1.ReencryptTLSTermination || termination == v1alpha1.PassthroughTLSTermination)
+}
+
+// DockerConnector is the HTTP connector opened by the Nexus server for a Docker repository
+type DockerConnector struct {
+	// Repository is the name of the Docker repository served by the connector
+	Repository string
+	// PortName is the name of the connector port in the pods and on the service
+	PortName string
+	// Port is the connector port, both in the container and on the service
+	Port int32
+	// Host is the dedicated host exposing the connector, if any
+	Host string
+}
+
+// DockerConnectors returns the HTTP connectors of the Docker repositories declared in the Nexus CR in the order they are declared
+func DockerConnectors(nexus *v1alpha1.Nexus) []DockerConnector {
+	var connectors []DockerConnector
+	for _, repo := range nexus.Spec.Repositories {
+		if repo.Format != v1alpha1.DockerRepositoryFormat || repo.Docker == nil || repo.Docker.HTTPPort == nil {
+			continue
+		}
+		connectors = append(connectors, DockerConnector{
+			Repository: repo.Name,
+			PortName:   fmt.Sprintf(dockerPortNameFormat, *repo.Docker.HTTPPort),
+			Port:       *repo.Docker.HTTPPort,
+			Host:       repo.Docker.Host,
+		})
+	}
+	return connectors
+}
+
 func newService(nexus *v1alpha1.Nexus) *corev1.Service {
 	svc := &corev1.Service{
 		ObjectMeta: meta.DefaultObjectMeta(nexus),
@@ -40,7 +92,7 @@ func newService(nexus *v1alpha1.Nexus) *corev1.Service {
 					Protocol: corev1.ProtocolTCP,
 					Port:     DefaultHTTPPort,
 					TargetPort: intstr.IntOrString{
-						IntVal: nexusContainerPort,
+						IntVal: nexus.HTTPPort(),
 					},
 				},
 			},
@@ -49,11 +101,82 @@ func newService(nexus *v1alpha1.Nexus) *corev1.Service {
 		},
 	}
 
+	// the operator keeps reaching the server directly through the first port, only the traffic from the Ingress/Route goes through the sidecar.
+	// The sidecar can't be used along with a NodePort or LoadBalancer service, which would expose the first port too.
+	if nexus.Spec.Security.OAuth2Proxy != nil {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:       OAuth2ProxyPortName,
+			Protocol:   corev1.ProtocolTCP,
+			Port:       OAuth2ProxyPort,
+			TargetPort: intstr.FromString(OAuth2ProxyPortName),
+		})
+	}
+
 	if nexus.Spec.Networking.ExposeAs == v1alpha1.NodePortExposeType {
 		svc.Spec.Type = corev1.ServiceTypeNodePort
 		svc.Spec.Ports[0].NodePort = nexus.Spec.Networking.NodePort
 		svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
 	}
 
+	if nexus.Spec.Networking.Expose && nexus.Spec.Networking.ExposeAs == v1alpha1.LoadBalancerExposeType {
+		exposeAsLoadBalancer(nexus, svc)
+	}
+
+	for _, connector := range DockerConnectors(nexus) {
+		svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+			Name:       connector.PortName,
+			Protocol:   corev1.ProtocolTCP,
+			Port:       connector.Port,
+			TargetPort: intstr.FromString(connector.PortName),
+		})
+	}
+
+	addHTTPSServicePort(nexus, svc)
+
 	return svc
 }
+
+// exposeAsLoadBalancer has the cluster provision a load balancer for the service, configured through the networking annotations
+func exposeAsLoadBalancer(nexus *v1alpha1.Nexus, svc *corev1.Service) {
+	svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+	svc.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	svc.Spec.LoadBalancerIP = nexus.Spec.Networking.LoadBalancerIP
+	svc.Spec.LoadBalancerSourceRanges = nexus.Spec.Networking.LoadBalancerSourceRanges
+	for key, value := range nexus.Spec.Networking.Annotations {
+		svc.Annotations = util.AppendToStringMap(svc.Annotations, key, value)
+	}
+}
+
+// ExposedPort is the port on the service receiving the exposed traffic, the oauth2-proxy sidecar one when enabled
+func ExposedPort(nexus *v1alpha1.Nexus) int32 {
+	if nexus.Spec.Security.OAuth2Proxy != nil {
+		return OAuth2ProxyPort
+	}
+	return DefaultHTTPPort
+}
+
+// addHTTPSServicePort adds the port receiving the re-encrypted or passed through TLS connections, along with the annotations telling how to reach it
+func addHTTPSServicePort(nexus *v1alpha1.Nexus, svc *corev1.Service) {
+	if !ServerTLSEnabled(nexus) {
+		return
+	}
+	svc.Spec.Ports = append(svc.Spec.Ports, corev1.ServicePort{
+		Name:       NexusHTTPSPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       DefaultHTTPSPort,
+		TargetPort: intstr.FromString(NexusHTTPSPortName),
+	})
+
+	if nexus.Spec.Networking.TLS.ServingCertificate {
+		svc.Annotations = util.AppendToStringMap(svc.Annotations, servingCertSecretAnnotation, nexus.Spec.Networking.TLS.ServerSecretName)
+	}
+	if nexus.Spec.Networking.ExposeAs != v1alpha1.IngressExposeType {
+		return
+	}
+	switch nexus.Spec.Networking.IngressProfile {
+	case v1alpha1.TraefikIngressProfile:
+		svc.Annotations = util.AppendToStringMap(svc.Annotations, traefikServersSchemeAnnotation, "https")
+	case v1alpha1.ContourIngressProfile:
+		svc.Annotations = util.AppendToStringMap(svc.Annotations, contourUpstreamTLSAnnotation, NexusHTTPSPortName)
+	}
+}
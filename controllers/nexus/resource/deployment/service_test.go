@@ -18,7 +18,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
@@ -45,3 +47,118 @@ func Test_newService(t *testing.T) {
 	assert.Equal(t, appName, svc.Labels[meta.AppLabel])
 	assert.Equal(t, appName, svc.Spec.Selector[meta.AppLabel])
 }
+
+func Test_newService_WithOAuth2ProxySidecar(t *testing.T) {
+	nexus := &v1alpha1.Nexus{
+		ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
+		Spec: v1alpha1.NexusSpec{
+			Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType},
+			Security:   v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{ClientSecretName: "oauth2-proxy"}},
+		},
+	}
+	svc := newService(nexus)
+
+	assert.Len(t, svc.Spec.Ports, 2)
+	// the operator keeps reaching the server directly, the service is left as ClusterIP
+	assert.Empty(t, svc.Spec.Type)
+	assert.Equal(t, NexusPortName, svc.Spec.Ports[0].Name)
+	assert.Equal(t, OAuth2ProxyPortName, svc.Spec.Ports[1].Name)
+	assert.Equal(t, int32(OAuth2ProxyPort), svc.Spec.Ports[1].Port)
+	assert.Equal(t, int32(OAuth2ProxyPort), ExposedPort(nexus))
+}
+
+func Test_newService_WithDockerConnectors(t *testing.T) {
+	port := int32(5000)
+	nexus := &v1alpha1.Nexus{
+		ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
+		Spec: v1alpha1.NexusSpec{
+			Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType, NodePort: 31031},
+			Repositories: []v1alpha1.Repository{
+				{Name: "maven-releases", Format: v1alpha1.MavenRepositoryFormat, Type: v1alpha1.HostedRepositoryType},
+				{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port, Host: "registry.example.com"}},
+			},
+		},
+	}
+	svc := newService(nexus)
+
+	assert.Len(t, svc.Spec.Ports, 2)
+	// the node port still goes to the HTTP port
+	assert.Equal(t, int32(31031), svc.Spec.Ports[0].NodePort)
+	assert.Equal(t, "docker-5000", svc.Spec.Ports[1].Name)
+	assert.Equal(t, port, svc.Spec.Ports[1].Port)
+	assert.Equal(t, intstr.FromString("docker-5000"), svc.Spec.Ports[1].TargetPort)
+}
+
+func TestDockerConnectors(t *testing.T) {
+	hostedPort, groupPort := int32(5000), int32(5001)
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Repositories: []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &hostedPort, Host: "push.example.com"}},
+		{Name: "docker-proxy", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.ProxyRepositoryType},
+		{Name: "docker-group", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.GroupRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &groupPort}},
+	}}}
+
+	assert.Equal(t, []DockerConnector{
+		{Repository: "docker-hosted", PortName: "docker-5000", Port: hostedPort, Host: "push.example.com"},
+		{Repository: "docker-group", PortName: "docker-5001", Port: groupPort},
+	}, DockerConnectors(nexus))
+	assert.Empty(t, DockerConnectors(&v1alpha1.Nexus{}))
+}
+
+func Test_newService_WithServerTLS(t *testing.T) {
+	nexus := &v1alpha1.Nexus{
+		ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
+		Spec: v1alpha1.NexusSpec{
+			Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{
+				Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "nexus3-server-tls", ServingCertificate: true,
+			}},
+		},
+	}
+	svc := newService(nexus)
+
+	assert.Len(t, svc.Spec.Ports, 2)
+	assert.Equal(t, NexusHTTPSPortName, svc.Spec.Ports[1].Name)
+	assert.Equal(t, int32(DefaultHTTPSPort), svc.Spec.Ports[1].Port)
+	assert.Equal(t, intstr.FromString(NexusHTTPSPortName), svc.Spec.Ports[1].TargetPort)
+	assert.Equal(t, "nexus3-server-tls", svc.Annotations[servingCertSecretAnnotation])
+
+	nexus.Spec.Networking.ExposeAs = v1alpha1.IngressExposeType
+	nexus.Spec.Networking.IngressProfile = v1alpha1.TraefikIngressProfile
+	nexus.Spec.Networking.TLS.ServingCertificate = false
+	svc = newService(nexus)
+	assert.Equal(t, map[string]string{traefikServersSchemeAnnotation: "https"}, svc.Annotations)
+
+	nexus.Spec.Networking.IngressProfile = v1alpha1.ContourIngressProfile
+	svc = newService(nexus)
+	assert.Equal(t, map[string]string{contourUpstreamTLSAnnotation: NexusHTTPSPortName}, svc.Annotations)
+}
+
+func Test_newService_WithLoadBalancer(t *testing.T) {
+	nexus := &v1alpha1.Nexus{
+		ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
+		Spec: v1alpha1.NexusSpec{
+			Networking: v1alpha1.NexusNetworking{
+				Expose:                   true,
+				ExposeAs:                 v1alpha1.LoadBalancerExposeType,
+				LoadBalancerIP:           "192.168.1.240",
+				LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+				Annotations:              map[string]string{"metallb.universe.tf/address-pool": "production"},
+			},
+		},
+	}
+	svc := newService(nexus)
+
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	assert.Equal(t, "192.168.1.240", svc.Spec.LoadBalancerIP)
+	assert.Equal(t, []string{"10.0.0.0/8"}, svc.Spec.LoadBalancerSourceRanges)
+	assert.Equal(t, map[string]string{"metallb.universe.tf/address-pool": "production"}, svc.Annotations)
+	assert.Equal(t, int32(DefaultHTTPPort), svc.Spec.Ports[0].Port)
+	// the Nexus CR is left untouched
+	svc.Annotations["other"] = "value"
+	assert.Len(t, nexus.Spec.Networking.Annotations, 1)
+
+	// the annotations only go to the service with a load balancer
+	nexus.Spec.Networking.ExposeAs = v1alpha1.RouteExposeType
+	svc = newService(nexus)
+	assert.Empty(t, svc.Annotations)
+	assert.Empty(t, svc.Spec.LoadBalancerIP)
+}
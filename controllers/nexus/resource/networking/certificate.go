@@ -0,0 +1,66 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networking
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
+	"github.com/m88i/nexus-operator/pkg/util"
+)
+
+// newCertificate builds the cert-manager Certificate for the hosts exposing the Nexus server and its Docker repositories.
+// It is expected that the Nexus has been previously validated, defaulting the issuer and the Secret name.
+func newCertificate(nexus *v1alpha1.Nexus) *unstructured.Unstructured {
+	objectMeta := meta.DefaultObjectMeta(nexus)
+	cert := certmanager.NewCertificate()
+	cert.SetName(objectMeta.Name)
+	cert.SetNamespace(objectMeta.Namespace)
+	cert.SetLabels(objectMeta.Labels)
+
+	issuerRef := nexus.Spec.Networking.TLS.CertManager.IssuerRef
+	cert.Object["spec"] = map[string]interface{}{
+		"secretName": nexus.Spec.Networking.TLS.SecretName,
+		"dnsNames":   toInterfaceSlice(certificateDNSNames(nexus)),
+		"issuerRef": map[string]interface{}{
+			"name":  issuerRef.Name,
+			"kind":  issuerRef.Kind,
+			"group": issuerRef.Group,
+		},
+	}
+	return cert
+}
+
+// certificateDNSNames lists every host the certificate must be valid for, the main one first
+func certificateDNSNames(nexus *v1alpha1.Nexus) []string {
+	hosts := NexusHosts(nexus)
+	for _, connector := range deployment.DockerConnectors(nexus) {
+		if len(connector.Host) > 0 && !util.ContainsString(hosts, connector.Host) {
+			hosts = append(hosts, connector.Host)
+		}
+	}
+	return hosts
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	s := make([]interface{}, len(values))
+	for i, v := range values {
+		s[i] = v
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
+)
+
+func TestNewCertificate(t *testing.T) {
+	nexus := nexusIngress.DeepCopy()
+	nexus.Spec.Networking.AdditionalHosts = []string{"nexus.internal.test.com"}
+	nexus.Spec.Networking.TLS.CertManager = &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt", Kind: certmanager.ClusterIssuerKind, Group: certmanager.GroupVersion.Group}}
+	port := int32(5000)
+	nexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port, Host: "registry.test.com"}},
+	}
+	cert := newCertificate(nexus)
+
+	assert.Equal(t, certmanager.CertificateGroupVersionKind, cert.GroupVersionKind())
+	assert.Equal(t, nexus.Name, cert.GetName())
+	assert.Equal(t, nexus.Namespace, cert.GetNamespace())
+	assert.Equal(t, nexus.Name, cert.GetLabels()[meta.AppLabel])
+
+	secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	assert.Equal(t, "test-tls", secretName)
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	assert.Equal(t, []string{"ingress.tls.test.com", "nexus.internal.test.com", "registry.test.com"}, dnsNames)
+	issuerRef, _, _ := unstructured.NestedStringMap(cert.Object, "spec", "issuerRef")
+	assert.Equal(t, map[string]string{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuerRef)
+}
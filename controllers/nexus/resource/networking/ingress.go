@@ -16,19 +16,31 @@ package networking
 
 import (
 	v1 "k8s.io/api/networking/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/util"
 )
 
 const (
-	ingressBasePath   = "/?(.*)"
-	ingressClassKey   = "kubernetes.io/ingress.class"
-	ingressClassNginx = "nginx"
-	nginxRewriteKey   = "nginx.ingress.kubernetes.io/rewrite-target"
-	nginxRewriteValue = "/$1"
+	ingressBasePath = "/"
+
+	nginxBodySizeKey          = "nginx.ingress.kubernetes.io/proxy-body-size"
+	nginxUnlimitedBodySize    = "0"
+	haproxyBodySizeKey        = "haproxy-ingress.github.io/proxy-body-size"
+	haproxyUnlimitedBodySize  = "unlimited"
+	contourResponseTimeoutKey = "projectcontour.io/response-timeout"
+	contourNoResponseTimeout  = "infinity"
+	albTargetTypeKey          = "alb.ingress.kubernetes.io/target-type"
+	albTargetTypeIP           = "ip"
+
+	nginxBackendProtocolKey   = "nginx.ingress.kubernetes.io/backend-protocol"
+	nginxBackendHTTPS         = "HTTPS"
+	haproxyBackendProtocolKey = "haproxy-ingress.github.io/backend-protocol"
+	haproxyBackendHTTPS       = "h1-ssl"
+	albBackendProtocolKey     = "alb.ingress.kubernetes.io/backend-protocol"
+	albBackendHTTPS           = "HTTPS"
 )
 
 // hack to take the address of v1.PathExactType
@@ -40,25 +52,31 @@ type ingressBuilder struct {
 }
 
 func newIngressBuilder(nexus *v1alpha1.Nexus) *ingressBuilder {
-	ingress := &v1.Ingress{
-		ObjectMeta: meta.DefaultNetworkingMeta(nexus),
-		Spec: v1.IngressSpec{
-			Rules: []v1.IngressRule{
-				{
-					Host: nexus.Spec.Networking.Host,
-					IngressRuleValue: v1.IngressRuleValue{
-						HTTP: &v1.HTTPIngressRuleValue{
-							Paths: []v1.HTTPIngressPath{
-								{
-									PathType: &pathTypePrefix,
-									Path:     ingressBasePath,
-									Backend: v1.IngressBackend{
-										Service: &v1.IngressServiceBackend{
-											Name: nexus.Name,
-											Port: v1.ServiceBackendPort{Number: deployment.DefaultHTTPPort},
-										},
-									},
-								},
+	ingress := &v1.Ingress{ObjectMeta: meta.DefaultNetworkingMeta(nexus)}
+	for _, host := range NexusHosts(nexus) {
+		ingress.Spec.Rules = append(ingress.Spec.Rules, newIngressRule(nexus, host, ingressPath(nexus), servicePort(nexus)))
+	}
+	if len(nexus.Spec.Networking.IngressClassName) > 0 {
+		ingress.Spec.IngressClassName = &nexus.Spec.Networking.IngressClassName
+	}
+	addDockerRules(nexus, ingress)
+	addProfileAnnotations(nexus, ingress)
+	return &ingressBuilder{Ingress: ingress, nexus: nexus}
+}
+
+func newIngressRule(nexus *v1alpha1.Nexus, host, path string, port int32) v1.IngressRule {
+	return v1.IngressRule{
+		Host: host,
+		IngressRuleValue: v1.IngressRuleValue{
+			HTTP: &v1.HTTPIngressRuleValue{
+				Paths: []v1.HTTPIngressPath{
+					{
+						PathType: &pathTypePrefix,
+						Path:     path,
+						Backend: v1.IngressBackend{
+							Service: &v1.IngressServiceBackend{
+								Name: nexus.Name,
+								Port: v1.ServiceBackendPort{Number: port},
 							},
 						},
 					},
@@ -66,8 +84,32 @@ func newIngressBuilder(nexus *v1alpha1.Nexus) *ingressBuilder {
 			},
 		},
 	}
-	addNginxAnnotations(ingress.ObjectMeta)
-	return &ingressBuilder{Ingress: ingress, nexus: nexus}
+}
+
+// ingressPath is the path routed to the Nexus server, its context path if any
+func ingressPath(nexus *v1alpha1.Nexus) string {
+	if contextPath := nexus.ContextPath(); len(contextPath) > 0 {
+		return contextPath
+	}
+	return ingressBasePath
+}
+
+// addDockerRules routes the dedicated host of each Docker repository to its HTTP connector on the service
+func addDockerRules(nexus *v1alpha1.Nexus, ingress *v1.Ingress) {
+	for _, connector := range deployment.DockerConnectors(nexus) {
+		if len(connector.Host) == 0 {
+			continue
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, newIngressRule(nexus, connector.Host, ingressBasePath, connector.Port))
+	}
+}
+
+// servicePort is the port on the service receiving the traffic: the HTTPS one when re-encrypting, the oauth2-proxy sidecar one when enabled
+func servicePort(nexus *v1alpha1.Nexus) int32 {
+	if deployment.ServerTLSEnabled(nexus) {
+		return deployment.DefaultHTTPSPort
+	}
+	return deployment.ExposedPort(nexus)
 }
 
 func (i *ingressBuilder) withCustomTLS() *ingressBuilder {
@@ -84,6 +126,17 @@ func (i *ingressBuilder) build() *v1.Ingress {
 	return i.Ingress
 }
 
+// NexusHosts returns the hosts where the Nexus server is exposed, the main one first
+func NexusHosts(nexus *v1alpha1.Nexus) []string {
+	hosts := []string{nexus.Spec.Networking.Host}
+	for _, host := range nexus.Spec.Networking.AdditionalHosts {
+		if len(host) > 0 && !util.ContainsString(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
 func hosts(rules []v1.IngressRule) []string {
 	var hosts []string
 	for _, rule := range rules {
@@ -92,11 +145,47 @@ func hosts(rules []v1.IngressRule) []string {
 	return hosts
 }
 
-func addNginxAnnotations(meta metav1.ObjectMeta) {
-	if meta.Annotations == nil {
-		meta.Annotations = make(map[string]string)
+// addProfileAnnotations tunes the Ingress for the controller serving it, without overriding the annotations set in the Nexus CR.
+// Artifacts can be large, so the controllers limiting the request body size or the time to upload it are told not to.
+// When re-encrypting, the controllers are told to reach the Nexus server over HTTPS. Traefik and Contour read it from the Service instead.
+func addProfileAnnotations(nexus *v1alpha1.Nexus, ingress *v1.Ingress) {
+	annotations := make(map[string]string)
+	maxBodySize := nexus.Spec.Networking.MaxBodySize
+	reencrypt := deployment.ServerTLSEnabled(nexus)
+	switch nexus.Spec.Networking.IngressProfile {
+	case v1alpha1.NginxIngressProfile:
+		annotations[nginxBodySizeKey] = stringOrDefault(maxBodySize, nginxUnlimitedBodySize)
+		if reencrypt {
+			annotations[nginxBackendProtocolKey] = nginxBackendHTTPS
+		}
+	case v1alpha1.HAProxyIngressProfile:
+		annotations[haproxyBodySizeKey] = stringOrDefault(maxBodySize, haproxyUnlimitedBodySize)
+		if reencrypt {
+			annotations[haproxyBackendProtocolKey] = haproxyBackendHTTPS
+		}
+	case v1alpha1.ContourIngressProfile:
+		annotations[contourResponseTimeoutKey] = contourNoResponseTimeout
+	case v1alpha1.ALBIngressProfile:
+		// the service isn't a NodePort, so the load balancer must target the pods
+		annotations[albTargetTypeKey] = albTargetTypeIP
+		if reencrypt {
+			annotations[albBackendProtocolKey] = albBackendHTTPS
+		}
+	}
+
+	if len(annotations) == 0 {
+		return
 	}
+	// the annotations may be the very map from the Nexus CR, which must be left untouched
+	for key, value := range ingress.Annotations {
+		annotations[key] = value
+	}
+	ingress.Annotations = annotations
+}
 
-	meta.Annotations[ingressClassKey] = ingressClassNginx
-	meta.Annotations[nginxRewriteKey] = nginxRewriteValue
+func stringOrDefault(value, defaultValue string) string {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	return value
 }
@@ -81,6 +81,109 @@ func TestNewIngressWithSecretName(t *testing.T) {
 	assertIngressSecretName(t, ingress)
 }
 
+func TestNewIngressWithOAuth2ProxySidecar(t *testing.T) {
+	nexus := nexusIngress.DeepCopy()
+	nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{ClientSecretName: "oauth2-proxy"}
+	ingress := newIngressBuilder(nexus).build()
+	assert.Equal(t, int32(deployment.OAuth2ProxyPort), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+}
+
+func TestNewIngressWithDockerConnectors(t *testing.T) {
+	nexus := nexusIngress.DeepCopy()
+	hostedPort, groupPort := int32(5000), int32(5001)
+	nexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &hostedPort, Host: "registry.test.com"}},
+		{Name: "docker-group", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.GroupRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &groupPort}},
+	}
+	ingress := newIngressBuilder(nexus).withCustomTLS().build()
+
+	// the connector without a host isn't exposed
+	assert.Len(t, ingress.Spec.Rules, 2)
+	rule := ingress.Spec.Rules[1]
+	assert.Equal(t, "registry.test.com", rule.Host)
+	assert.Equal(t, nexus.Name, rule.HTTP.Paths[0].Backend.Service.Name)
+	assert.Equal(t, hostedPort, rule.HTTP.Paths[0].Backend.Service.Port.Number)
+	assert.Equal(t, []string{nexus.Spec.Networking.Host, "registry.test.com"}, ingress.Spec.TLS[0].Hosts)
+}
+
+func TestNewIngressWithIngressClassName(t *testing.T) {
+	nexus := nexusIngress.DeepCopy()
+	nexus.Spec.Networking.IngressClassName = "traefik"
+	ingress := newIngressBuilder(nexus).build()
+	assert.Equal(t, "traefik", *ingress.Spec.IngressClassName)
+}
+
+func TestNewIngressWithProfiles(t *testing.T) {
+	tests := []struct {
+		profile         v1alpha1.NexusIngressProfile
+		maxBodySize     string
+		wantAnnotations map[string]string
+	}{
+		{v1alpha1.GenericIngressProfile, "", map[string]string{}},
+		{v1alpha1.TraefikIngressProfile, "1g", map[string]string{}},
+		{v1alpha1.NginxIngressProfile, "", map[string]string{nginxBodySizeKey: nginxUnlimitedBodySize}},
+		{v1alpha1.NginxIngressProfile, "1g", map[string]string{nginxBodySizeKey: "1g"}},
+		{v1alpha1.HAProxyIngressProfile, "", map[string]string{haproxyBodySizeKey: haproxyUnlimitedBodySize}},
+		{v1alpha1.ContourIngressProfile, "", map[string]string{contourResponseTimeoutKey: contourNoResponseTimeout}},
+		{v1alpha1.ALBIngressProfile, "", map[string]string{albTargetTypeKey: albTargetTypeIP}},
+	}
+	for _, tt := range tests {
+		nexus := nexusIngress.DeepCopy()
+		nexus.Spec.Networking.IngressProfile = tt.profile
+		nexus.Spec.Networking.MaxBodySize = tt.maxBodySize
+		ingress := newIngressBuilder(nexus).build()
+
+		tt.wantAnnotations["test-annotation"] = "enabled"
+		assert.Equal(t, tt.wantAnnotations, ingress.Annotations, string(tt.profile))
+		// the Nexus CR is left untouched
+		assert.Len(t, nexus.Spec.Networking.Annotations, 1)
+	}
+
+	// annotations from the Nexus CR take precedence
+	nexus := nexusIngress.DeepCopy()
+	nexus.Spec.Networking.IngressProfile = v1alpha1.NginxIngressProfile
+	nexus.Spec.Networking.Annotations[nginxBodySizeKey] = "10m"
+	ingress := newIngressBuilder(nexus).build()
+	assert.Equal(t, "10m", ingress.Annotations[nginxBodySizeKey])
+}
+
+func TestNewIngressWithServerTLS(t *testing.T) {
+	tests := []struct {
+		profile         v1alpha1.NexusIngressProfile
+		wantAnnotations map[string]string
+	}{
+		{v1alpha1.TraefikIngressProfile, map[string]string{}},
+		{v1alpha1.NginxIngressProfile, map[string]string{nginxBodySizeKey: nginxUnlimitedBodySize, nginxBackendProtocolKey: nginxBackendHTTPS}},
+		{v1alpha1.HAProxyIngressProfile, map[string]string{haproxyBodySizeKey: haproxyUnlimitedBodySize, haproxyBackendProtocolKey: haproxyBackendHTTPS}},
+		{v1alpha1.ALBIngressProfile, map[string]string{albTargetTypeKey: albTargetTypeIP, albBackendProtocolKey: albBackendHTTPS}},
+	}
+	for _, tt := range tests {
+		nexus := nexusIngress.DeepCopy()
+		nexus.Spec.Networking.IngressProfile = tt.profile
+		nexus.Spec.Networking.TLS.Termination = v1alpha1.ReencryptTLSTermination
+		ingress := newIngressBuilder(nexus).build()
+
+		tt.wantAnnotations["test-annotation"] = "enabled"
+		assert.Equal(t, tt.wantAnnotations, ingress.Annotations, string(tt.profile))
+		assert.Equal(t, int32(deployment.DefaultHTTPSPort), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+	}
+}
+
+func TestNewIngressWithAdditionalHostsAndContextPath(t *testing.T) {
+	nexus := nexusIngress.DeepCopy()
+	nexus.Spec.Networking.AdditionalHosts = []string{"nexus.internal.test.com", nexus.Spec.Networking.Host, ""}
+	nexus.Spec.Networking.ContextPath = "/nexus"
+	ingress := newIngressBuilder(nexus).withCustomTLS().build()
+
+	assert.Len(t, ingress.Spec.Rules, 2)
+	assert.Equal(t, []string{nexus.Spec.Networking.Host, "nexus.internal.test.com"}, hosts(ingress.Spec.Rules))
+	for _, rule := range ingress.Spec.Rules {
+		assert.Equal(t, "/nexus", rule.HTTP.Paths[0].Path)
+		assert.Equal(t, int32(deployment.DefaultHTTPPort), rule.HTTP.Paths[0].Backend.Service.Port.Number)
+	}
+	assert.Equal(t, []string{nexus.Spec.Networking.Host, "nexus.internal.test.com"}, ingress.Spec.TLS[0].Hosts)
+}
+
 func assertIngressBasic(t *testing.T, ingress *v1.Ingress) {
 	assert.Equal(t, nexusIngress.Name, ingress.Name)
 	assert.Equal(t, nexusIngress.Namespace, ingress.Namespace)
@@ -102,8 +205,9 @@ func assertIngressBasic(t *testing.T, ingress *v1.Ingress) {
 	assert.NotNil(t, path.Backend)
 	assert.Equal(t, int32(deployment.DefaultHTTPPort), path.Backend.Service.Port.Number)
 	assert.Equal(t, nexusIngress.Name, path.Backend.Service.Name)
-	assert.NotEmpty(t, ingress.Annotations[nginxRewriteKey])
-	assert.Equal(t, ingressClassNginx, ingress.Annotations[ingressClassKey])
+	// no controller specific configuration by default
+	assert.Nil(t, ingress.Spec.IngressClassName)
+	assert.Len(t, ingress.Annotations, 1)
 }
 
 func assertIngressSecretName(t *testing.T, ingress *v1.Ingress) {
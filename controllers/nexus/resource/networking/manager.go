@@ -15,16 +15,26 @@
 package networking
 
 import (
+	ctx "context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/RHsyseng/operator-utils/pkg/resource"
 	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
 	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/patch"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
 	"github.com/m88i/nexus-operator/pkg/cluster/discovery"
 	"github.com/m88i/nexus-operator/pkg/framework"
 	"github.com/m88i/nexus-operator/pkg/framework/kind"
@@ -44,8 +54,12 @@ type Manager struct {
 	log                 logger.Logger
 	managedObjectsRef   map[string]resource.KubernetesResource
 	shouldIgnoreUpdates bool
+	// tlsSecret holds the certificate issued by cert-manager, nil until it has been issued
+	tlsSecret *corev1.Secret
+	// serverSecret holds the certificate served by the Nexus server when re-encrypting, nil until it has been created
+	serverSecret *corev1.Secret
 
-	routeAvailable, ingressAvailable bool
+	routeAvailable, ingressAvailable, certManagerAvailable bool
 }
 
 // NewManager creates a networking resources manager
@@ -78,6 +92,16 @@ func NewManager(nexus *v1alpha1.Nexus, client client.Client) (*Manager, error) {
 		mgr.managedObjectsRef[kind.RouteKind] = &routev1.Route{}
 	}
 
+	certManagerAvailable, err := discovery.IsCertManagerAvailable()
+	if err != nil {
+		return nil, fmt.Errorf(discFailureFormat, "cert-manager certificates", err)
+	}
+
+	if certManagerAvailable {
+		mgr.certManagerAvailable = true
+		mgr.managedObjectsRef[kind.CertificateKind] = certmanager.NewCertificate()
+	}
+
 	mgr.shouldIgnoreUpdates = nexus.Spec.Networking.IgnoreUpdates
 
 	return mgr, nil
@@ -96,9 +120,22 @@ func (m *Manager) GetRequiredResources() ([]resource.KubernetesResource, error)
 			return nil, fmt.Errorf(resUnavailableFormat, "routes")
 		}
 
+		if err := m.fetchTLSSecret(); err != nil {
+			return nil, err
+		}
+		if err := m.fetchServerSecret(); err != nil {
+			return nil, err
+		}
+
 		m.log.Debug("Generating required resource", "kind", kind.RouteKind)
 		route := m.createRoute()
 		resources = append(resources, route)
+		for _, hostRoute := range m.createHostRoutes() {
+			resources = append(resources, hostRoute)
+		}
+		for _, dockerRoute := range m.createDockerRoutes() {
+			resources = append(resources, dockerRoute)
+		}
 
 	case v1alpha1.IngressExposeType:
 		if !m.ingressAvailable {
@@ -109,15 +146,107 @@ func (m *Manager) GetRequiredResources() ([]resource.KubernetesResource, error)
 		ingress := m.createIngress()
 		resources = append(resources, ingress)
 	}
+
+	for _, res := range resources {
+		if err := patch.Apply(m.nexus, res); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.nexus.Spec.Networking.TLS.CertManager != nil {
+		if !m.certManagerAvailable {
+			return nil, fmt.Errorf(resUnavailableFormat, "cert-manager certificates")
+		}
+
+		m.log.Debug("Generating required resource", "kind", kind.CertificateKind)
+		resources = append(resources, newCertificate(m.nexus))
+	}
 	return resources, nil
 }
 
-func (m *Manager) createRoute() *routev1.Route {
-	builder := newRouteBuilder(m.nexus)
+// fetchTLSSecret fetches the Secret where cert-manager stores the issued certificate, Routes can't reference it so its content is copied to them
+func (m *Manager) fetchTLSSecret() error {
+	m.tlsSecret = nil
+	if m.nexus.Spec.Networking.TLS.CertManager == nil {
+		return nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.nexus.Namespace, Name: m.nexus.Spec.Networking.TLS.SecretName}
+	if err := m.client.Get(ctx.TODO(), key, secret); err != nil {
+		if errors.IsNotFound(err) {
+			m.log.Info("Waiting for cert-manager to issue the certificate, the Routes won't serve it until then", "Secret", key.Name)
+			return nil
+		}
+		return fmt.Errorf("could not fetch %s (%s/%s): %v", kind.SecretKind, key.Namespace, key.Name, err)
+	}
+	m.tlsSecret = secret
+	return nil
+}
+
+// fetchServerSecret fetches the Secret with the certificate served by the Nexus server, re-encrypting Routes trust its certificate authority
+func (m *Manager) fetchServerSecret() error {
+	m.serverSecret = nil
+	if m.nexus.Spec.Networking.TLS.Termination != v1alpha1.ReencryptTLSTermination {
+		return nil
+	}
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.nexus.Namespace, Name: m.nexus.Spec.Networking.TLS.ServerSecretName}
+	if err := m.client.Get(ctx.TODO(), key, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("could not fetch %s (%s/%s): %v", kind.SecretKind, key.Namespace, key.Name, err)
+	}
+	m.serverSecret = secret
+	return nil
+}
+
+// withTLS sets the TLS configuration of a Route exposing the given host.
+// Only the Routes to the Nexus server honor the termination, the Docker connectors are always served in plain HTTP by the server.
+func (m *Manager) withTLS(builder *routeBuilder, host string, toServer bool) *routeBuilder {
+	termination := m.nexus.Spec.Networking.TLS.Termination
+	if toServer {
+		switch termination {
+		case v1alpha1.ReencryptTLSTermination:
+			builder = builder.withReencrypt(m.serverSecret)
+		case v1alpha1.PassthroughTLSTermination:
+			builder = builder.withPassthrough()
+		}
+	}
 	if m.nexus.Spec.Networking.TLS.Mandatory {
 		builder = builder.withRedirect()
 	}
-	return builder.build()
+	// the Nexus server presents its own certificate on passthrough Routes
+	if toServer && termination == v1alpha1.PassthroughTLSTermination {
+		return builder
+	}
+	// Routes without a host get one generated by the cluster, which isn't covered by the certificate
+	if m.tlsSecret != nil && len(host) > 0 {
+		builder = builder.withCertificate(m.tlsSecret)
+	}
+	return builder
+}
+
+func (m *Manager) createRoute() *routev1.Route {
+	return m.withTLS(newRouteBuilder(m.nexus), m.nexus.Spec.Networking.Host, true).build()
+}
+
+func (m *Manager) createHostRoutes() []*routev1.Route {
+	var routes []*routev1.Route
+	for _, host := range NexusHosts(m.nexus)[1:] {
+		m.log.Debug("Generating required resource", "kind", kind.RouteKind, "host", host)
+		routes = append(routes, m.withTLS(newHostRouteBuilder(m.nexus, host), host, true).build())
+	}
+	return routes
+}
+
+func (m *Manager) createDockerRoutes() []*routev1.Route {
+	var routes []*routev1.Route
+	for _, connector := range deployment.DockerConnectors(m.nexus) {
+		m.log.Debug("Generating required resource", "kind", kind.RouteKind, "repository", connector.Repository)
+		routes = append(routes, m.withTLS(newDockerRouteBuilder(m.nexus, connector), connector.Host, false).build())
+	}
+	return routes
 }
 
 func (m *Manager) createIngress() resource.KubernetesResource {
@@ -130,7 +259,33 @@ func (m *Manager) createIngress() resource.KubernetesResource {
 
 // GetDeployedResources returns the networking resources deployed on the cluster
 func (m *Manager) GetDeployedResources() ([]resource.KubernetesResource, error) {
-	return framework.FetchDeployedResources(m.managedObjectsRef, m.nexus, m.client)
+	resources, err := framework.FetchDeployedResources(m.managedObjectsRef, m.nexus, m.client)
+	if err != nil || !m.routeAvailable {
+		return resources, err
+	}
+
+	extraRoutes, err := m.fetchExtraRoutes()
+	if err != nil {
+		return nil, err
+	}
+	return append(resources, extraRoutes...), nil
+}
+
+// fetchExtraRoutes fetches the deployed Routes dedicated to the additional hosts and to the Docker repositories, including the ones no longer declared
+func (m *Manager) fetchExtraRoutes() ([]resource.KubernetesResource, error) {
+	routes := &routev1.RouteList{}
+	if err := m.client.List(ctx.TODO(), routes, client.InNamespace(m.nexus.Namespace), client.MatchingLabels(meta.GenerateLabels(m.nexus))); err != nil {
+		return nil, fmt.Errorf("could not fetch %s (%s/%s): %v", kind.RouteKind, m.nexus.Namespace, m.nexus.Name, err)
+	}
+
+	var resources []resource.KubernetesResource
+	prefix := fmt.Sprintf(hostRouteNameFormat, m.nexus.Name, "")
+	for i := range routes.Items {
+		if strings.HasPrefix(routes.Items[i].Name, prefix) {
+			resources = append(resources, &routes.Items[i])
+		}
+	}
+	return resources, nil
 }
 
 // GetCustomComparator returns the custom comp function used to compare a networking resource.
@@ -143,7 +298,14 @@ func (m *Manager) GetCustomComparator(t reflect.Type) func(deployed resource.Kub
 
 	switch t {
 	case reflect.TypeOf(&networkingv1.Ingress{}):
-		return ingressEqual
+		return patch.Comparator(m.nexus, t.Elem(), ingressEqual)
+	case reflect.TypeOf(&routev1.Route{}):
+		if patch.Targets(m.nexus, t.Elem()) {
+			return patch.Comparator(m.nexus, t.Elem(), compare.DefaultComparator().GetComparator(t.Elem()))
+		}
+		return nil
+	case reflect.TypeOf(&unstructured.Unstructured{}):
+		return certificateEqual
 	default:
 		return nil
 	}
@@ -155,14 +317,42 @@ func (m *Manager) GetCustomComparators() map[reflect.Type]func(deployed resource
 	if m.shouldIgnoreUpdates {
 		m.log.Debug("Nexus configured to ignore Networking updates, won't compare current state and desired state for Ingress/Route")
 		return map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool{
-			reflect.TypeOf(networkingv1.Ingress{}): framework.AlwaysTrueComparator(),
-			reflect.TypeOf(routev1.Route{}):        framework.AlwaysTrueComparator(),
+			reflect.TypeOf(networkingv1.Ingress{}):      framework.AlwaysTrueComparator(),
+			reflect.TypeOf(routev1.Route{}):             framework.AlwaysTrueComparator(),
+			reflect.TypeOf(unstructured.Unstructured{}): framework.AlwaysTrueComparator(),
 		}
 	}
 
-	return map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool{
-		reflect.TypeOf(networkingv1.Ingress{}): ingressEqual,
+	ingressType := reflect.TypeOf(networkingv1.Ingress{})
+	comparators := map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool{
+		ingressType: patch.Comparator(m.nexus, ingressType, ingressEqual),
+		reflect.TypeOf(unstructured.Unstructured{}): certificateEqual,
+	}
+	// Routes have no custom comparator, the patched fields are compared on top of the default one
+	if routeType := reflect.TypeOf(routev1.Route{}); patch.Targets(m.nexus, routeType) {
+		comparators[routeType] = patch.Comparator(m.nexus, routeType, compare.DefaultComparator().GetComparator(routeType))
 	}
+	return comparators
+}
+
+// certificateEqual compares only the Certificate fields set by the Operator, cert-manager keeps track of the rest
+func certificateEqual(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
+	cert1 := deployed.(*unstructured.Unstructured)
+	cert2 := requested.(*unstructured.Unstructured)
+	var pairs [][2]interface{}
+	pairs = append(pairs, [2]interface{}{cert1.GetName(), cert2.GetName()})
+	pairs = append(pairs, [2]interface{}{cert1.GetNamespace(), cert2.GetNamespace()})
+	for _, field := range []string{"secretName", "dnsNames", "issuerRef"} {
+		value1, _, _ := unstructured.NestedFieldNoCopy(cert1.Object, "spec", field)
+		value2, _, _ := unstructured.NestedFieldNoCopy(cert2.Object, "spec", field)
+		pairs = append(pairs, [2]interface{}{value1, value2})
+	}
+
+	equal := compare.EqualPairs(pairs)
+	if !equal {
+		logger.GetLogger("networking_manager").Info("Resources are not equal", "deployed", deployed, "requested", requested)
+	}
+	return equal
 }
 
 func ingressEqual(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
@@ -23,12 +23,16 @@ import (
 	"github.com/RHsyseng/operator-utils/pkg/resource"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
 	"github.com/m88i/nexus-operator/pkg/cluster/discovery"
 	"github.com/m88i/nexus-operator/pkg/framework/kind"
 	"github.com/m88i/nexus-operator/pkg/logger"
@@ -46,6 +50,7 @@ func TestNewManager(t *testing.T) {
 	k8sClient := test.NewFakeClientBuilder().Build()
 	k8sClientWithIngress := test.NewFakeClientBuilder().WithIngress().Build()
 	ocpClient := test.NewFakeClientBuilder().OnOpenshift().Build()
+	certManagerClient := test.NewFakeClientBuilder().WithIngress().WithCertManager().Build()
 
 	//default-setting logic is tested elsewhere
 	//so here we just check if the resulting manager took in the arguments correctly
@@ -88,6 +93,20 @@ func TestNewManager(t *testing.T) {
 			},
 			ocpClient,
 		},
+		{
+			"On Kubernetes with cert-manager",
+			&Manager{
+				nexus:                nodePortNexus,
+				routeAvailable:       false,
+				ingressAvailable:     true,
+				certManagerAvailable: true,
+				managedObjectsRef: map[string]resource.KubernetesResource{
+					kind.IngressKind:     &networkingv1.Ingress{},
+					kind.CertificateKind: certmanager.NewCertificate(),
+				},
+			},
+			certManagerClient,
+		},
 	}
 
 	for _, tt := range tests {
@@ -98,6 +117,7 @@ func TestNewManager(t *testing.T) {
 		assert.Equal(t, tt.want.nexus, got.nexus)
 		assert.Equal(t, tt.want.routeAvailable, got.routeAvailable)
 		assert.Equal(t, tt.want.ingressAvailable, got.ingressAvailable)
+		assert.Equal(t, tt.want.certManagerAvailable, got.certManagerAvailable)
 		assert.Equal(t, tt.want.managedObjectsRef, got.managedObjectsRef)
 	}
 
@@ -136,6 +156,29 @@ func TestManager_GetRequiredResources(t *testing.T) {
 	assert.Len(t, resources, 1)
 	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&routev1.Route{})))
 
+	// a route with a dedicated route per docker connector
+	port := int32(5000)
+	dockerNexus := routeNexus.DeepCopy()
+	dockerNexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port}},
+	}
+	mgr.nexus = dockerNexus
+	resources, err = mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "nexus3-docker-5000", resources[1].GetName())
+	assert.NotNil(t, resources[1].(*routev1.Route).Spec.TLS)
+
+	// a route per additional host
+	hostsNexus := routeNexus.DeepCopy()
+	hostsNexus.Spec.Networking.AdditionalHosts = []string{"nexus.internal.test.com", "nexus.external.test.com"}
+	mgr.nexus = hostsNexus
+	resources, err = mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 3)
+	assert.Equal(t, "nexus3-nexus.internal.test.com", resources[1].GetName())
+	assert.Equal(t, "nexus3-nexus.external.test.com", resources[2].GetName())
+
 	// still a route, but in a cluster without routes
 	mgr = &Manager{
 		nexus:  routeNexus,
@@ -158,6 +201,18 @@ func TestManager_GetRequiredResources(t *testing.T) {
 	assert.Len(t, resources, 1)
 	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&networkingv1.Ingress{})))
 
+	// an ingress with a certificate issued by cert-manager
+	certNexus := nexusIngress.DeepCopy()
+	certNexus.Spec.Networking.TLS.CertManager = &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt", Kind: certmanager.ClusterIssuerKind}}
+	mgr.nexus = certNexus
+	resources, err = mgr.GetRequiredResources()
+	assert.EqualError(t, err, fmt.Sprintf(resUnavailableFormat, "cert-manager certificates"))
+	mgr.certManagerAvailable = true
+	resources, err = mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 2)
+	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&unstructured.Unstructured{})))
+
 	// still an ingress, but in a cluster without ingresses
 	mgr = &Manager{
 		nexus:  nexusIngress,
@@ -169,6 +224,117 @@ func TestManager_GetRequiredResources(t *testing.T) {
 	assert.EqualError(t, err, fmt.Sprintf(resUnavailableFormat, "ingresses"))
 }
 
+func TestManager_GetRequiredResources_withCertManager(t *testing.T) {
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Networking.TLS.SecretName = "nexus3-tls"
+	nexus.Spec.Networking.TLS.CertManager = &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt", Kind: certmanager.ClusterIssuerKind}}
+	port := int32(5000)
+	nexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port}},
+	}
+	mgr := &Manager{
+		nexus:                nexus,
+		client:               test.NewFakeClientBuilder().OnOpenshift().WithCertManager().Build(),
+		log:                  logger.GetLoggerWithResource("test", nexus),
+		routeAvailable:       true,
+		certManagerAvailable: true,
+	}
+
+	// the certificate hasn't been issued yet
+	resources, err := mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 3)
+	assert.Empty(t, resources[0].(*routev1.Route).Spec.TLS.Certificate)
+	assert.Equal(t, kind.CertificateKind, resources[2].GetObjectKind().GroupVersionKind().Kind)
+
+	// now it has, the routes with a host serve it
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "nexus3-tls", Namespace: nexus.Namespace},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	assert.NoError(t, mgr.client.Create(ctx.TODO(), secret))
+	resources, err = mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 3)
+	route := resources[0].(*routev1.Route)
+	assert.Equal(t, "cert", route.Spec.TLS.Certificate)
+	assert.Equal(t, "key", route.Spec.TLS.Key)
+	assert.Equal(t, routev1.InsecureEdgeTerminationPolicyRedirect, route.Spec.TLS.InsecureEdgeTerminationPolicy)
+	// the docker route has no host, so it gets one from the cluster which isn't covered by the certificate
+	assert.Empty(t, resources[1].(*routev1.Route).Spec.TLS.Certificate)
+}
+
+func TestManager_GetRequiredResources_withServerTLS(t *testing.T) {
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Networking.TLS = v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.PassthroughTLSTermination, SecretName: "nexus3-tls", ServerSecretName: "nexus3-tls", CertManager: &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt"}}}
+	port := int32(5000)
+	nexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port, Host: "registry.test.com"}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "nexus3-tls", Namespace: nexus.Namespace},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key"), serverCAKey: []byte("ca")},
+	}
+	mgr := &Manager{
+		nexus:                nexus,
+		client:               test.NewFakeClientBuilder(secret).OnOpenshift().WithCertManager().Build(),
+		log:                  logger.GetLoggerWithResource("test", nexus),
+		routeAvailable:       true,
+		certManagerAvailable: true,
+	}
+
+	// the Nexus server presents the certificate itself
+	resources, err := mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	route := resources[0].(*routev1.Route)
+	assert.Equal(t, routev1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+	assert.Empty(t, route.Spec.TLS.Certificate)
+	// the docker connectors are still served in plain HTTP by the server
+	dockerRoute := resources[1].(*routev1.Route)
+	assert.Equal(t, routev1.TLSTerminationEdge, dockerRoute.Spec.TLS.Termination)
+	assert.Equal(t, "cert", dockerRoute.Spec.TLS.Certificate)
+
+	// the re-encrypting routes serve the certificate and trust the server one
+	nexus.Spec.Networking.TLS.Termination = v1alpha1.ReencryptTLSTermination
+	resources, err = mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	route = resources[0].(*routev1.Route)
+	assert.Equal(t, routev1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+	assert.Equal(t, "cert", route.Spec.TLS.Certificate)
+	assert.Equal(t, "ca", route.Spec.TLS.DestinationCACertificate)
+}
+
+func TestManager_GetRequiredResources_withPatches(t *testing.T) {
+	port := int32(5000)
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Repositories = []v1alpha1.Repository{
+		{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port}},
+	}
+	nexus.Spec.Patches = []v1alpha1.ResourcePatch{
+		{Kind: v1alpha1.RoutePatchKind, Patch: `{"metadata": {"annotations": {"haproxy.router.openshift.io/timeout": "5m"}}}`},
+		{Kind: v1alpha1.RoutePatchKind, Name: "nexus3-docker-5000", Patch: `{"metadata": {"annotations": {"haproxy.router.openshift.io/timeout": "30m"}}}`},
+	}
+	mgr := &Manager{
+		nexus:          nexus,
+		client:         test.NewFakeClientBuilder().OnOpenshift().Build(),
+		log:            logger.GetLoggerWithResource("test", nexus),
+		routeAvailable: true,
+	}
+	resources, err := mgr.GetRequiredResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 2)
+	assert.Equal(t, "5m", resources[0].GetAnnotations()["haproxy.router.openshift.io/timeout"])
+	assert.Equal(t, "30m", resources[1].GetAnnotations()["haproxy.router.openshift.io/timeout"])
+
+	// routes have no custom comparator unless patched
+	routeComp := mgr.GetCustomComparators()[reflect.TypeOf(routev1.Route{})]
+	assert.NotNil(t, routeComp)
+	deployed := resources[0].(*routev1.Route).DeepCopy()
+	assert.True(t, routeComp(deployed, resources[0]))
+	delete(deployed.Annotations, "haproxy.router.openshift.io/timeout")
+	assert.False(t, routeComp(deployed, resources[0]))
+}
+
 func TestManager_createRoute(t *testing.T) {
 	mgr := &Manager{nexus: &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{TLS: v1alpha1.NexusNetworkingTLS{}}}}}
 
@@ -229,6 +395,16 @@ func TestManager_GetDeployedResources(t *testing.T) {
 	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&routev1.Route{})))
 	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&networkingv1.Ingress{})))
 
+	// docker routes are fetched too, even if no longer declared, but not the routes of other apps
+	dockerRoute := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: mgr.nexus.Name + "-docker-5000", Namespace: mgr.nexus.Namespace, Labels: map[string]string{meta.AppLabel: mgr.nexus.Name}}}
+	assert.NoError(t, mgr.client.Create(ctx.TODO(), dockerRoute))
+	otherRoute := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: mgr.nexus.Name + "-docker-5001", Namespace: mgr.nexus.Namespace, Labels: map[string]string{meta.AppLabel: "other"}}}
+	assert.NoError(t, mgr.client.Create(ctx.TODO(), otherRoute))
+
+	resources, err = mgr.GetDeployedResources()
+	assert.Nil(t, err)
+	assert.Len(t, resources, 3)
+
 	// make the client return a mocked 500 response to test errors other than NotFound
 	mockErrorMsg := "mock 500"
 	fakeClient.SetMockErrorForOneRequest(errors.NewInternalError(fmt.Errorf(mockErrorMsg)))
@@ -246,6 +422,9 @@ func TestManager_GetCustomComparator(t *testing.T) {
 	// there is a custom comparator function for v1 ingresses
 	ingressComp := mgr.GetCustomComparator(reflect.TypeOf(&networkingv1.Ingress{}))
 	assert.NotNil(t, ingressComp)
+	// and for cert-manager certificates
+	certComp := mgr.GetCustomComparator(reflect.TypeOf(&unstructured.Unstructured{}))
+	assert.NotNil(t, certComp)
 }
 
 func TestManager_GetCustomComparator_shouldIgnoreUpdates(t *testing.T) {
@@ -262,17 +441,17 @@ func TestManager_GetCustomComparator_shouldIgnoreUpdates(t *testing.T) {
 func TestManager_GetCustomComparators(t *testing.T) {
 	mgr := &Manager{}
 
-	// there are two custom comparators (v1 ingress)
+	// there are two custom comparators (v1 ingress and cert-manager certificate)
 	comparators := mgr.GetCustomComparators()
-	assert.Len(t, comparators, 1)
+	assert.Len(t, comparators, 2)
 }
 
 func TestManager_GetCustomComparators_shouldIgnoreUpdates(t *testing.T) {
 	mgr := &Manager{shouldIgnoreUpdates: true, log: logger.GetLogger("test")}
 
-	// ingress and route need alwaysTrueComparator when ignoring updates
+	// ingress, route and certificate need alwaysTrueComparator when ignoring updates
 	comparators := mgr.GetCustomComparators()
-	assert.Len(t, comparators, 2)
+	assert.Len(t, comparators, 3)
 }
 
 func Test_ingressEqual(t *testing.T) {
@@ -365,3 +544,20 @@ func Test_ingressEqual(t *testing.T) {
 		}
 	}
 }
+
+func Test_certificateEqual(t *testing.T) {
+	nexus := nexusIngress.DeepCopy()
+	nexus.Spec.Networking.TLS.CertManager = &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt", Kind: certmanager.ClusterIssuerKind, Group: certmanager.GroupVersion.Group}}
+	requested := newCertificate(nexus)
+
+	// cert-manager adds its own fields, they're not compared
+	deployed := requested.DeepCopy()
+	deployed.SetResourceVersion("1")
+	assert.NoError(t, unstructured.SetNestedField(deployed.Object, "True", "status", "conditions"))
+	assert.NoError(t, unstructured.SetNestedField(deployed.Object, false, "spec", "isCA"))
+	assert.True(t, certificateEqual(deployed, requested))
+
+	// a new host
+	nexus.Spec.Networking.AdditionalHosts = []string{"nexus.internal.test.com"}
+	assert.False(t, certificateEqual(deployed, newCertificate(nexus)))
+}
@@ -15,6 +15,10 @@
 package networking
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	v1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -24,6 +28,14 @@ import (
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
 )
 
+const (
+	dockerRouteNamePrefixFormat = "%s-docker-" // nexus name
+	hostRouteNameFormat         = "%s-%s"      // nexus name, host
+
+	// serverCAKey is the key holding the certificate authority of the server certificate in "kubernetes.io/tls" Secrets
+	serverCAKey = "ca.crt"
+)
+
 var serviceKind = (&corev1.Service{}).GroupVersionKind().Kind
 
 type routeBuilder struct {
@@ -31,27 +43,114 @@ type routeBuilder struct {
 }
 
 func newRouteBuilder(nexus *v1alpha1.Nexus) *routeBuilder {
-	route := &v1.Route{
+	targetPort := deployment.NexusPortName
+	if deployment.ServerTLSEnabled(nexus) {
+		targetPort = deployment.NexusHTTPSPortName
+	} else if nexus.Spec.Security.OAuth2Proxy != nil {
+		targetPort = deployment.OAuth2ProxyPortName
+	}
+	route := newRoute(nexus, nexus.Spec.Networking.Host, targetPort)
+	route.Spec.Path = nexus.ContextPath()
+	return &routeBuilder{route}
+}
+
+// RouteNames returns the names of the Routes exposing the Nexus server, the one of the main host first
+func RouteNames(nexus *v1alpha1.Nexus) []string {
+	names := []string{nexus.Name}
+	for _, host := range NexusHosts(nexus)[1:] {
+		names = append(names, hostRouteName(nexus, host))
+	}
+	return names
+}
+
+func hostRouteName(nexus *v1alpha1.Nexus, host string) string {
+	return fmt.Sprintf(hostRouteNameFormat, nexus.Name, strings.ToLower(host))
+}
+
+// newHostRouteBuilder builds the Route exposing the Nexus server through one of the additional hosts
+func newHostRouteBuilder(nexus *v1alpha1.Nexus, host string) *routeBuilder {
+	builder := newRouteBuilder(nexus)
+	builder.Name = hostRouteName(nexus, host)
+	builder.Spec.Host = host
+	return builder
+}
+
+// newDockerRouteBuilder builds the Route dedicated to the HTTP connector of a Docker repository.
+// Docker clients can't go through the oauth2-proxy sidecar, so the connector port is always targeted directly.
+func newDockerRouteBuilder(nexus *v1alpha1.Nexus, connector deployment.DockerConnector) *routeBuilder {
+	route := newRoute(nexus, connector.Host, connector.PortName)
+	route.Name = dockerRouteName(nexus, connector)
+	return &routeBuilder{route}
+}
+
+func dockerRouteName(nexus *v1alpha1.Nexus, connector deployment.DockerConnector) string {
+	return dockerRouteNamePrefix(nexus) + strconv.Itoa(int(connector.Port))
+}
+
+func dockerRouteNamePrefix(nexus *v1alpha1.Nexus) string {
+	return fmt.Sprintf(dockerRouteNamePrefixFormat, nexus.Name)
+}
+
+func newRoute(nexus *v1alpha1.Nexus, host, targetPort string) *v1.Route {
+	return &v1.Route{
 		ObjectMeta: meta.DefaultNetworkingMeta(nexus),
 		Spec: v1.RouteSpec{
-			Host: nexus.Spec.Networking.Host,
+			Host: host,
 			To: v1.RouteTargetReference{
 				Kind: serviceKind,
 				Name: nexus.Name,
 			},
 			Port: &v1.RoutePort{
-				TargetPort: intstr.FromString(deployment.NexusPortName),
+				TargetPort: intstr.FromString(targetPort),
 			},
 		},
 	}
-	return &routeBuilder{route}
 }
 
 func (r *routeBuilder) withRedirect() *routeBuilder {
+	if r.Spec.TLS == nil {
+		r.Spec.TLS = &v1.TLSConfig{Termination: v1.TLSTerminationEdge}
+	}
+	r.Spec.TLS.InsecureEdgeTerminationPolicy = v1.InsecureEdgeTerminationPolicyRedirect
+	return r
+}
+
+// withReencrypt makes the Route open a new TLS connection to the Nexus server.
+// The server certificate is trusted through the "ca.crt" key of its Secret, if any. Service serving certificates are trusted by default.
+func (r *routeBuilder) withReencrypt(serverSecret *corev1.Secret) *routeBuilder {
+	r.Spec.TLS = &v1.TLSConfig{
+		Termination:                   v1.TLSTerminationReencrypt,
+		InsecureEdgeTerminationPolicy: v1.InsecureEdgeTerminationPolicyAllow,
+	}
+	if serverSecret != nil {
+		r.Spec.TLS.DestinationCACertificate = string(serverSecret.Data[serverCAKey])
+	}
+	return r
+}
+
+// withPassthrough makes the Route forward the TLS connections to the Nexus server as is.
+// Passthrough Routes can't serve plain HTTP, so insecure traffic is refused unless the Route has been set to redirect it.
+// The router can't read the path of the encrypted requests either, so the whole host is routed to the server, whatever its context path.
+func (r *routeBuilder) withPassthrough() *routeBuilder {
 	r.Spec.TLS = &v1.TLSConfig{
-		Termination:                   v1.TLSTerminationEdge,
-		InsecureEdgeTerminationPolicy: v1.InsecureEdgeTerminationPolicyRedirect,
+		Termination:                   v1.TLSTerminationPassthrough,
+		InsecureEdgeTerminationPolicy: v1.InsecureEdgeTerminationPolicyNone,
+	}
+	r.Spec.Path = ""
+	return r
+}
+
+// withCertificate makes the Route serve the certificate and key stored in the given "kubernetes.io/tls" Secret.
+// Insecure traffic is still allowed, unless the Route has been set to redirect it.
+func (r *routeBuilder) withCertificate(secret *corev1.Secret) *routeBuilder {
+	if r.Spec.TLS == nil {
+		r.Spec.TLS = &v1.TLSConfig{
+			Termination:                   v1.TLSTerminationEdge,
+			InsecureEdgeTerminationPolicy: v1.InsecureEdgeTerminationPolicyAllow,
+		}
 	}
+	r.Spec.TLS.Certificate = string(secret.Data[corev1.TLSCertKey])
+	r.Spec.TLS.Key = string(secret.Data[corev1.TLSPrivateKeyKey])
 	return r
 }
 
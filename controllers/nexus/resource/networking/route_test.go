@@ -76,6 +76,90 @@ func TestNewRouteWithRedirection(t *testing.T) {
 	assertRouteRedirection(t, route)
 }
 
+func TestNewRouteWithOAuth2ProxySidecar(t *testing.T) {
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{ClientSecretName: "oauth2-proxy"}
+	route := newRouteBuilder(nexus).build()
+	assert.Equal(t, intstr.FromString(deployment.OAuth2ProxyPortName), route.Spec.Port.TargetPort)
+}
+
+func TestNewDockerRoute(t *testing.T) {
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{ClientSecretName: "oauth2-proxy"}
+	connector := deployment.DockerConnector{Repository: "docker-hosted", PortName: "docker-5000", Port: 5000, Host: "registry.test.com"}
+	route := newDockerRouteBuilder(nexus, connector).withRedirect().build()
+
+	assert.Equal(t, "nexus3-docker-5000", route.Name)
+	assert.Equal(t, routeNexus.Namespace, route.Namespace)
+	assert.Equal(t, routeNexus.Name, route.Labels[meta.AppLabel])
+	assert.Equal(t, connector.Host, route.Spec.Host)
+	assert.Equal(t, routeService.Name, route.Spec.To.Name)
+	// docker clients don't go through the sidecar
+	assert.Equal(t, intstr.FromString(connector.PortName), route.Spec.Port.TargetPort)
+	assertRouteRedirection(t, route)
+}
+
+func TestNewHostRoute(t *testing.T) {
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Networking.AdditionalHosts = []string{"Nexus.Internal.test.com"}
+	nexus.Spec.Networking.ContextPath = "/nexus"
+	route := newHostRouteBuilder(nexus, "Nexus.Internal.test.com").build()
+
+	assert.Equal(t, "nexus3-nexus.internal.test.com", route.Name)
+	assert.Equal(t, "Nexus.Internal.test.com", route.Spec.Host)
+	assert.Equal(t, "/nexus", route.Spec.Path)
+	assert.Equal(t, intstr.FromString(deployment.NexusPortName), route.Spec.Port.TargetPort)
+	assert.Equal(t, []string{"nexus3", "nexus3-nexus.internal.test.com"}, RouteNames(nexus))
+}
+
+func TestNewRouteWithCertificate(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")}}
+
+	// the redirect is kept
+	route := newRouteBuilder(routeNexus).withRedirect().withCertificate(secret).build()
+	assertRouteRedirection(t, route)
+	assert.Equal(t, "cert", route.Spec.TLS.Certificate)
+	assert.Equal(t, "key", route.Spec.TLS.Key)
+
+	// insecure traffic is allowed otherwise
+	route = newRouteBuilder(routeNexus).withCertificate(secret).build()
+	assert.Equal(t, v1.TLSTerminationEdge, route.Spec.TLS.Termination)
+	assert.Equal(t, v1.InsecureEdgeTerminationPolicyAllow, route.Spec.TLS.InsecureEdgeTerminationPolicy)
+	assert.Equal(t, "cert", route.Spec.TLS.Certificate)
+	assert.Equal(t, "key", route.Spec.TLS.Key)
+}
+
+func TestNewRouteWithServerTLS(t *testing.T) {
+	nexus := routeNexus.DeepCopy()
+	nexus.Spec.Networking.TLS.Termination = v1alpha1.ReencryptTLSTermination
+	secret := &corev1.Secret{Data: map[string][]byte{serverCAKey: []byte("ca")}}
+
+	route := newRouteBuilder(nexus).withReencrypt(secret).build()
+	assert.Equal(t, intstr.FromString(deployment.NexusHTTPSPortName), route.Spec.Port.TargetPort)
+	assert.Equal(t, v1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+	assert.Equal(t, v1.InsecureEdgeTerminationPolicyAllow, route.Spec.TLS.InsecureEdgeTerminationPolicy)
+	assert.Equal(t, "ca", route.Spec.TLS.DestinationCACertificate)
+
+	// the termination is kept when redirecting
+	route = newRouteBuilder(nexus).withReencrypt(nil).withRedirect().build()
+	assert.Equal(t, v1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+	assert.Equal(t, v1.InsecureEdgeTerminationPolicyRedirect, route.Spec.TLS.InsecureEdgeTerminationPolicy)
+	assert.Empty(t, route.Spec.TLS.DestinationCACertificate)
+
+	nexus.Spec.Networking.TLS.Termination = v1alpha1.PassthroughTLSTermination
+	route = newRouteBuilder(nexus).withPassthrough().build()
+	assert.Equal(t, intstr.FromString(deployment.NexusHTTPSPortName), route.Spec.Port.TargetPort)
+	assert.Equal(t, v1.TLSTerminationPassthrough, route.Spec.TLS.Termination)
+	assert.Equal(t, v1.InsecureEdgeTerminationPolicyNone, route.Spec.TLS.InsecureEdgeTerminationPolicy)
+
+	// OpenShift rejects passthrough Routes with a path
+	nexus.Spec.Networking.ContextPath = "/nexus"
+	route = newRouteBuilder(nexus).withPassthrough().build()
+	assert.Empty(t, route.Spec.Path)
+	route = newHostRouteBuilder(nexus, "nexus.other.example.com").withPassthrough().build()
+	assert.Empty(t, route.Spec.Path)
+}
+
 func assertRouteBasic(t *testing.T, route *v1.Route) {
 	assert.Equal(t, routeNexus.Name, route.Name)
 	assert.Equal(t, routeNexus.Namespace, route.Namespace)
@@ -0,0 +1,150 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/RHsyseng/operator-utils/pkg/resource"
+	jsonpatch "github.com/evanphx/json-patch"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/logger"
+)
+
+var patchKinds = map[reflect.Type]v1alpha1.ResourcePatchKind{
+	reflect.TypeOf(appsv1.Deployment{}):    v1alpha1.DeploymentPatchKind,
+	reflect.TypeOf(corev1.Service{}):       v1alpha1.ServicePatchKind,
+	reflect.TypeOf(networkingv1.Ingress{}): v1alpha1.IngressPatchKind,
+	reflect.TypeOf(routev1.Route{}):        v1alpha1.RoutePatchKind,
+}
+
+// Apply applies the patches from the Nexus CR targeting the resource
+func Apply(nexus *v1alpha1.Nexus, res resource.KubernetesResource) error {
+	for _, p := range targeting(nexus, res) {
+		if err := apply(p, res); err != nil {
+			return fmt.Errorf("unable to apply the %s patch to %s: %v", p.Kind, res.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// Targets checks if any patch from the Nexus CR targets the given resource type
+func Targets(nexus *v1alpha1.Nexus, t reflect.Type) bool {
+	if nexus == nil {
+		return false
+	}
+	for _, p := range nexus.Spec.Patches {
+		if p.Kind == patchKinds[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// Comparator wraps the comparator of the given resource type so that the fields changed by the patches are also compared.
+// Patching the deployed resource must leave it untouched, otherwise the patches have not been applied yet.
+// Returns compFunc as is if no patch targets the type.
+func Comparator(nexus *v1alpha1.Nexus, t reflect.Type, compFunc func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool) func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
+	if !Targets(nexus, t) {
+		return compFunc
+	}
+	return func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
+		if !compFunc(deployed, requested) {
+			return false
+		}
+		patched := deployed.DeepCopyObject().(resource.KubernetesResource)
+		if err := Apply(nexus, patched); err != nil {
+			// the same patches were applied to the requested resource, this is unlikely to happen
+			logger.GetLogger("patch").Error(err, "Unable to patch the deployed resource", "name", deployed.GetName())
+			return false
+		}
+		equal := equality.Semantic.DeepEqual(deployed, patched)
+		if !equal {
+			logger.GetLogger("patch").Info("Patches not applied to the deployed resource", "name", deployed.GetName())
+		}
+		return equal
+	}
+}
+
+// Validate checks if the patch can be decoded
+func Validate(p v1alpha1.ResourcePatch) error {
+	patchJSON, err := toJSON(p)
+	if err != nil {
+		return err
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(patchJSON, &content); err != nil {
+		return fmt.Errorf("the patch must be an object: %v", err)
+	}
+	return nil
+}
+
+func targeting(nexus *v1alpha1.Nexus, res resource.KubernetesResource) []v1alpha1.ResourcePatch {
+	kind := patchKinds[reflect.ValueOf(res).Elem().Type()]
+	var patches []v1alpha1.ResourcePatch
+	for _, p := range nexus.Spec.Patches {
+		if p.Kind == kind && (len(p.Name) == 0 || p.Name == res.GetName()) {
+			patches = append(patches, p)
+		}
+	}
+	return patches
+}
+
+func apply(p v1alpha1.ResourcePatch, res resource.KubernetesResource) error {
+	patchJSON, err := toJSON(p)
+	if err != nil {
+		return err
+	}
+	original, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	if p.Type == v1alpha1.MergePatchType {
+		patched, err = jsonpatch.MergePatch(original, patchJSON)
+	} else {
+		patched, err = strategicpatch.StrategicMergePatch(original, patchJSON, reflect.New(reflect.ValueOf(res).Elem().Type()).Interface())
+	}
+	if err != nil {
+		return err
+	}
+
+	// decode into a blank object so that the fields removed by the patch are cleared
+	result := reflect.New(reflect.ValueOf(res).Elem().Type())
+	if err := json.Unmarshal(patched, result.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(res).Elem().Set(result.Elem())
+	return nil
+}
+
+func toJSON(p v1alpha1.ResourcePatch) ([]byte, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(p.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the patch: %v", err)
+	}
+	return patchJSON, nil
+}
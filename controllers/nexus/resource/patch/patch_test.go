@@ -0,0 +1,132 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package patch
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/RHsyseng/operator-utils/pkg/resource"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const sidecarPatch = `
+spec:
+  template:
+    spec:
+      containers:
+        - name: nexus
+          env:
+            - name: TZ
+              value: Europe/Rome
+        - name: logger
+          image: busybox
+`
+
+func newTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: "test"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nexus", Image: "sonatype/nexus3", Env: []corev1.EnvVar{{Name: "INSTALL4J_ADD_VM_PARAMS", Value: "-Xms2703m"}}}},
+				},
+			},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Patches: []v1alpha1.ResourcePatch{
+		{Kind: v1alpha1.DeploymentPatchKind, Patch: sidecarPatch},
+		{Kind: v1alpha1.ServicePatchKind, Type: v1alpha1.MergePatchType, Patch: `{"metadata": {"annotations": {"team": "platform"}}}`},
+		{Kind: v1alpha1.ServicePatchKind, Name: "other", Patch: `{"metadata": {"labels": {"team": "platform"}}}`},
+	}}}
+
+	deployment := newTestDeployment()
+	assert.NoError(t, Apply(nexus, deployment))
+	assert.Len(t, deployment.Spec.Template.Spec.Containers, 2)
+	assert.Equal(t, "sonatype/nexus3", deployment.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, []corev1.EnvVar{{Name: "TZ", Value: "Europe/Rome"}, {Name: "INSTALL4J_ADD_VM_PARAMS", Value: "-Xms2703m"}}, deployment.Spec.Template.Spec.Containers[0].Env)
+	assert.Equal(t, corev1.Container{Name: "logger", Image: "busybox"}, deployment.Spec.Template.Spec.Containers[1])
+
+	// the patch named after another service is left out
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: "test"}}
+	assert.NoError(t, Apply(nexus, service))
+	assert.Equal(t, map[string]string{"team": "platform"}, service.Annotations)
+	assert.Empty(t, service.Labels)
+}
+
+func TestApply_Removal(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Patches: []v1alpha1.ResourcePatch{
+		{Kind: v1alpha1.DeploymentPatchKind, Type: v1alpha1.MergePatchType, Patch: `{"spec": {"template": {"spec": {"containers": [{"name": "nexus", "image": "sonatype/nexus3"}]}}}}`},
+	}}}
+	deployment := newTestDeployment()
+	assert.NoError(t, Apply(nexus, deployment))
+	// merge patches replace the lists as a whole
+	assert.Empty(t, deployment.Spec.Template.Spec.Containers[0].Env)
+}
+
+func TestApply_InvalidPatch(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Patches: []v1alpha1.ResourcePatch{
+		{Kind: v1alpha1.DeploymentPatchKind, Patch: `{"spec": {"replicas": "two"}}`},
+	}}}
+	assert.Error(t, Apply(nexus, newTestDeployment()))
+}
+
+func TestComparator(t *testing.T) {
+	deploymentType := reflect.TypeOf(appsv1.Deployment{})
+	alwaysTrue := func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool { return true }
+
+	// without patches the comparator is left as is
+	nexus := &v1alpha1.Nexus{}
+	assert.Equal(t, reflect.ValueOf(alwaysTrue).Pointer(), reflect.ValueOf(Comparator(nexus, deploymentType, alwaysTrue)).Pointer())
+	assert.Equal(t, reflect.ValueOf(alwaysTrue).Pointer(), reflect.ValueOf(Comparator(nil, deploymentType, alwaysTrue)).Pointer())
+
+	nexus.Spec.Patches = []v1alpha1.ResourcePatch{{Kind: v1alpha1.DeploymentPatchKind, Patch: sidecarPatch}}
+	comparator := Comparator(nexus, deploymentType, alwaysTrue)
+	requested := newTestDeployment()
+	assert.NoError(t, Apply(nexus, requested))
+
+	// deployed before the patch was added
+	assert.False(t, comparator(newTestDeployment(), requested))
+
+	// deployed with the patch, the API server having defaulted some fields
+	deployed := requested.DeepCopy()
+	deployed.Spec.Template.Spec.Containers[1].TerminationMessagePath = corev1.TerminationMessagePathDefault
+	deployed.Spec.Template.Spec.Containers[1].ImagePullPolicy = corev1.PullAlways
+	deployed.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	assert.True(t, comparator(deployed, requested))
+
+	// the patched fields changed on the cluster
+	deployed.Spec.Template.Spec.Containers[1].Image = "alpine"
+	assert.False(t, comparator(deployed, requested))
+
+	// the wrapped comparator still applies
+	neverTrue := func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool { return false }
+	assert.False(t, Comparator(nexus, deploymentType, neverTrue)(requested.DeepCopy(), requested))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(v1alpha1.ResourcePatch{Patch: sidecarPatch}))
+	assert.NoError(t, Validate(v1alpha1.ResourcePatch{Patch: `{"spec": {"replicas": 2}}`}))
+	assert.Error(t, Validate(v1alpha1.ResourcePatch{Patch: `{"spec": `}))
+	assert.Error(t, Validate(v1alpha1.ResourcePatch{Patch: `[{"op": "add", "path": "/spec/replicas", "value": 2}]`}))
+}
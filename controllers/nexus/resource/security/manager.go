@@ -19,11 +19,14 @@ import (
 	"reflect"
 
 	"github.com/RHsyseng/operator-utils/pkg/resource"
+	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
 	"github.com/m88i/nexus-operator/pkg/framework"
 	"github.com/m88i/nexus-operator/pkg/framework/kind"
 	"github.com/m88i/nexus-operator/pkg/logger"
@@ -55,7 +58,35 @@ func NewManager(nexus *v1alpha1.Nexus, client client.Client) *Manager {
 func (m *Manager) GetRequiredResources() ([]resource.KubernetesResource, error) {
 	m.log.Debug("Generating required resource", "kind", kind.SvcAccountKind)
 	m.log.Debug("Generating required resource", "kind", kind.SecretKind)
-	return []resource.KubernetesResource{defaultServiceAccount(m.nexus), defaultSecret(m.nexus)}, nil
+	resources := []resource.KubernetesResource{defaultServiceAccount(m.nexus), defaultSecret(m.nexus)}
+
+	keystore, err := m.createKeystoreSecret()
+	if err != nil {
+		return nil, err
+	}
+	if keystore != nil {
+		resources = append(resources, keystore)
+	}
+	return resources, nil
+}
+
+// createKeystoreSecret generates the keystore served by the Nexus server from its TLS Secret, nil if the server doesn't serve HTTPS or the TLS Secret doesn't exist yet
+func (m *Manager) createKeystoreSecret() (*core.Secret, error) {
+	if !deployment.ServerTLSEnabled(m.nexus) {
+		return nil, nil
+	}
+	tlsSecret := &core.Secret{}
+	key := types.NamespacedName{Namespace: m.nexus.Namespace, Name: m.nexus.Spec.Networking.TLS.ServerSecretName}
+	if err := framework.Fetch(m.client, key, tlsSecret, kind.SecretKind); err != nil {
+		if errors.IsNotFound(err) {
+			m.log.Info("Waiting for the server TLS Secret to generate the keystore, the Nexus server won't start until then", "Secret", key.Name)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not fetch %s (%s/%s): %v", kind.SecretKind, key.Namespace, key.Name, err)
+	}
+
+	m.log.Debug("Generating required resource", "kind", kind.SecretKind, "name", deployment.KeystoreSecretName(m.nexus))
+	return newKeystoreSecret(m.nexus, tlsSecret)
 }
 
 // GetDeployedResources returns the security resources deployed on the cluster
@@ -68,6 +99,15 @@ func (m *Manager) GetDeployedResources() ([]resource.KubernetesResource, error)
 			return nil, fmt.Errorf("could not fetch %s (%s/%s): %v", resType, m.nexus.Namespace, m.nexus.Name, err)
 		}
 	}
+
+	// the keystore is fetched even if the server no longer serves HTTPS, so that it gets removed
+	keystore := &core.Secret{}
+	key := types.NamespacedName{Namespace: m.nexus.Namespace, Name: deployment.KeystoreSecretName(m.nexus)}
+	if err := framework.Fetch(m.client, key, keystore, kind.SecretKind); err == nil {
+		resources = append(resources, keystore)
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("could not fetch %s (%s/%s): %v", kind.SecretKind, key.Namespace, key.Name, err)
+	}
 	return resources, nil
 }
 
@@ -75,7 +115,7 @@ func (m *Manager) GetDeployedResources() ([]resource.KubernetesResource, error)
 // Returns nil if there is none
 func (m *Manager) GetCustomComparator(t reflect.Type) func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
 	if t == reflect.TypeOf(&core.Secret{}) {
-		return framework.AlwaysTrueComparator()
+		return secretEqual
 	}
 	return nil
 }
@@ -84,6 +124,20 @@ func (m *Manager) GetCustomComparator(t reflect.Type) func(deployed resource.Kub
 // Returns nil if there are none
 func (m *Manager) GetCustomComparators() map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
 	return map[reflect.Type]func(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool{
-		reflect.TypeOf(core.Secret{}): framework.AlwaysTrueComparator(),
+		reflect.TypeOf(core.Secret{}): secretEqual,
 	}
 }
+
+// secretEqual only compares the keystore generated by the Operator, the content of the other Secret is not managed by the Operator
+func secretEqual(deployed resource.KubernetesResource, requested resource.KubernetesResource) bool {
+	reqSecret := requested.(*core.Secret)
+	if _, ok := reqSecret.Data[deployment.KeystoreFilename]; !ok {
+		return true
+	}
+	depSecret := deployed.(*core.Secret)
+
+	var pairs [][2]interface{}
+	pairs = append(pairs, [2]interface{}{depSecret.Labels, reqSecret.Labels})
+	pairs = append(pairs, [2]interface{}{depSecret.Data, reqSecret.Data})
+	return compare.EqualPairs(pairs)
+}
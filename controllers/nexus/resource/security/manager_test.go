@@ -16,9 +16,17 @@ package security
 
 import (
 	ctx "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/m88i/nexus-operator/pkg/framework/kind"
 	"github.com/m88i/nexus-operator/pkg/logger"
@@ -29,6 +37,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
 	"github.com/m88i/nexus-operator/pkg/framework"
 	"github.com/m88i/nexus-operator/pkg/test"
 )
@@ -67,6 +76,59 @@ func TestManager_GetRequiredResources(t *testing.T) {
 	assert.True(t, test.ContainsType(resources, reflect.TypeOf(&corev1.Secret{})))
 }
 
+func newServerTLSSecret(t *testing.T, nexus *v1alpha1.Nexus) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: nexus.Name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: nexus.Spec.Networking.TLS.ServerSecretName, Namespace: nexus.Namespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}
+}
+
+func TestManager_GetRequiredResources_WithServerTLS(t *testing.T) {
+	nexus := baseNexus.DeepCopy()
+	nexus.Spec.Networking = v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "nexus-server-tls"}}
+	mgr := &Manager{
+		nexus:  nexus,
+		client: test.NewFakeClientBuilder().Build(),
+		log:    logger.GetLoggerWithResource("test", nexus),
+	}
+
+	// the keystore waits for the server TLS Secret
+	resources, err := mgr.GetRequiredResources()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 2)
+
+	mgr.client = test.NewFakeClientBuilder(newServerTLSSecret(t, nexus)).Build()
+	resources, err = mgr.GetRequiredResources()
+	assert.NoError(t, err)
+	assert.Len(t, resources, 3)
+	keystore := resources[2].(*corev1.Secret)
+	assert.Equal(t, deployment.KeystoreSecretName(nexus), keystore.Name)
+	assert.NotEmpty(t, keystore.Data[deployment.KeystoreFilename])
+
+	// a TLS Secret without a certificate can't make a keystore
+	invalid := newServerTLSSecret(t, nexus)
+	delete(invalid.Data, corev1.TLSCertKey)
+	mgr.client = test.NewFakeClientBuilder(invalid).Build()
+	_, err = mgr.GetRequiredResources()
+	assert.Error(t, err)
+}
+
 func TestManager_GetDeployedResources(t *testing.T) {
 	// first with no deployed resources
 	fakeClient := test.NewFakeClientBuilder().Build()
@@ -137,3 +199,18 @@ func TestManager_GetCustomComparators(t *testing.T) {
 	comparators := mgr.GetCustomComparators()
 	assert.Len(t, comparators, 1)
 }
+
+func Test_secretEqual(t *testing.T) {
+	// the default Secret is never compared
+	assert.True(t, secretEqual(&corev1.Secret{Data: map[string][]byte{"password": []byte("changed")}}, defaultSecret(baseNexus)))
+
+	keystore := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: deployment.KeystoreSecretName(baseNexus), Labels: map[string]string{"app": "nexus"}},
+		Data:       map[string][]byte{deployment.KeystoreFilename: []byte("keystore")},
+	}
+	assert.True(t, secretEqual(keystore.DeepCopy(), keystore))
+
+	renewed := keystore.DeepCopy()
+	renewed.Data[deployment.KeystoreFilename] = []byte("renewed")
+	assert.False(t, secretEqual(keystore, renewed))
+}
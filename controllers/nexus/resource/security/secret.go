@@ -15,10 +15,14 @@
 package security
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/keystore"
 )
 
 // defaultSecret all purposes secret used by this instance
@@ -28,3 +32,18 @@ func defaultSecret(nexus *v1alpha1.Nexus) *corev1.Secret {
 		Type:       corev1.SecretTypeOpaque,
 	}
 }
+
+// newKeystoreSecret holds the keystore generated from the certificate and private key of the server TLS Secret
+func newKeystoreSecret(nexus *v1alpha1.Nexus, tlsSecret *corev1.Secret) (*corev1.Secret, error) {
+	jks, err := keystore.JKSFromPEM(tlsSecret.Data[corev1.TLSCertKey], tlsSecret.Data[corev1.TLSPrivateKeyKey], deployment.KeystoreAlias, deployment.KeystorePassword)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the keystore from the Secret %s: %v", tlsSecret.Name, err)
+	}
+	objectMeta := meta.DefaultObjectMeta(nexus)
+	objectMeta.Name = deployment.KeystoreSecretName(nexus)
+	return &corev1.Secret{
+		ObjectMeta: objectMeta,
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{deployment.KeystoreFilename: jks},
+	}, nil
+}
@@ -28,6 +28,10 @@ const (
 
 	DefaultVolumeSize = "10Gi"
 
+	DefaultOAuth2ProxyImage       = "quay.io/oauth2-proxy/oauth2-proxy:v7.1.3"
+	defaultOAuth2ProxyProvider    = "oidc"
+	defaultOAuth2ProxyEmailDomain = "*"
+
 	probeDefaultInitialDelaySeconds = int32(240)
 	probeDefaultTimeoutSeconds      = int32(15)
 	probeDefaultPeriodSeconds       = int32(10)
@@ -35,9 +39,36 @@ const (
 	probeDefaultFailureThreshold    = int32(3)
 
 	maxReplicas = int32(1)
+
+	// the heap and the direct memory leave a quarter of the memory limit to the rest of the JVM, e.g. metaspace and thread stacks
+	jvmDefaultHeapPercentage         = int32(50)
+	jvmDefaultDirectMemoryPercentage = int32(25)
+	jvmMaxMemoryPercentage           = int32(90)
+
+	defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+	// the Ingresses were served by the nginx controller through this annotation before IngressClasses were supported
+	legacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+	legacyIngressClass           = "nginx"
+
+	certManagerSecretNameFormat = "%s-tls"
+	servingCertSecretNameFormat = "%s-server-tls"
 )
 
 var (
+	// ingressControllerProfiles maps the controllers of the IngressClasses to the Ingress profiles tuned for them
+	ingressControllerProfiles = map[string]v1alpha1.NexusIngressProfile{
+		"k8s.io/ingress-nginx":                 v1alpha1.NginxIngressProfile,
+		"traefik.io/ingress-controller":        v1alpha1.TraefikIngressProfile,
+		"haproxy-ingress.github.io/controller": v1alpha1.HAProxyIngressProfile,
+		"projectcontour.io/ingress-controller": v1alpha1.ContourIngressProfile,
+		"ingress.k8s.aws/alb":                  v1alpha1.ALBIngressProfile,
+	}
+
+	// headers holding the user ID sent by oauth2-proxy to the upstream server
+	oauth2ProxyUserHeaders = []string{v1alpha1.DefaultRUTAuthHeader, "X-Forwarded-Preferred-Username", "X-Forwarded-Email"}
+
+	minJVMMemorySize = k8sres.MustParse("1Mi")
+
 	DefaultResources = corev1.ResourceRequirements{
 		Limits: corev1.ResourceList{
 			corev1.ResourceCPU:    k8sres.MustParse("2"),
@@ -57,6 +88,13 @@ var (
 		FailureThreshold:    probeDefaultFailureThreshold,
 	}
 
+	heapPercentage, directMemoryPercentage = jvmDefaultHeapPercentage, jvmDefaultDirectMemoryPercentage
+
+	DefaultJVM = v1alpha1.NexusJVM{
+		HeapPercentage:         &heapPercentage,
+		DirectMemoryPercentage: &directMemoryPercentage,
+	}
+
 	DefaultPersistence = v1alpha1.NexusPersistence{
 		Persistent:   false,
 		VolumeSize:   DefaultVolumeSize,
@@ -86,6 +124,7 @@ var (
 			ImagePullPolicy:             "",
 			AutomaticUpdate:             DefaultUpdate,
 			Resources:                   DefaultResources,
+			JVM:                         *DefaultJVM.DeepCopy(),
 			Persistence:                 DefaultPersistence,
 			UseRedHatImage:              false,
 			GenerateRandomAdminPassword: false,
@@ -15,17 +15,26 @@
 package validation
 
 import (
+	ctx "context"
 	"fmt"
+	"net"
+	"path"
+	"path/filepath"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sres "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/patch"
 	"github.com/m88i/nexus-operator/controllers/nexus/update"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
 	"github.com/m88i/nexus-operator/pkg/cluster/discovery"
 	"github.com/m88i/nexus-operator/pkg/logger"
+	"github.com/m88i/nexus-operator/pkg/util"
 )
 
 const (
@@ -34,11 +43,22 @@ const (
 	unspecifiedExposeAsFormat = "'spec.exposeAs' left unspecified, setting to: "
 )
 
+// reservedDockerPorts are the ports already taken in the pods or on the service, which can't be used by Docker connectors.
+// The Nexus server HTTP port is added by reservedPorts since it's configurable.
+var reservedDockerPorts = map[int32]string{
+	80:   "service HTTP port",
+	443:  "service HTTPS port",
+	8443: "Nexus server HTTPS port",
+	4180: "oauth2-proxy sidecar port",
+}
+
+const nexusHTTPPortName = "Nexus server HTTP port"
+
 type Validator struct {
-	client                                client.Client
-	scheme                                *runtime.Scheme
-	log                                   logger.Logger
-	routeAvailable, ingressAvailable, ocp bool
+	client                                                      client.Client
+	scheme                                                      *runtime.Scheme
+	log                                                         logger.Logger
+	routeAvailable, ingressAvailable, certManagerAvailable, ocp bool
 }
 
 // NewValidator creates a new validator to set defaults, validate and update the Nexus CR
@@ -53,17 +73,23 @@ func NewValidator(client client.Client, scheme *runtime.Scheme) (*Validator, err
 		return nil, fmt.Errorf(discFailureFormat, "ingresses", err)
 	}
 
+	certManagerAvailable, err := discovery.IsCertManagerAvailable()
+	if err != nil {
+		return nil, fmt.Errorf(discFailureFormat, "cert-manager certificates", err)
+	}
+
 	ocp, err := discovery.IsOpenShift()
 	if err != nil {
 		return nil, fmt.Errorf(discOCPFailureFormat, err)
 	}
 
 	return &Validator{
-		client:           client,
-		scheme:           scheme,
-		routeAvailable:   routeAvailable,
-		ingressAvailable: ingressAvailable,
-		ocp:              ocp,
+		client:               client,
+		scheme:               scheme,
+		routeAvailable:       routeAvailable,
+		ingressAvailable:     ingressAvailable,
+		certManagerAvailable: certManagerAvailable,
+		ocp:                  ocp,
 	}, nil
 }
 
@@ -75,7 +101,25 @@ func (v *Validator) SetDefaultsAndValidate(nexus *v1alpha1.Nexus) (*v1alpha1.Nex
 }
 
 func (v *Validator) validate(nexus *v1alpha1.Nexus) error {
-	return v.validateNetworking(nexus)
+	if err := v.validateNetworking(nexus); err != nil {
+		return err
+	}
+	if err := v.validateConfiguration(nexus); err != nil {
+		return err
+	}
+	if err := v.validateDockerConnectors(nexus); err != nil {
+		return err
+	}
+	if err := v.validateJVM(nexus); err != nil {
+		return err
+	}
+	if err := v.validateContainers(nexus); err != nil {
+		return err
+	}
+	if err := v.validatePatches(nexus); err != nil {
+		return err
+	}
+	return v.validateSecurity(nexus)
 }
 
 func (v *Validator) validateNetworking(nexus *v1alpha1.Nexus) error {
@@ -99,16 +143,47 @@ func (v *Validator) validateNetworking(nexus *v1alpha1.Nexus) error {
 		return fmt.Errorf("nodeport expose required, but no port informed")
 	}
 
+	if nexus.Spec.Networking.ExposeAs == v1alpha1.NodePortExposeType && trustsRemoteUserHeader(nexus) {
+		v.log.Warn("NodePort networking opens the Nexus server HTTP port on every node, where anyone could log in as any user by setting the Remote User Token header. Try setting ", "spec.networking.exposeAs", v1alpha1.IngressExposeType)
+		return fmt.Errorf("nodeport expose required, but the Nexus server trusts the remote user header")
+	}
+
+	if (nexus.Spec.Networking.ExposeAs == v1alpha1.NodePortExposeType || nexus.Spec.Networking.ExposeAs == v1alpha1.LoadBalancerExposeType) && len(nexus.Spec.Networking.AdditionalHosts) > 0 {
+		v.log.Warn("'spec.networking.additionalHosts' is only available when using an Ingress or a Route, ignoring it")
+	}
+
+	if err := v.validateLoadBalancer(nexus); err != nil {
+		return err
+	}
+
+	if _, ok := nexus.Spec.Networking.Annotations[legacyIngressClassAnnotation]; ok && nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType && len(nexus.Spec.Networking.IngressClassName) > 0 {
+		v.log.Warn("The IngressClass can't be set along with the legacy annotation. Remove it from the Nexus resource 'spec.networking.annotations' parameter", "Annotation", legacyIngressClassAnnotation)
+		return fmt.Errorf("'spec.networking.ingressClassName' can't be set along with the %s annotation", legacyIngressClassAnnotation)
+	}
+
 	if nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType && len(nexus.Spec.Networking.Host) == 0 {
 		v.log.Warn("Ingress networking requires a host. Check the Nexus resource 'spec.networking.host' parameter")
 		return fmt.Errorf("ingress expose required, but no host informed")
 	}
 
-	if len(nexus.Spec.Networking.TLS.SecretName) > 0 && nexus.Spec.Networking.ExposeAs != v1alpha1.IngressExposeType {
+	if len(nexus.Spec.Networking.TLS.SecretName) > 0 && nexus.Spec.Networking.ExposeAs != v1alpha1.IngressExposeType && nexus.Spec.Networking.TLS.CertManager == nil {
 		v.log.Warn("'spec.networking.tls.secretName' is only available when using an Ingress. Try setting ", "spec.networking.exposeAs'", v1alpha1.IngressExposeType)
 		return fmt.Errorf("tls secret name informed, but using route")
 	}
 
+	if len(nexus.Spec.Networking.MaxBodySize) > 0 && nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType &&
+		nexus.Spec.Networking.IngressProfile != v1alpha1.NginxIngressProfile && nexus.Spec.Networking.IngressProfile != v1alpha1.HAProxyIngressProfile {
+		v.log.Warn("'spec.networking.maxBodySize' is not supported by the Ingress profile and won't be applied. Try setting it through 'spec.networking.annotations'", "Profile", nexus.Spec.Networking.IngressProfile)
+	}
+
+	if err := v.validateCertManager(nexus); err != nil {
+		return err
+	}
+
+	if err := v.validateServerTLS(nexus); err != nil {
+		return err
+	}
+
 	if nexus.Spec.Networking.TLS.Mandatory && nexus.Spec.Networking.ExposeAs != v1alpha1.RouteExposeType {
 		v.log.Warn("'spec.networking.tls.mandatory' is only available when using a Route. Try setting ", "spec.networking.exposeAs'", v1alpha1.RouteExposeType)
 		return fmt.Errorf("tls set to mandatory, but using ingress")
@@ -117,6 +192,284 @@ func (v *Validator) validateNetworking(nexus *v1alpha1.Nexus) error {
 	return nil
 }
 
+// trustsRemoteUserHeader checks if the Remote User Token realm is enabled, either directly or by the oauth2-proxy sidecar
+func trustsRemoteUserHeader(nexus *v1alpha1.Nexus) bool {
+	return nexus.Spec.Security.OAuth2Proxy != nil || nexus.Spec.Security.RUTAuth != nil
+}
+
+func (v *Validator) validateLoadBalancer(nexus *v1alpha1.Nexus) error {
+	networking := nexus.Spec.Networking
+	if networking.ExposeAs != v1alpha1.LoadBalancerExposeType {
+		if len(networking.LoadBalancerIP) > 0 || len(networking.LoadBalancerSourceRanges) > 0 {
+			v.log.Warn("'spec.networking.loadBalancerIP' and 'spec.networking.loadBalancerSourceRanges' are only available when using a LoadBalancer, ignoring them")
+		}
+		return nil
+	}
+
+	if len(networking.LoadBalancerIP) > 0 && net.ParseIP(networking.LoadBalancerIP) == nil {
+		v.log.Warn("Invalid load balancer IP. Check the Nexus resource 'spec.networking.loadBalancerIP' parameter", "IP", networking.LoadBalancerIP)
+		return fmt.Errorf("loadbalancer expose required, but the informed ip is invalid: %s", networking.LoadBalancerIP)
+	}
+
+	for _, sourceRange := range networking.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			v.log.Warn("Invalid load balancer source range, it must be a CIDR such as 10.0.0.0/8. Check the Nexus resource 'spec.networking.loadBalancerSourceRanges' parameter", "SourceRange", sourceRange)
+			return fmt.Errorf("loadbalancer expose required, but the informed source range is invalid: %s", sourceRange)
+		}
+	}
+
+	if trustsRemoteUserHeader(nexus) {
+		v.log.Warn("The load balancer would expose the Nexus server HTTP port, where anyone could log in as any user by setting the Remote User Token header. Try setting ", "spec.networking.exposeAs", v1alpha1.IngressExposeType)
+		return fmt.Errorf("loadbalancer expose required, but the Nexus server trusts the remote user header")
+	}
+	return nil
+}
+
+func (v *Validator) validateCertManager(nexus *v1alpha1.Nexus) error {
+	certManager := nexus.Spec.Networking.TLS.CertManager
+	if certManager == nil {
+		return nil
+	}
+
+	if !v.certManagerAvailable {
+		v.log.Warn("cert-manager is not available on your cluster. Install it or provide the TLS Secret with 'spec.networking.tls.secretName'")
+		return fmt.Errorf("cert-manager certificate required, but cert-manager is unavailable")
+	}
+
+	if nexus.Spec.Networking.ExposeAs == v1alpha1.NodePortExposeType || nexus.Spec.Networking.ExposeAs == v1alpha1.LoadBalancerExposeType {
+		v.log.Warn("'spec.networking.tls.certManager' is only available when using an Ingress or a Route. Try setting ", "spec.networking.exposeAs'", v1alpha1.IngressExposeType)
+		return fmt.Errorf("cert-manager certificate required, but using %s", strings.ToLower(string(nexus.Spec.Networking.ExposeAs)))
+	}
+
+	if len(certManager.IssuerRef.Name) == 0 {
+		v.log.Warn("cert-manager requires an issuer. Check the Nexus resource 'spec.networking.tls.certManager.issuerRef.name' parameter")
+		return fmt.Errorf("cert-manager certificate required, but no issuer informed")
+	}
+
+	if len(nexus.Spec.Networking.Host) == 0 {
+		v.log.Warn("cert-manager requires a host to issue the certificate for. Check the Nexus resource 'spec.networking.host' parameter")
+		return fmt.Errorf("cert-manager certificate required, but no host informed")
+	}
+	return nil
+}
+
+func (v *Validator) validateServerTLS(nexus *v1alpha1.Nexus) error {
+	tls := nexus.Spec.Networking.TLS
+	if tls.Termination != v1alpha1.ReencryptTLSTermination && tls.Termination != v1alpha1.PassthroughTLSTermination {
+		return nil
+	}
+
+	if nexus.Spec.Networking.ExposeAs == v1alpha1.NodePortExposeType || nexus.Spec.Networking.ExposeAs == v1alpha1.LoadBalancerExposeType {
+		v.log.Warn("'spec.networking.tls.termination' is only available when using an Ingress or a Route. Try setting ", "spec.networking.exposeAs'", v1alpha1.RouteExposeType)
+		return fmt.Errorf("%s tls termination required, but using %s", tls.Termination, strings.ToLower(string(nexus.Spec.Networking.ExposeAs)))
+	}
+
+	if tls.Termination == v1alpha1.PassthroughTLSTermination && nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType {
+		v.log.Warn("Passthrough TLS termination is only available when using a Route. Try setting ", "spec.networking.tls.termination'", v1alpha1.ReencryptTLSTermination)
+		return fmt.Errorf("passthrough tls termination required, but using ingress")
+	}
+
+	if nexus.Spec.Security.OAuth2Proxy != nil {
+		v.log.Warn("The oauth2-proxy sidecar only receives plain HTTP. Try setting ", "spec.networking.tls.termination'", v1alpha1.EdgeTLSTermination)
+		return fmt.Errorf("%s tls termination required, but using the oauth2-proxy sidecar", tls.Termination)
+	}
+
+	if tls.ServingCertificate && !v.ocp {
+		v.log.Warn("Service serving certificates are only available on OpenShift. Check the Nexus resource 'spec.networking.tls.servingCertificate' parameter")
+		return fmt.Errorf("service serving certificate required, but not running on openshift")
+	}
+
+	if len(tls.ServerSecretName) == 0 {
+		v.log.Warn("The Nexus server requires a certificate to serve HTTPS. Check the Nexus resource 'spec.networking.tls.serverSecretName' parameter")
+		return fmt.Errorf("%s tls termination required, but no server secret informed", tls.Termination)
+	}
+
+	if nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType && nexus.Spec.Networking.IngressProfile == v1alpha1.GenericIngressProfile {
+		v.log.Warn("The generic Ingress profile can't tell the controller to reach the Nexus server over HTTPS. Set it through 'spec.networking.annotations'", "Profile", nexus.Spec.Networking.IngressProfile)
+	}
+	return nil
+}
+
+func (v *Validator) validateConfiguration(nexus *v1alpha1.Nexus) error {
+	if property, ok := nexus.Spec.Properties[v1alpha1.HTTPPortProperty]; ok && nexus.Spec.Configuration.HTTPPort == 0 {
+		if _, valid := nexus.HTTPPortFromProperties(); !valid {
+			v.log.Warn("Invalid Nexus server HTTP port in the properties, using the default one. Set it through 'spec.configuration.httpPort'", "Property", v1alpha1.HTTPPortProperty, "Value", property, "Default", v1alpha1.DefaultNexusHTTPPort)
+		}
+	}
+	port := nexus.HTTPPort()
+	if reserved, ok := reservedDockerPorts[port]; ok {
+		v.log.Warn("Nexus server HTTP port already taken. Check the Nexus resource 'spec.configuration.httpPort' parameter", "Port", port, "TakenBy", reserved)
+		return fmt.Errorf("nexus server HTTP port %d is already taken by the %s", port, reserved)
+	}
+	if nexus.ScriptCreationAllowed() {
+		v.log.Info("Script creation through the REST API enabled, anyone with the 'nx-script-*-add' privilege can run code on the Nexus server")
+	}
+	return nil
+}
+
+// reservedPorts are the ports which can't be used by Docker connectors, including the Nexus server HTTP port
+func reservedPorts(nexus *v1alpha1.Nexus) map[int32]string {
+	ports := make(map[int32]string, len(reservedDockerPorts)+1)
+	for port, takenBy := range reservedDockerPorts {
+		ports[port] = takenBy
+	}
+	ports[nexus.HTTPPort()] = nexusHTTPPortName
+	return ports
+}
+
+func (v *Validator) validateDockerConnectors(nexus *v1alpha1.Nexus) error {
+	ports := make(map[int32]string)
+	for _, repo := range nexus.Spec.Repositories {
+		if repo.Format != v1alpha1.DockerRepositoryFormat || repo.Docker == nil || repo.Docker.HTTPPort == nil {
+			continue
+		}
+		port := *repo.Docker.HTTPPort
+		if reserved, ok := reservedPorts(nexus)[port]; ok {
+			v.log.Warn("Docker connector port already taken. Check the Nexus resource 'spec.repositories[].docker.httpPort' parameter", "Repository", repo.Name, "Port", port, "TakenBy", reserved)
+			return fmt.Errorf("docker connector port %d of repository %s is already taken by the %s", port, repo.Name, reserved)
+		}
+		if other, ok := ports[port]; ok {
+			v.log.Warn("Docker connector port used by more than one repository. Check the Nexus resource 'spec.repositories[].docker.httpPort' parameter", "Repositories", []string{other, repo.Name}, "Port", port)
+			return fmt.Errorf("docker connector port %d is used by both repositories %s and %s", port, other, repo.Name)
+		}
+		ports[port] = repo.Name
+
+		if nexus.Spec.Networking.Expose && nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType && len(repo.Docker.Host) == 0 {
+			v.log.Info("Docker repository without a dedicated host, its connector won't be exposed through the Ingress", "Repository", repo.Name)
+		}
+	}
+	return nil
+}
+
+// validateJVM checks the JVM fits in the memory limit of the Nexus container
+func (v *Validator) validateJVM(nexus *v1alpha1.Nexus) error {
+	jvm := nexus.Spec.JVM
+	sizes := []struct {
+		field string
+		size  *k8sres.Quantity
+	}{{"heapSize", jvm.HeapSize}, {"directMemorySize", jvm.DirectMemorySize}}
+	for _, s := range sizes {
+		if s.size != nil && s.size.Cmp(minJVMMemorySize) < 0 {
+			v.log.Warn("JVM memory size too small. Check the Nexus resource 'spec.jvm."+s.field+"' parameter", "Size", s.size.String(), "MinSize", minJVMMemorySize.String())
+			return fmt.Errorf("the JVM %s must be at least %s", s.field, minJVMMemorySize.String())
+		}
+	}
+
+	if memoryLimit, ok := nexus.Spec.Resources.Limits[corev1.ResourceMemory]; ok {
+		heapSize := jvmMemorySize(jvm.HeapSize, jvm.HeapPercentage, memoryLimit)
+		directMemorySize := jvmMemorySize(jvm.DirectMemorySize, jvm.DirectMemoryPercentage, memoryLimit)
+		if (heapSize+directMemorySize)*100 > memoryLimit.Value()*int64(jvmMaxMemoryPercentage) {
+			v.log.Warn("The JVM heap and direct memory don't fit in the memory limit. Check the Nexus resource 'spec.jvm' parameter", "HeapSize", heapSize, "DirectMemorySize", directMemorySize, "MemoryLimit", memoryLimit.String())
+			return fmt.Errorf("the JVM heap and direct memory take more than %d%% of the %s memory limit, leaving too little to the rest of the JVM", jvmMaxMemoryPercentage, memoryLimit.String())
+		}
+	}
+
+	if len(jvm.HeapDumpPath) > 0 && !path.IsAbs(jvm.HeapDumpPath) {
+		return fmt.Errorf("the JVM heap dump path %s must be absolute", jvm.HeapDumpPath)
+	}
+	if len(jvm.HeapDumpPath) > 0 && !persistentPath(nexus, jvm.HeapDumpPath) {
+		v.log.Warn("The heap dumps aren't written to a persistent volume, they will be lost when the pod restarts. Check the Nexus resource 'spec.jvm.heapDumpPath' parameter", "Path", jvm.HeapDumpPath)
+	}
+
+	for _, arg := range jvm.ExtraArgs {
+		if !strings.HasPrefix(arg, "-") {
+			v.log.Warn("Invalid JVM argument. Check the Nexus resource 'spec.jvm.extraArgs' parameter", "Argument", arg)
+			return fmt.Errorf("the JVM argument %s must start with '-'", arg)
+		}
+	}
+	return nil
+}
+
+// jvmMemorySize returns the size in bytes, the absolute size taking precedence over the percentage of the memory limit
+func jvmMemorySize(size *k8sres.Quantity, percentage *int32, memoryLimit k8sres.Quantity) int64 {
+	if size != nil {
+		return size.Value()
+	}
+	if percentage != nil {
+		return memoryLimit.Value() * int64(*percentage) / 100
+	}
+	return 0
+}
+
+// persistentPath checks if the path is in the data volume or in an extra volume
+func persistentPath(nexus *v1alpha1.Nexus, p string) bool {
+	var mountPaths []string
+	if nexus.Spec.Persistence.Persistent {
+		mountPaths = append(mountPaths, v1alpha1.NexusDataDir)
+	}
+	for _, volume := range nexus.Spec.Persistence.ExtraVolumes {
+		mountPaths = append(mountPaths, volume.MountPath)
+	}
+	for _, mountPath := range mountPaths {
+		if rel, err := filepath.Rel(mountPath, p); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateContainers checks the sidecars and init containers can run along with the containers managed by the Operator
+func (v *Validator) validateContainers(nexus *v1alpha1.Nexus) error {
+	names := map[string]string{
+		v1alpha1.NexusContainerName:       "Nexus server container",
+		v1alpha1.OAuth2ProxyContainerName: "oauth2-proxy sidecar",
+	}
+	for _, container := range nexus.Spec.InitContainers {
+		if taken, ok := names[container.Name]; ok {
+			v.log.Warn("Init container name already taken. Check the Nexus resource 'spec.initContainers[].name' parameter", "Name", container.Name, "TakenBy", taken)
+			return fmt.Errorf("init container name %s is already taken by the %s", container.Name, taken)
+		}
+		names[container.Name] = "init container"
+	}
+	for _, container := range nexus.Spec.Sidecars {
+		if taken, ok := names[container.Name]; ok {
+			v.log.Warn("Sidecar name already taken. Check the Nexus resource 'spec.sidecars[].name' parameter", "Name", container.Name, "TakenBy", taken)
+			return fmt.Errorf("sidecar name %s is already taken by the %s", container.Name, taken)
+		}
+		names[container.Name] = "sidecar"
+	}
+	return nil
+}
+
+func (v *Validator) validatePatches(nexus *v1alpha1.Nexus) error {
+	for i, p := range nexus.Spec.Patches {
+		if err := patch.Validate(p); err != nil {
+			v.log.Warn("Invalid patch. Check the Nexus resource 'spec.patches[].patch' parameter", "Index", i, "Kind", p.Kind, "Error", err)
+			return fmt.Errorf("invalid %s patch at index %d: %v", p.Kind, i, err)
+		}
+		if !patchTargetCreated(nexus, p.Kind) {
+			v.log.Info("No resource of this kind is created, the patch won't be applied", "Index", i, "Kind", p.Kind)
+		}
+	}
+	return nil
+}
+
+// patchTargetCreated checks if the Operator creates resources of the given kind
+func patchTargetCreated(nexus *v1alpha1.Nexus, kind v1alpha1.ResourcePatchKind) bool {
+	switch kind {
+	case v1alpha1.IngressPatchKind:
+		return nexus.Spec.Networking.Expose && nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType
+	case v1alpha1.RoutePatchKind:
+		return nexus.Spec.Networking.Expose && nexus.Spec.Networking.ExposeAs == v1alpha1.RouteExposeType
+	default:
+		return true
+	}
+}
+
+func (v *Validator) validateSecurity(nexus *v1alpha1.Nexus) error {
+	if nexus.Spec.Security.OAuth2Proxy == nil || nexus.Spec.Security.RUTAuth == nil {
+		return nil
+	}
+	header := nexus.Spec.Security.RUTAuth.HeaderName
+	for _, oauth2ProxyHeader := range oauth2ProxyUserHeaders {
+		if strings.EqualFold(header, oauth2ProxyHeader) {
+			return nil
+		}
+	}
+	v.log.Warn("The oauth2-proxy sidecar doesn't set the Remote User Token header. Check the Nexus resource 'spec.security.rutAuth.headerName' parameter", "SupportedHeaders", oauth2ProxyUserHeaders)
+	return fmt.Errorf("oauth2-proxy sidecar required, but the remote user token header %s is not set by it", header)
+}
+
 func (v *Validator) setDefaults(nexus *v1alpha1.Nexus) *v1alpha1.Nexus {
 	n := nexus.DeepCopy()
 	v.setDeploymentDefaults(n)
@@ -130,6 +483,7 @@ func (v *Validator) setDefaults(nexus *v1alpha1.Nexus) *v1alpha1.Nexus {
 func (v *Validator) setDeploymentDefaults(nexus *v1alpha1.Nexus) {
 	v.setReplicasDefaults(nexus)
 	v.setResourcesDefaults(nexus)
+	v.setJVMDefaults(nexus)
 	v.setImageDefaults(nexus)
 	v.setProbeDefaults(nexus)
 }
@@ -147,6 +501,28 @@ func (v *Validator) setResourcesDefaults(nexus *v1alpha1.Nexus) {
 	}
 }
 
+// setJVMDefaults sizes the JVM memory after the memory limit.
+// The servers deployed by previous versions of the Operator without any JVM setting keep their previous sizing, so that upgrading doesn't resize them.
+func (v *Validator) setJVMDefaults(nexus *v1alpha1.Nexus) {
+	jvm := nexus.Spec.JVM
+	if deployed(nexus) && jvm.HeapSize == nil && jvm.HeapPercentage == nil && jvm.DirectMemorySize == nil && jvm.DirectMemoryPercentage == nil {
+		v.log.Debug("Nexus server already deployed without JVM settings, keeping the previous memory sizing")
+		return
+	}
+	defaults := DefaultJVM.DeepCopy()
+	if nexus.Spec.JVM.HeapPercentage == nil {
+		nexus.Spec.JVM.HeapPercentage = defaults.HeapPercentage
+	}
+	if nexus.Spec.JVM.DirectMemoryPercentage == nil {
+		nexus.Spec.JVM.DirectMemoryPercentage = defaults.DirectMemoryPercentage
+	}
+}
+
+// deployed checks if the Deployment of the Nexus server has already been created
+func deployed(nexus *v1alpha1.Nexus) bool {
+	return nexus.Status.DeploymentStatus.ObservedGeneration > 0
+}
+
 func (v *Validator) setImageDefaults(nexus *v1alpha1.Nexus) {
 	if nexus.Spec.UseRedHatImage {
 		if len(nexus.Spec.Image) > 0 {
@@ -270,6 +646,112 @@ func (v *Validator) setNetworkingDefaults(nexus *v1alpha1.Nexus) {
 			nexus.Spec.Networking.ExposeAs = v1alpha1.NodePortExposeType
 		}
 	}
+
+	if nexus.Spec.Networking.ExposeAs == v1alpha1.IngressExposeType {
+		v.setIngressDefaults(nexus)
+	}
+
+	v.setCertManagerDefaults(nexus)
+	v.setServerTLSDefaults(nexus)
+}
+
+func (v *Validator) setCertManagerDefaults(nexus *v1alpha1.Nexus) {
+	certManager := nexus.Spec.Networking.TLS.CertManager
+	if certManager == nil {
+		return
+	}
+	if len(nexus.Spec.Networking.TLS.SecretName) == 0 {
+		nexus.Spec.Networking.TLS.SecretName = fmt.Sprintf(certManagerSecretNameFormat, nexus.Name)
+	}
+	if len(certManager.IssuerRef.Kind) == 0 {
+		certManager.IssuerRef.Kind = certmanager.IssuerKind
+	}
+	if len(certManager.IssuerRef.Group) == 0 {
+		certManager.IssuerRef.Group = certmanager.GroupVersion.Group
+	}
+}
+
+// setServerTLSDefaults picks the certificate served by the Nexus server when the TLS connections reach it
+func (v *Validator) setServerTLSDefaults(nexus *v1alpha1.Nexus) {
+	tls := &nexus.Spec.Networking.TLS
+	if tls.Termination != v1alpha1.ReencryptTLSTermination && tls.Termination != v1alpha1.PassthroughTLSTermination {
+		return
+	}
+	if len(tls.ServerSecretName) > 0 {
+		return
+	}
+	// re-encrypting Routes reach the server through its Service, so the certificate must be issued for the Service rather than for the exposed hosts
+	if tls.Termination == v1alpha1.ReencryptTLSTermination && v.ocp {
+		tls.ServingCertificate = true
+		tls.ServerSecretName = fmt.Sprintf(servingCertSecretNameFormat, nexus.Name)
+		return
+	}
+	if tls.CertManager != nil {
+		tls.ServerSecretName = tls.SecretName
+	}
+}
+
+// setIngressDefaults picks the Ingress profile. When no IngressClass serves the Ingress, it keeps targeting the nginx controller
+// through the legacy annotation, as the Operator did before supporting IngressClasses.
+func (v *Validator) setIngressDefaults(nexus *v1alpha1.Nexus) {
+	if !v.ingressAvailable {
+		if len(nexus.Spec.Networking.IngressProfile) == 0 {
+			nexus.Spec.Networking.IngressProfile = v1alpha1.GenericIngressProfile
+		}
+		return
+	}
+	class, err := v.ingressClass(nexus)
+	if err != nil {
+		v.log.Warn("Unable to list the IngressClasses, using the generic Ingress profile", "Error", err)
+		if len(nexus.Spec.Networking.IngressProfile) == 0 {
+			nexus.Spec.Networking.IngressProfile = v1alpha1.GenericIngressProfile
+		}
+		return
+	}
+	if class == nil && len(nexus.Spec.Networking.IngressClassName) == 0 {
+		if _, ok := nexus.Spec.Networking.Annotations[legacyIngressClassAnnotation]; !ok {
+			v.log.Info("No default IngressClass, targeting the nginx controller through the legacy annotation", "Annotation", legacyIngressClassAnnotation, "IngressClass", legacyIngressClass)
+			nexus.Spec.Networking.Annotations = util.AppendToStringMap(nexus.Spec.Networking.Annotations, legacyIngressClassAnnotation, legacyIngressClass)
+		}
+	}
+	if len(nexus.Spec.Networking.IngressProfile) == 0 {
+		nexus.Spec.Networking.IngressProfile = v.ingressProfile(nexus, class)
+	}
+}
+
+// ingressClass finds the IngressClass serving the Ingress: the named one or, if not set, the default one. Nil if there's none.
+func (v *Validator) ingressClass(nexus *v1alpha1.Nexus) (*networkingv1.IngressClass, error) {
+	classes := &networkingv1.IngressClassList{}
+	if err := v.client.List(ctx.TODO(), classes); err != nil {
+		return nil, err
+	}
+	for i, class := range classes.Items {
+		if class.Name == nexus.Spec.Networking.IngressClassName ||
+			(len(nexus.Spec.Networking.IngressClassName) == 0 && class.Annotations[defaultIngressClassAnnotation] == "true") {
+			return &classes.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// ingressProfile finds the profile matching the controller of the IngressClass serving the Ingress, if any
+func (v *Validator) ingressProfile(nexus *v1alpha1.Nexus, class *networkingv1.IngressClass) v1alpha1.NexusIngressProfile {
+	if class == nil {
+		if nexus.Spec.Networking.Annotations[legacyIngressClassAnnotation] == legacyIngressClass {
+			v.log.Debug("Ingress profile matching the legacy annotation", "Annotation", legacyIngressClassAnnotation, "Profile", v1alpha1.NginxIngressProfile)
+			return v1alpha1.NginxIngressProfile
+		}
+		if len(nexus.Spec.Networking.IngressClassName) > 0 {
+			v.log.Warn("IngressClass not found, using the generic Ingress profile. Check the Nexus resource 'spec.networking.ingressClassName' parameter", "IngressClass", nexus.Spec.Networking.IngressClassName)
+		}
+		return v1alpha1.GenericIngressProfile
+	}
+	if profile, ok := ingressControllerProfiles[class.Spec.Controller]; ok {
+		v.log.Debug("Ingress profile matching the IngressClass", "IngressClass", class.Name, "Controller", class.Spec.Controller, "Profile", profile)
+		return profile
+	}
+	v.log.Info("No Ingress profile for the IngressClass controller, using the generic one", "IngressClass", class.Name, "Controller", class.Spec.Controller)
+	return v1alpha1.GenericIngressProfile
 }
 
 func (v *Validator) setPersistenceDefaults(nexus *v1alpha1.Nexus) {
@@ -282,6 +764,30 @@ func (v *Validator) setSecurityDefaults(nexus *v1alpha1.Nexus) {
 	if len(nexus.Spec.ServiceAccountName) == 0 {
 		nexus.Spec.ServiceAccountName = nexus.Name
 	}
+	v.setOAuth2ProxyDefaults(nexus)
+	if nexus.Spec.Security.RUTAuth != nil && len(nexus.Spec.Security.RUTAuth.HeaderName) == 0 {
+		nexus.Spec.Security.RUTAuth.HeaderName = v1alpha1.DefaultRUTAuthHeader
+	}
+}
+
+func (v *Validator) setOAuth2ProxyDefaults(nexus *v1alpha1.Nexus) {
+	oauth2Proxy := nexus.Spec.Security.OAuth2Proxy
+	if oauth2Proxy == nil {
+		return
+	}
+	if len(oauth2Proxy.Image) == 0 {
+		oauth2Proxy.Image = DefaultOAuth2ProxyImage
+	}
+	if len(oauth2Proxy.Provider) == 0 {
+		oauth2Proxy.Provider = defaultOAuth2ProxyProvider
+	}
+	if len(oauth2Proxy.EmailDomains) == 0 {
+		oauth2Proxy.EmailDomains = []string{defaultOAuth2ProxyEmailDomain}
+	}
+	// the sidecar is useless if the server doesn't trust the user ID it sends
+	if nexus.Spec.Security.RUTAuth == nil {
+		nexus.Spec.Security.RUTAuth = &v1alpha1.RUTAuth{}
+	}
 }
 
 func ensureMinimum(value, minimum int32) int32 {
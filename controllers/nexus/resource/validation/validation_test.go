@@ -20,7 +20,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sres "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/update"
@@ -62,6 +67,16 @@ func TestNewValidator(t *testing.T) {
 				ocp:              false,
 			},
 		},
+		{
+			"On K8s with v1 ingress and cert-manager",
+			test.NewFakeClientBuilder().WithIngress().WithCertManager().Build(),
+			&Validator{
+				routeAvailable:       false,
+				ingressAvailable:     true,
+				certManagerAvailable: true,
+				ocp:                  false,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -283,6 +298,9 @@ func TestValidator_setNetworkingDefaults(t *testing.T) {
 				n := AllDefaultsCommunityNexus.DeepCopy()
 				n.Spec.Networking.Expose = true
 				n.Spec.Networking.ExposeAs = v1alpha1.IngressExposeType
+				// no IngressClass in the cluster
+				n.Spec.Networking.IngressProfile = v1alpha1.NginxIngressProfile
+				n.Spec.Networking.Annotations = map[string]string{legacyIngressClassAnnotation: legacyIngressClass}
 				return n
 			}(),
 		},
@@ -307,6 +325,7 @@ func TestValidator_setNetworkingDefaults(t *testing.T) {
 
 	for _, tt := range tests {
 		v := &Validator{
+			client:           test.NewFakeClientBuilder().WithIngress().Build(),
 			routeAvailable:   tt.routeAvailable,
 			ingressAvailable: tt.ingressAvailable,
 			ocp:              tt.ocp,
@@ -320,6 +339,75 @@ func TestValidator_setNetworkingDefaults(t *testing.T) {
 	}
 }
 
+func TestValidator_validateConfiguration(t *testing.T) {
+	tests := []struct {
+		name       string
+		httpPort   int32
+		properties map[string]string
+		wantError  bool
+	}{
+		{"Default port", v1alpha1.DefaultNexusHTTPPort, nil, false},
+		{"Custom port", 8082, nil, false},
+		{"Port of the HTTPS connector", 8443, nil, true},
+		{"Port of the oauth2-proxy sidecar", 4180, nil, true},
+		{"Port of the HTTPS connector in the properties", 0, map[string]string{v1alpha1.HTTPPortProperty: "8443"}, true},
+		{"Invalid port in the properties", 0, map[string]string{v1alpha1.HTTPPortProperty: "http"}, false},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Properties: tt.properties, Configuration: v1alpha1.NexusConfiguration{HTTPPort: tt.httpPort}}}
+		v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+		if err := v.validateConfiguration(nexus); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
+func TestValidator_setIngressDefaults(t *testing.T) {
+	nginxClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+	defaultTraefikClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "traefik", Annotations: map[string]string{defaultIngressClassAnnotation: "true"}},
+		Spec:       networkingv1.IngressClassSpec{Controller: "traefik.io/ingress-controller"},
+	}
+	unknownClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "unknown"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "example.com/ingress-controller"},
+	}
+
+	legacyAnnotation := map[string]string{legacyIngressClassAnnotation: legacyIngressClass}
+
+	tests := []struct {
+		name            string
+		className       string
+		annotations     map[string]string
+		classes         []runtime.Object
+		wantProfile     v1alpha1.NexusIngressProfile
+		wantAnnotations map[string]string
+	}{
+		// the Ingresses created before IngressClasses were supported keep being served by the nginx controller
+		{"No IngressClasses", "", nil, nil, v1alpha1.NginxIngressProfile, legacyAnnotation},
+		{"Named IngressClass", "nginx", nil, []runtime.Object{nginxClass, defaultTraefikClass}, v1alpha1.NginxIngressProfile, nil},
+		{"Default IngressClass", "", nil, []runtime.Object{nginxClass, defaultTraefikClass}, v1alpha1.TraefikIngressProfile, nil},
+		{"No default IngressClass", "", nil, []runtime.Object{nginxClass}, v1alpha1.NginxIngressProfile, legacyAnnotation},
+		{"Legacy annotation set by hand", "", map[string]string{legacyIngressClassAnnotation: "traefik"}, nil, v1alpha1.GenericIngressProfile, map[string]string{legacyIngressClassAnnotation: "traefik"}},
+		{"Missing IngressClass", "contour", nil, []runtime.Object{nginxClass}, v1alpha1.GenericIngressProfile, nil},
+		{"Unknown controller", "unknown", nil, []runtime.Object{unknownClass}, v1alpha1.GenericIngressProfile, nil},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{IngressClassName: tt.className, Annotations: tt.annotations}}}
+		v := &Validator{
+			client:           test.NewFakeClientBuilder(tt.classes...).WithIngress().Build(),
+			ingressAvailable: true,
+			log:              logger.GetLoggerWithResource("test", nexus),
+		}
+		v.setIngressDefaults(nexus)
+		assert.Equal(t, tt.wantProfile, nexus.Spec.Networking.IngressProfile, tt.name)
+		assert.Equal(t, tt.wantAnnotations, nexus.Spec.Networking.Annotations, tt.name)
+	}
+}
+
 func TestValidator_validateNetworking(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -345,6 +433,14 @@ func TestValidator_validateNetworking(t *testing.T) {
 			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com"}}},
 			false,
 		},
+		{
+			"Ingress with both an IngressClass and the legacy annotation",
+			false,
+			false,
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", IngressClassName: "nginx", Annotations: map[string]string{legacyIngressClassAnnotation: legacyIngressClass}}}},
+			true,
+		},
 		{
 			"Valid Nexus with Ingress and TLS secret on K8s",
 			false,
@@ -433,6 +529,78 @@ func TestValidator_validateNetworking(t *testing.T) {
 			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType}}},
 			true,
 		},
+		{
+			"Invalid Nexus with Node Port and the oauth2-proxy sidecar",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType, NodePort: 31031}, Security: v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Node Port and the Remote User Token realm",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType, NodePort: 31031}, Security: v1alpha1.NexusSecurity{RUTAuth: &v1alpha1.RUTAuth{}}}},
+			true,
+		},
+		{
+			"Valid Nexus with Load Balancer",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType, LoadBalancerIP: "192.168.1.240", LoadBalancerSourceRanges: []string{"10.0.0.0/8", "2001:db8::/32"}}}},
+			false,
+		},
+		{
+			"Invalid Nexus with Load Balancer and the oauth2-proxy sidecar",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType}, Security: v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Load Balancer and the Remote User Token realm",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType}, Security: v1alpha1.NexusSecurity{RUTAuth: &v1alpha1.RUTAuth{}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Load Balancer and an invalid IP",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType, LoadBalancerIP: "nexus.example.com"}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Load Balancer and an invalid source range",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType, LoadBalancerSourceRanges: []string{"10.0.0.1"}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Load Balancer and cert-manager",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType, TLS: v1alpha1.NexusNetworkingTLS{CertManager: &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt"}}}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Load Balancer and 'spec.networking.mandatory' set to 'true'",
+			false, // unimportant
+			false, // unimportant
+			false, // unimportant
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.LoadBalancerExposeType, TLS: v1alpha1.NexusNetworkingTLS{Mandatory: true}}}},
+			true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -448,6 +616,185 @@ func TestValidator_validateNetworking(t *testing.T) {
 	}
 }
 
+func TestValidator_setCertManagerDefaults(t *testing.T) {
+	v := &Validator{log: logger.GetLogger("test")}
+
+	// nothing to default without cert-manager
+	nexus := &v1alpha1.Nexus{ObjectMeta: metav1.ObjectMeta{Name: "nexus3"}}
+	v.setCertManagerDefaults(nexus)
+	assert.Empty(t, nexus.Spec.Networking.TLS.SecretName)
+
+	nexus.Spec.Networking.TLS.CertManager = &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt"}}
+	v.setCertManagerDefaults(nexus)
+	assert.Equal(t, "nexus3-tls", nexus.Spec.Networking.TLS.SecretName)
+	assert.Equal(t, v1alpha1.CertManagerIssuerRef{Name: "letsencrypt", Kind: "Issuer", Group: "cert-manager.io"}, nexus.Spec.Networking.TLS.CertManager.IssuerRef)
+
+	// informed values are kept
+	nexus.Spec.Networking.TLS.SecretName = "custom-tls"
+	nexus.Spec.Networking.TLS.CertManager.IssuerRef = v1alpha1.CertManagerIssuerRef{Name: "vault", Kind: "VaultIssuer", Group: "example.com"}
+	v.setCertManagerDefaults(nexus)
+	assert.Equal(t, "custom-tls", nexus.Spec.Networking.TLS.SecretName)
+	assert.Equal(t, v1alpha1.CertManagerIssuerRef{Name: "vault", Kind: "VaultIssuer", Group: "example.com"}, nexus.Spec.Networking.TLS.CertManager.IssuerRef)
+}
+
+func TestValidator_validateCertManager(t *testing.T) {
+	certManager := &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt"}}
+	tests := []struct {
+		name                 string
+		certManagerAvailable bool
+		input                *v1alpha1.Nexus
+		wantError            bool
+	}{
+		{
+			"Valid Nexus with Ingress and cert-manager",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", TLS: v1alpha1.NexusNetworkingTLS{SecretName: "nexus3-tls", CertManager: certManager}}}},
+			false,
+		},
+		{
+			"Valid Nexus with Route and cert-manager",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, Host: "example.com", TLS: v1alpha1.NexusNetworkingTLS{SecretName: "nexus3-tls", CertManager: certManager}}}},
+			false,
+		},
+		{
+			"Invalid Nexus with cert-manager unavailable",
+			false,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", TLS: v1alpha1.NexusNetworkingTLS{SecretName: "nexus3-tls", CertManager: certManager}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Route, cert-manager and no 'spec.networking.host'",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{SecretName: "nexus3-tls", CertManager: certManager}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Node Port and cert-manager",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType, NodePort: 8080, TLS: v1alpha1.NexusNetworkingTLS{CertManager: certManager}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with cert-manager and no issuer",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", TLS: v1alpha1.NexusNetworkingTLS{SecretName: "nexus3-tls", CertManager: &v1alpha1.CertManagerTLS{}}}}},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		v := &Validator{
+			routeAvailable:       true,
+			ingressAvailable:     true,
+			certManagerAvailable: tt.certManagerAvailable,
+			log:                  logger.GetLoggerWithResource("test", tt.input),
+		}
+		if err := v.validateNetworking(tt.input); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
+func TestValidator_setServerTLSDefaults(t *testing.T) {
+	certManager := &v1alpha1.CertManagerTLS{IssuerRef: v1alpha1.CertManagerIssuerRef{Name: "letsencrypt"}}
+	tests := []struct {
+		name                 string
+		ocp                  bool
+		tls                  v1alpha1.NexusNetworkingTLS
+		wantServerSecretName string
+		wantServingCert      bool
+	}{
+		{"Edge termination", true, v1alpha1.NexusNetworkingTLS{CertManager: certManager, SecretName: "nexus3-tls"}, "", false},
+		{"Reencrypt on OpenShift", true, v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination}, "nexus3-server-tls", true},
+		{"Reencrypt on OpenShift with cert-manager", true, v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, CertManager: certManager, SecretName: "nexus3-tls"}, "nexus3-server-tls", true},
+		{"Reencrypt on Kubernetes with cert-manager", false, v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, CertManager: certManager, SecretName: "nexus3-tls"}, "nexus3-tls", false},
+		{"Reencrypt on Kubernetes", false, v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination}, "", false},
+		{"Passthrough with cert-manager", true, v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.PassthroughTLSTermination, CertManager: certManager, SecretName: "nexus3-tls"}, "nexus3-tls", false},
+		{"Informed server Secret", true, v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "custom-tls"}, "custom-tls", false},
+	}
+
+	for _, tt := range tests {
+		v := &Validator{ocp: tt.ocp, log: logger.GetLogger("test")}
+		nexus := &v1alpha1.Nexus{ObjectMeta: metav1.ObjectMeta{Name: "nexus3"}, Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{TLS: tt.tls}}}
+		v.setServerTLSDefaults(nexus)
+		assert.Equal(t, tt.wantServerSecretName, nexus.Spec.Networking.TLS.ServerSecretName, tt.name)
+		assert.Equal(t, tt.wantServingCert, nexus.Spec.Networking.TLS.ServingCertificate, tt.name)
+	}
+}
+
+func TestValidator_validateServerTLS(t *testing.T) {
+	reencrypt := v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "nexus3-server-tls"}
+	passthrough := v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.PassthroughTLSTermination, ServerSecretName: "nexus3-server-tls"}
+	tests := []struct {
+		name      string
+		ocp       bool
+		input     *v1alpha1.Nexus
+		wantError bool
+	}{
+		{
+			"Valid Nexus with Route and reencrypt",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: reencrypt}}},
+			false,
+		},
+		{
+			"Valid Nexus with Route and passthrough",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: passthrough}}},
+			false,
+		},
+		{
+			"Valid Nexus with Ingress and reencrypt",
+			false,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", IngressProfile: v1alpha1.NginxIngressProfile, TLS: reencrypt}}},
+			false,
+		},
+		{
+			"Invalid Nexus with Ingress and passthrough",
+			false,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", TLS: passthrough}}},
+			true,
+		},
+		{
+			"Invalid Nexus with Node Port and reencrypt",
+			false,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.NodePortExposeType, NodePort: 8080, TLS: reencrypt}}},
+			true,
+		},
+		{
+			"Invalid Nexus with reencrypt and the oauth2-proxy sidecar",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: reencrypt}, Security: v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with reencrypt and no server Secret",
+			true,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.RouteExposeType, TLS: v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination}}}},
+			true,
+		},
+		{
+			"Invalid Nexus with a serving certificate on Kubernetes",
+			false,
+			&v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Networking: v1alpha1.NexusNetworking{Expose: true, ExposeAs: v1alpha1.IngressExposeType, Host: "example.com", TLS: v1alpha1.NexusNetworkingTLS{Termination: v1alpha1.ReencryptTLSTermination, ServerSecretName: "nexus3-server-tls", ServingCertificate: true}}}},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		v := &Validator{
+			routeAvailable:   true,
+			ingressAvailable: true,
+			ocp:              tt.ocp,
+			log:              logger.GetLoggerWithResource("test", tt.input),
+		}
+		if err := v.validateNetworking(tt.input); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
 func TestValidator_SetDefaultsAndValidate_Persistence(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -495,6 +842,25 @@ func TestValidator_SetDefaultsAndValidate_Security(t *testing.T) {
 			}(),
 			&AllDefaultsCommunityNexus,
 		},
+		{
+			"'spec.security.oauth2Proxy' with defaults",
+			func() *v1alpha1.Nexus {
+				nexus := AllDefaultsCommunityNexus.DeepCopy()
+				nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{ClientSecretName: "oauth2-proxy"}
+				return nexus
+			}(),
+			func() *v1alpha1.Nexus {
+				nexus := AllDefaultsCommunityNexus.DeepCopy()
+				nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{
+					Image:            DefaultOAuth2ProxyImage,
+					Provider:         defaultOAuth2ProxyProvider,
+					ClientSecretName: "oauth2-proxy",
+					EmailDomains:     []string{defaultOAuth2ProxyEmailDomain},
+				}
+				nexus.Spec.Security.RUTAuth = &v1alpha1.RUTAuth{HeaderName: v1alpha1.DefaultRUTAuthHeader}
+				return nexus
+			}(),
+		},
 	}
 	for _, tt := range tests {
 		v := &Validator{}
@@ -505,3 +871,174 @@ func TestValidator_SetDefaultsAndValidate_Security(t *testing.T) {
 		}
 	}
 }
+
+func TestValidator_validateSecurity(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     v1alpha1.NexusSecurity
+		wantError bool
+	}{
+		{"No oauth2-proxy sidecar", v1alpha1.NexusSecurity{RUTAuth: &v1alpha1.RUTAuth{HeaderName: "X-Remote-User"}}, false},
+		{"oauth2-proxy sidecar with the default header", v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{}, RUTAuth: &v1alpha1.RUTAuth{HeaderName: v1alpha1.DefaultRUTAuthHeader}}, false},
+		{"oauth2-proxy sidecar with the email header", v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{}, RUTAuth: &v1alpha1.RUTAuth{HeaderName: "x-forwarded-email"}}, false},
+		{"oauth2-proxy sidecar with an unknown header", v1alpha1.NexusSecurity{OAuth2Proxy: &v1alpha1.OAuth2Proxy{}, RUTAuth: &v1alpha1.RUTAuth{HeaderName: "X-Remote-User"}}, true},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Security: tt.input}}
+		v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+		if err := v.validateSecurity(nexus); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
+func TestValidator_validateDockerConnectors(t *testing.T) {
+	dockerRepo := func(name string, port int32) v1alpha1.Repository {
+		return v1alpha1.Repository{Name: name, Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port}}
+	}
+	tests := []struct {
+		name      string
+		input     []v1alpha1.Repository
+		wantError bool
+	}{
+		{"No repositories", nil, false},
+		{"Docker repository without connector", []v1alpha1.Repository{{Name: "docker", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType}}, false},
+		{"Docker repositories with distinct ports", []v1alpha1.Repository{dockerRepo("docker-hosted", 5000), dockerRepo("docker-group", 5001)}, false},
+		{"Docker repositories sharing a port", []v1alpha1.Repository{dockerRepo("docker-hosted", 5000), dockerRepo("docker-group", 5000)}, true},
+		{"Docker repository on the Nexus server port", []v1alpha1.Repository{dockerRepo("docker-hosted", 8081)}, true},
+		{"Docker repository on the service port", []v1alpha1.Repository{dockerRepo("docker-hosted", 80)}, true},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Repositories: tt.input}}
+		v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+		if err := v.validateDockerConnectors(nexus); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
+func TestValidator_validateDockerConnectors_WithCustomHTTPPort(t *testing.T) {
+	port := v1alpha1.DefaultNexusHTTPPort
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{
+		Configuration: v1alpha1.NexusConfiguration{HTTPPort: 8082},
+		Repositories:  []v1alpha1.Repository{{Name: "docker-hosted", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Docker: &v1alpha1.RepositoryDocker{HTTPPort: &port}}},
+	}}
+	v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+	// the default port is free once the Nexus server listens on another one
+	assert.NoError(t, v.validateDockerConnectors(nexus))
+	port = 8082
+	assert.Error(t, v.validateDockerConnectors(nexus))
+}
+
+func TestValidator_validateContainers(t *testing.T) {
+	tests := []struct {
+		name           string
+		sidecars       []corev1.Container
+		initContainers []corev1.Container
+		wantError      bool
+	}{
+		{"No extra containers", nil, nil, false},
+		{"Sidecar and init container", []corev1.Container{{Name: "log-shipper"}}, []corev1.Container{{Name: "fix-permissions"}}, false},
+		{"Sidecar named after the Nexus container", []corev1.Container{{Name: v1alpha1.NexusContainerName}}, nil, true},
+		{"Sidecar named after the oauth2-proxy sidecar", []corev1.Container{{Name: v1alpha1.OAuth2ProxyContainerName}}, nil, true},
+		{"Init container named after the Nexus container", nil, []corev1.Container{{Name: v1alpha1.NexusContainerName}}, true},
+		{"Sidecar named after an init container", []corev1.Container{{Name: "backup"}}, []corev1.Container{{Name: "backup"}}, true},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Sidecars: tt.sidecars, InitContainers: tt.initContainers}}
+		v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+		if err := v.validateContainers(nexus); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
+func TestValidator_validatePatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []v1alpha1.ResourcePatch
+		wantError bool
+	}{
+		{"No patches", nil, false},
+		{"JSON patch", []v1alpha1.ResourcePatch{{Kind: v1alpha1.ServicePatchKind, Patch: `{"metadata": {"annotations": {"team": "platform"}}}`}}, false},
+		{"YAML patch", []v1alpha1.ResourcePatch{{Kind: v1alpha1.DeploymentPatchKind, Patch: "spec:\n  template:\n    spec:\n      hostname: nexus\n"}}, false},
+		{"Patch of a resource not created", []v1alpha1.ResourcePatch{{Kind: v1alpha1.RoutePatchKind, Patch: `{"spec": {"wildcardPolicy": "None"}}`}}, false},
+		{"Malformed patch", []v1alpha1.ResourcePatch{{Kind: v1alpha1.ServicePatchKind, Patch: `{"metadata": `}}, true},
+		{"Patch not being an object", []v1alpha1.ResourcePatch{{Kind: v1alpha1.ServicePatchKind, Patch: "- op: add"}}, true},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Patches: tt.input}}
+		v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+		if err := v.validatePatches(nexus); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+}
+
+func TestValidator_setJVMDefaults(t *testing.T) {
+	nexus := &v1alpha1.Nexus{}
+	v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+	v.setJVMDefaults(nexus)
+	assert.Equal(t, DefaultJVM, nexus.Spec.JVM)
+
+	heapPercentage := int32(70)
+	nexus.Spec.JVM = v1alpha1.NexusJVM{HeapPercentage: &heapPercentage}
+	v.setJVMDefaults(nexus)
+	assert.Equal(t, int32(70), *nexus.Spec.JVM.HeapPercentage)
+	assert.Equal(t, *DefaultJVM.DirectMemoryPercentage, *nexus.Spec.JVM.DirectMemoryPercentage)
+
+	// deployed by a previous version of the Operator without JVM settings
+	nexus = &v1alpha1.Nexus{Status: v1alpha1.NexusStatus{DeploymentStatus: appsv1.DeploymentStatus{ObservedGeneration: 1}}}
+	v.setJVMDefaults(nexus)
+	assert.Equal(t, v1alpha1.NexusJVM{}, nexus.Spec.JVM)
+}
+
+func TestValidator_validateJVM(t *testing.T) {
+	percentage := func(value int32) *int32 { return &value }
+	size := func(value string) *k8sres.Quantity {
+		quantity := k8sres.MustParse(value)
+		return &quantity
+	}
+	tests := []struct {
+		name      string
+		input     v1alpha1.NexusJVM
+		wantError bool
+	}{
+		{"Default percentages", DefaultJVM, false},
+		{"No JVM settings", v1alpha1.NexusJVM{}, false},
+		{"Percentages leaving too little memory", v1alpha1.NexusJVM{HeapPercentage: percentage(70), DirectMemoryPercentage: percentage(25)}, true},
+		{"Absolute sizes within the limit", v1alpha1.NexusJVM{HeapSize: size("1Gi"), DirectMemorySize: size("512Mi")}, false},
+		{"Absolute heap size beyond the limit", v1alpha1.NexusJVM{HeapSize: size("2Gi"), DirectMemoryPercentage: percentage(25)}, true},
+		{"Heap size too small", v1alpha1.NexusJVM{HeapSize: size("512Ki")}, true},
+		{"Heap dump in the data volume", v1alpha1.NexusJVM{HeapDumpPath: "/nexus-data/log"}, false},
+		{"Relative heap dump path", v1alpha1.NexusJVM{HeapDumpPath: "log"}, true},
+		{"Extra arguments", v1alpha1.NexusJVM{ExtraArgs: []string{"-XX:ActiveProcessorCount=2", "-Dkaraf.log.console=INFO"}}, false},
+		{"Extra argument without dash", v1alpha1.NexusJVM{ExtraArgs: []string{"Xss1m"}}, true},
+	}
+	for _, tt := range tests {
+		nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Resources: DefaultResources, JVM: tt.input}}
+		v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+		if err := v.validateJVM(nexus); (err != nil) != tt.wantError {
+			t.Errorf("%s\nWantError: %v\tError: %v", tt.name, tt.wantError, err)
+		}
+	}
+
+	// without memory limit the percentages are ignored
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{JVM: v1alpha1.NexusJVM{HeapPercentage: percentage(90), DirectMemoryPercentage: percentage(90)}}}
+	v := &Validator{log: logger.GetLoggerWithResource("test", nexus)}
+	assert.NoError(t, v.validateJVM(nexus))
+}
+
+func Test_persistentPath(t *testing.T) {
+	nexus := &v1alpha1.Nexus{Spec: v1alpha1.NexusSpec{Persistence: v1alpha1.NexusPersistence{
+		ExtraVolumes: []v1alpha1.NexusVolume{{MountPath: "/dumps"}},
+	}}}
+	assert.True(t, persistentPath(nexus, "/dumps"))
+	assert.True(t, persistentPath(nexus, "/dumps/nexus"))
+	assert.True(t, persistentPath(nexus, "/dumps/..nexus"))
+	assert.False(t, persistentPath(nexus, "/dumpster"))
+	assert.False(t, persistentPath(nexus, "/"))
+	assert.False(t, persistentPath(nexus, "/nexus-data/log"))
+	nexus.Spec.Persistence.Persistent = true
+	assert.True(t, persistentPath(nexus, "/nexus-data/log"))
+}
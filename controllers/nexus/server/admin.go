@@ -0,0 +1,242 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/cluster/kubernetes"
+	"github.com/m88i/nexus-operator/pkg/framework"
+	"github.com/m88i/nexus-operator/pkg/framework/kind"
+)
+
+const (
+	// SecretKeyAdminPassword secret key for the randomly generated admin password in the Nexus server
+	SecretKeyAdminPassword = "server-admin-password"
+
+	// written by the server on its first boot when the random admin password generation is enabled, removed once the password changes
+	generatedAdminPasswordFile = v1alpha1.NexusDataDir + "/admin.password"
+	anonymousRESTPath          = "/security/anonymous"
+)
+
+// errGeneratedAdminPasswordUnavailable is returned when the randomly generated admin password can't be found anywhere or is no longer valid
+var errGeneratedAdminPasswordUnavailable = errors.New("the randomly generated admin password is unavailable, it might have been changed by hand. Consider setting spec.serverOperations.adminCredentialsSecret")
+
+// credentials to authenticate against the Nexus server
+type credentials struct {
+	username string
+	password string
+}
+
+var defaultAdminCredentials = credentials{username: defaultAdminUsername, password: defaultAdminPassword}
+
+// getAdminCredentials reads the administrator credentials from the Secret referenced by `spec.serverOperations.adminCredentialsSecret`,
+// falling back to the randomly generated ones or the default ones if there's no such reference
+func (s *server) getAdminCredentials() (credentials, error) {
+	ref := s.nexus.Spec.ServerOperations.AdminCredentialsSecret
+	if ref == nil {
+		if s.nexus.Spec.GenerateRandomAdminPassword {
+			return s.getGeneratedAdminCredentials()
+		}
+		return defaultAdminCredentials, nil
+	}
+	return s.getCredentials(v1alpha1.CredentialsSecret{Name: ref.Name, UsernameKey: ref.UsernameKey, PasswordKey: ref.PasswordKey})
+}
+
+// getCredentials reads the username and password from the referenced Secret in the namespace of the Nexus CR
+func (s *server) getCredentials(ref v1alpha1.CredentialsSecret) (credentials, error) {
+	secret := &corev1.Secret{}
+	if err := framework.Fetch(s.k8sclient, types.NamespacedName{Namespace: s.nexus.Namespace, Name: ref.Name}, secret, kind.SecretKind); err != nil {
+		return credentials{}, fmt.Errorf("failed to fetch credentials Secret %s: %v", ref.Name, err)
+	}
+	usernameKey := stringOrDefault(ref.UsernameKey, v1alpha1.DefaultCredentialsUsernameKey)
+	passwordKey := stringOrDefault(ref.PasswordKey, v1alpha1.DefaultCredentialsPasswordKey)
+	creds := credentials{username: string(secret.Data[usernameKey]), password: string(secret.Data[passwordKey])}
+	if len(creds.username) == 0 || len(creds.password) == 0 {
+		return credentials{}, fmt.Errorf("credentials Secret %s must hold both the '%s' and '%s' keys", ref.Name, usernameKey, passwordKey)
+	}
+	return creds, nil
+}
+
+// ensureAdminCredentials resolves the administrator credentials used to bootstrap the server operations.
+// If required, the default admin password is rotated to the one in the admin credentials Secret.
+func (s *server) ensureAdminCredentials() error {
+	if s.nexus.Spec.ServerOperations.AdminCredentialsSecret == nil && s.nexus.Spec.GenerateRandomAdminPassword {
+		if err := s.ensureGeneratedAdminCredentials(); err != nil {
+			return err
+		}
+		return s.completeOnboarding()
+	}
+
+	admin, err := s.getAdminCredentials()
+	if err != nil {
+		return err
+	}
+	s.admin = admin
+
+	ref := s.nexus.Spec.ServerOperations.AdminCredentialsSecret
+	if ref == nil || !ref.RotateDefaultPassword || admin.username != defaultAdminUsername || admin.password == defaultAdminPassword {
+		return nil
+	}
+	if authenticated, err := s.restcli.authenticates(admin.username, admin.password); err != nil || authenticated {
+		return err
+	}
+	if authenticated, err := s.restcli.authenticates(defaultAdminUsername, defaultAdminPassword); err != nil {
+		return err
+	} else if !authenticated {
+		log.Warn("Neither the credentials in the admin credentials Secret nor the default ones are accepted by the server", "Secret", ref.Name)
+		return nil
+	}
+
+	log.Info("Rotating the default admin password to the one in the admin credentials Secret", "Secret", ref.Name)
+	s.restcli.SetCredentials(defaultAdminUsername, defaultAdminPassword)
+	defer s.restcli.SetCredentials(admin.username, admin.password)
+	if err := s.restcli.putText(fmt.Sprintf("%s/%s/change-password", usersRESTPath, defaultAdminUsername), admin.password); err != nil {
+		return fmt.Errorf("failed to rotate the default admin password: %v", err)
+	}
+	return nil
+}
+
+// getGeneratedAdminCredentials reads the randomly generated admin password previously stored in the instance Secret
+func (s *server) getGeneratedAdminCredentials() (credentials, error) {
+	secret := &corev1.Secret{}
+	if err := framework.Fetch(s.k8sclient, framework.Key(s.nexus), secret, kind.SecretKind); err != nil {
+		return credentials{}, err
+	}
+	password := string(secret.Data[SecretKeyAdminPassword])
+	if len(password) == 0 {
+		return credentials{}, errGeneratedAdminPasswordUnavailable
+	}
+	return credentials{username: defaultAdminUsername, password: password}, nil
+}
+
+// ensureGeneratedAdminCredentials resolves the randomly generated admin password.
+// The password stored in the instance Secret is used if still valid, otherwise it's read from the server pod and then stored.
+func (s *server) ensureGeneratedAdminCredentials() error {
+	admin, err := s.getGeneratedAdminCredentials()
+	if err != nil && err != errGeneratedAdminPasswordUnavailable {
+		return err
+	}
+	if err == nil {
+		if authenticated, err := s.restcli.authenticates(admin.username, admin.password); err != nil {
+			return err
+		} else if authenticated {
+			s.admin = admin
+			s.restcli.SetCredentials(admin.username, admin.password)
+			return nil
+		}
+	}
+
+	log.Debug("Reading the randomly generated admin password from the server pod")
+	password, err := s.readGeneratedAdminPassword()
+	if err != nil {
+		return err
+	}
+	if len(password) == 0 {
+		return errGeneratedAdminPasswordUnavailable
+	}
+	if authenticated, err := s.restcli.authenticates(defaultAdminUsername, password); err != nil {
+		return err
+	} else if !authenticated {
+		return errGeneratedAdminPasswordUnavailable
+	}
+	if err := s.storeGeneratedAdminPassword(password); err != nil {
+		return err
+	}
+	s.admin = credentials{username: defaultAdminUsername, password: password}
+	s.restcli.SetCredentials(s.admin.username, s.admin.password)
+	return nil
+}
+
+// readGeneratedAdminPassword reads the randomly generated admin password from a running server pod.
+// Returns an empty string if the server no longer holds the password file.
+func (s *server) readGeneratedAdminPassword() (string, error) {
+	pods := &corev1.PodList{}
+	if err := s.k8sclient.List(context.TODO(), pods, client.InNamespace(s.nexus.Namespace), client.MatchingLabels(meta.GenerateLabels(s.nexus))); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// the file is gone once the password changes, which is not an error
+		password, err := kubernetes.ExecInPod(pod.Namespace, pod.Name, v1alpha1.NexusContainerName,
+			"sh", "-c", fmt.Sprintf("cat %s 2>/dev/null || true", generatedAdminPasswordFile))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(password), nil
+	}
+	return "", fmt.Errorf("no running pod found for Nexus instance %s", s.nexus.Name)
+}
+
+func (s *server) storeGeneratedAdminPassword(password string) error {
+	secret := &corev1.Secret{}
+	if err := framework.Fetch(s.k8sclient, framework.Key(s.nexus), secret, kind.SecretKind); err != nil {
+		return err
+	}
+	setSecretData(secret, SecretKeyAdminPassword, password)
+	log.Debug("Updating Secret with the randomly generated admin password")
+	return s.k8sclient.Update(context.TODO(), secret)
+}
+
+// completeOnboarding completes the onboarding wizard of a server whose admin password has been randomly generated:
+// the current anonymous access configuration is confirmed and the generated password is replaced by a new random one.
+func (s *server) completeOnboarding() error {
+	if !s.nexus.Spec.ServerOperations.CompleteOnboarding || s.status.OnboardingCompleted {
+		return nil
+	}
+	log.Debug("Completing the onboarding wizard")
+	anonymous := map[string]interface{}{}
+	if err := s.restcli.get(anonymousRESTPath, &anonymous); err != nil {
+		return err
+	}
+	if err := s.restcli.put(anonymousRESTPath, anonymous); err != nil {
+		return err
+	}
+
+	initial, err := s.readGeneratedAdminPassword()
+	if err != nil {
+		return err
+	}
+	if initial == s.admin.password {
+		password, err := GenerateRandomPassword()
+		if err != nil {
+			return err
+		}
+		// stored first, so the new password can't be lost: if changing it fails, the initial one is still in the pod to be read again
+		if err := s.storeGeneratedAdminPassword(password); err != nil {
+			return err
+		}
+		if err := s.restcli.putText(fmt.Sprintf("%s/%s/change-password", usersRESTPath, defaultAdminUsername), password); err != nil {
+			return fmt.Errorf("failed to replace the randomly generated admin password: %v", err)
+		}
+		s.admin.password = password
+		s.restcli.SetCredentials(s.admin.username, s.admin.password)
+		log.Info("Randomly generated admin password replaced, the new one is stored in the instance Secret", "Key", SecretKeyAdminPassword)
+	}
+	s.status.OnboardingCompleted = true
+	log.Info("Onboarding wizard completed")
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/resource/meta"
+	"github.com/m88i/nexus-operator/pkg/cluster/kubernetes"
+	"github.com/m88i/nexus-operator/pkg/framework"
+)
+
+// fakePodExecutor reads the admin.password file from a fake Nexus server
+type fakePodExecutor struct {
+	fake  *fakeNexusServer
+	calls int
+}
+
+func (e *fakePodExecutor) Exec(namespace, pod, container string, command ...string) (string, error) {
+	e.fake.mutex.Lock()
+	defer e.fake.mutex.Unlock()
+	e.calls++
+	if container != v1alpha1.NexusContainerName {
+		return "", fmt.Errorf("container %s not found", container)
+	}
+	return e.fake.adminPasswordFile + "\n", nil
+}
+
+// createNewServerWithGeneratedPassword creates a new server pointing to a fake Nexus server whose admin password has been randomly generated
+func createNewServerWithGeneratedPassword(t *testing.T, storedPassword string) (*server, *fakeNexusServer, *fakePodExecutor, client.Client) {
+	nexus := &v1alpha1.Nexus{ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "nexus3-abcde", Namespace: t.Name(), Labels: meta.GenerateLabels(nexus)},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}, Data: map[string][]byte{}}
+	if len(storedPassword) > 0 {
+		secret.Data[SecretKeyAdminPassword] = []byte(storedPassword)
+	}
+	server, cli := createNewServerAndKubeCli(t, pod, secret)
+	server.nexus.Spec.GenerateRandomAdminPassword = true
+	fake := newFakeNexusServer(t)
+	fake.adminPassword = "generated"
+	fake.adminPasswordFile = "generated"
+	server.restcli = fake.client()
+	executor := &fakePodExecutor{fake: fake}
+	kubernetes.SetPodExecutor(executor)
+	t.Cleanup(func() { kubernetes.SetPodExecutor(nil) })
+	return server, fake, executor, cli
+}
+
+func storedAdminPassword(t *testing.T, cli client.Client, nexus *v1alpha1.Nexus) string {
+	secret := &corev1.Secret{}
+	assert.NoError(t, cli.Get(context.TODO(), framework.Key(nexus), secret))
+	return secret.StringData[SecretKeyAdminPassword]
+}
+
+// createNewServerWithAdminSecret creates a new server pointing to a fake Nexus server with an admin credentials Secret in the cluster
+func createNewServerWithAdminSecret(t *testing.T, ref *v1alpha1.AdminCredentialsSecret, data map[string][]byte) (*server, *fakeNexusServer) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: t.Name()}, Data: data}
+	server, _ := createNewServerAndKubeCli(t, secret)
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	server.nexus.Spec.ServerOperations.AdminCredentialsSecret = ref
+	return server, fake
+}
+
+func Test_server_getAdminCredentialsDefault(t *testing.T) {
+	server, _ := createNewServerAndKubeCli(t)
+	admin, err := server.getAdminCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultAdminCredentials, admin)
+}
+
+func Test_server_getAdminCredentialsFromSecret(t *testing.T) {
+	server, _ := createNewServerWithAdminSecret(t,
+		&v1alpha1.AdminCredentialsSecret{Name: "admin", UsernameKey: "user", PasswordKey: "pass"},
+		map[string][]byte{"user": []byte("root"), "pass": []byte("s3cr3t")})
+	admin, err := server.getAdminCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, credentials{username: "root", password: "s3cr3t"}, admin)
+}
+
+func Test_server_getAdminCredentialsMissingKey(t *testing.T) {
+	server, _ := createNewServerWithAdminSecret(t,
+		&v1alpha1.AdminCredentialsSecret{Name: "admin"},
+		map[string][]byte{v1alpha1.DefaultAdminCredentialsUsernameKey: []byte("admin")})
+	_, err := server.getAdminCredentials()
+	assert.Error(t, err)
+}
+
+func Test_server_getAdminCredentialsSecretNotFound(t *testing.T) {
+	server, _ := createNewServerAndKubeCli(t)
+	server.nexus.Spec.ServerOperations.AdminCredentialsSecret = &v1alpha1.AdminCredentialsSecret{Name: "admin"}
+	_, err := server.getAdminCredentials()
+	assert.Error(t, err)
+}
+
+func Test_server_ensureAdminCredentialsRotatesDefaultPassword(t *testing.T) {
+	server, fake := createNewServerWithAdminSecret(t,
+		&v1alpha1.AdminCredentialsSecret{Name: "admin", RotateDefaultPassword: true},
+		map[string][]byte{"username": []byte(defaultAdminUsername), "password": []byte("s3cr3t")})
+
+	assert.NoError(t, server.ensureAdminCredentials())
+	assert.Equal(t, "s3cr3t", fake.adminPassword)
+	assert.Equal(t, credentials{username: defaultAdminUsername, password: "s3cr3t"}, server.admin)
+	assert.Equal(t, "s3cr3t", server.restcli.password)
+
+	// already rotated, nothing else to do
+	fake.requests = nil
+	assert.NoError(t, server.ensureAdminCredentials())
+	assert.False(t, fake.requested("PUT /security/users/admin/change-password"))
+}
+
+func Test_server_ensureAdminCredentialsWithoutRotation(t *testing.T) {
+	server, fake := createNewServerWithAdminSecret(t,
+		&v1alpha1.AdminCredentialsSecret{Name: "admin"},
+		map[string][]byte{"username": []byte(defaultAdminUsername), "password": []byte("s3cr3t")})
+
+	assert.NoError(t, server.ensureAdminCredentials())
+	assert.Equal(t, defaultAdminPassword, fake.adminPassword)
+	assert.Empty(t, fake.requests)
+}
+
+func Test_server_ensureAdminCredentialsReadsGeneratedPassword(t *testing.T) {
+	server, _, executor, cli := createNewServerWithGeneratedPassword(t, "")
+
+	assert.NoError(t, server.ensureAdminCredentials())
+	assert.Equal(t, credentials{username: defaultAdminUsername, password: "generated"}, server.admin)
+	assert.Equal(t, "generated", server.restcli.password)
+	assert.Equal(t, "generated", storedAdminPassword(t, cli, server.nexus))
+	assert.Equal(t, 1, executor.calls)
+	assert.False(t, server.status.OnboardingCompleted)
+}
+
+func Test_server_ensureAdminCredentialsUsesStoredGeneratedPassword(t *testing.T) {
+	server, _, executor, _ := createNewServerWithGeneratedPassword(t, "generated")
+
+	assert.NoError(t, server.ensureAdminCredentials())
+	assert.Equal(t, credentials{username: defaultAdminUsername, password: "generated"}, server.admin)
+	assert.Zero(t, executor.calls)
+
+	admin, err := server.getAdminCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, "generated", admin.password)
+}
+
+func Test_server_ensureAdminCredentialsGeneratedPasswordUnavailable(t *testing.T) {
+	server, fake, _, _ := createNewServerWithGeneratedPassword(t, "stale")
+	// changed by hand
+	fake.adminPassword = "changed"
+	fake.adminPasswordFile = ""
+
+	assert.Equal(t, errGeneratedAdminPasswordUnavailable, server.ensureAdminCredentials())
+}
+
+func Test_server_ensureAdminCredentialsCompletesOnboarding(t *testing.T) {
+	server, fake, _, cli := createNewServerWithGeneratedPassword(t, "")
+	server.nexus.Spec.ServerOperations.CompleteOnboarding = true
+
+	assert.NoError(t, server.ensureAdminCredentials())
+	assert.True(t, server.status.OnboardingCompleted)
+	assert.True(t, fake.requested("PUT /security/anonymous"))
+	// the generated password has been replaced
+	assert.Empty(t, fake.adminPasswordFile)
+	assert.NotEqual(t, "generated", fake.adminPassword)
+	assert.Equal(t, fake.adminPassword, server.admin.password)
+	assert.Equal(t, fake.adminPassword, storedAdminPassword(t, cli, server.nexus))
+}
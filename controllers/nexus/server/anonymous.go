@@ -0,0 +1,53 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+// apiAnonymousAccess is the representation of the anonymous access settings sent to and read from the Nexus REST API
+type apiAnonymousAccess struct {
+	Enabled   bool   `json:"enabled"`
+	UserID    string `json:"userId"`
+	RealmName string `json:"realmName"`
+}
+
+func (r *realmOperation) EnsureAnonymousAccess() error {
+	declared := r.nexus.Spec.Security.AnonymousAccess
+	if declared == nil {
+		log.Debug("No anonymous access declared in 'spec.security.anonymousAccess', skipping")
+		return nil
+	}
+	desired := apiAnonymousAccess{
+		Enabled:   declared.Enabled,
+		UserID:    stringOrDefault(declared.UserID, v1alpha1.DefaultAnonymousUserID),
+		RealmName: stringOrDefault(declared.Realm, v1alpha1.DefaultAnonymousRealm),
+	}
+	log.Debug("Attempt to fetch the anonymous access settings from the server")
+	actual := apiAnonymousAccess{}
+	if err := r.restcli.get(anonymousRESTPath, &actual); err != nil {
+		return err
+	}
+	if actual != desired {
+		log.Debug("Anonymous access differs from the desired state, trying to update it")
+		if err := r.restcli.put(anonymousRESTPath, desired); err != nil {
+			return err
+		}
+		log.Info("Anonymous access updated", "Enabled", desired.Enabled)
+	}
+	r.status.AnonymousAccessConfigured = true
+	return nil
+}
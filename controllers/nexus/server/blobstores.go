@@ -0,0 +1,248 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/framework"
+	"github.com/m88i/nexus-operator/pkg/framework/kind"
+)
+
+// BlobStoreOperations describes the public operations in the blob store domain for the Nexus instance
+type BlobStoreOperations interface {
+	// EnsureBlobStores creates or updates the blob stores declared in `spec.blobStores`.
+	// Must be called before the repositories referencing them are handled.
+	EnsureBlobStores() error
+	// PruneBlobStores removes from the server the blob stores created by the Operator and no longer declared.
+	// Must be called after the repositories using them are removed.
+	PruneBlobStores() error
+}
+
+type blobStoreOperation struct {
+	server
+}
+
+func blobStoreOperations(server *server) BlobStoreOperations {
+	return &blobStoreOperation{server: *server}
+}
+
+func (b *blobStoreOperation) EnsureBlobStores() error {
+	previous := b.status.BlobStores
+	if len(b.nexus.Spec.BlobStores) == 0 && len(previous) == 0 {
+		log.Debug("No blob stores declared in 'spec.blobStores', skipping")
+		return nil
+	}
+
+	existing, err := b.fetchBlobStores()
+	if err != nil {
+		return err
+	}
+	created := make(map[string]bool, len(previous))
+	for _, store := range previous {
+		created[store.Name] = store.Created
+	}
+
+	var statuses []v1alpha1.BlobStoreStatus
+	var errs []string
+	declared := make(map[string]bool, len(b.nexus.Spec.BlobStores))
+	for _, store := range b.nexus.Spec.BlobStores {
+		declared[store.Name] = true
+		status, err := b.ensureBlobStore(store, existing, created[store.Name])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		statuses = append(statuses, status)
+	}
+	// kept until pruned
+	for _, store := range previous {
+		if !declared[store.Name] {
+			statuses = append(statuses, store)
+		}
+	}
+
+	b.status.BlobStores = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to ensure blob stores: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (b *blobStoreOperation) PruneBlobStores() error {
+	declared := make(map[string]bool, len(b.nexus.Spec.BlobStores))
+	for _, store := range b.nexus.Spec.BlobStores {
+		declared[store.Name] = true
+	}
+
+	var statuses []v1alpha1.BlobStoreStatus
+	var errs []string
+	for _, store := range b.status.BlobStores {
+		if declared[store.Name] {
+			statuses = append(statuses, store)
+			continue
+		}
+		if !store.Created {
+			log.Debug("Blob store removed from the spec wasn't created by the Operator, won't remove it from the server", "BlobStore", store.Name)
+			continue
+		}
+		log.Debug("Trying to remove blob store", "BlobStore", store.Name)
+		if err := b.restcli.delete(fmt.Sprintf("%s/%s", blobStoresRESTPath, store.Name)); err != nil && !isRESTNotFound(err) {
+			// we keep it in the status to try again in the next reconciliation, it might still be used by a repository
+			store.Ready = false
+			store.Reason = fmt.Sprintf("Failed to remove blob store from the server: %v", err)
+			statuses = append(statuses, store)
+			errs = append(errs, err.Error())
+			continue
+		}
+		log.Info("Blob store removed", "BlobStore", store.Name)
+	}
+
+	b.status.BlobStores = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to remove blob stores: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (b *blobStoreOperation) fetchBlobStores() (map[string]apiBlobStoreRef, error) {
+	log.Debug("Attempt to fetch all blob stores from the server")
+	var fetched []apiBlobStoreRef
+	if err := b.restcli.get(blobStoresRESTPath, &fetched); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]apiBlobStoreRef, len(fetched))
+	for _, store := range fetched {
+		existing[store.Name] = store
+	}
+	return existing, nil
+}
+
+func (b *blobStoreOperation) ensureBlobStore(store v1alpha1.BlobStore, existing map[string]apiBlobStoreRef, created bool) (v1alpha1.BlobStoreStatus, error) {
+	status := v1alpha1.BlobStoreStatus{Name: store.Name, Type: store.Type, Created: created}
+	if err := validateBlobStore(store, b.nexus.Spec.Persistence.ExtraVolumes); err != nil {
+		log.Warn("Invalid blob store declared in 'spec.blobStores'", "BlobStore", store.Name, "Reason", err.Error())
+		status.Reason = err.Error()
+		return status, nil
+	}
+
+	desired, comparable, err := b.newAPIBlobStore(store)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+	path := blobStorePath(store.Type)
+	current, found := existing[store.Name]
+	if !found {
+		log.Debug("Trying to create blob store", "BlobStore", store.Name)
+		if err := b.restcli.post(path, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		status.Created = true
+		log.Info("Blob store created", "BlobStore", store.Name)
+		return b.withUsage(status)
+	}
+	if !strings.EqualFold(current.Type, string(store.Type)) {
+		status.Reason = fmt.Sprintf("A blob store named %s already exists in the server with type %s", store.Name, current.Type)
+		log.Warn(status.Reason)
+		return status, nil
+	}
+
+	var actual map[string]interface{}
+	if err := b.restcli.get(path+"/"+store.Name, &actual); err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+	equal, err := jsonContains(actual, comparable)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+	// a soft quota removed from the spec isn't caught by the comparison above
+	if !equal || (store.SoftQuota == nil && current.SoftQuota != nil) {
+		log.Debug("Blob store differs from the desired state, trying to update it", "BlobStore", store.Name)
+		if err := b.restcli.put(path+"/"+store.Name, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		log.Info("Blob store updated", "BlobStore", store.Name)
+	}
+	status.BlobCount, status.TotalSizeInBytes, status.AvailableSpaceInBytes = current.BlobCount, current.TotalSizeInBytes, current.AvailableSpaceInBytes
+	status.Ready = true
+	return status, nil
+}
+
+// withUsage reads the usage of a freshly created blob store
+func (b *blobStoreOperation) withUsage(status v1alpha1.BlobStoreStatus) (v1alpha1.BlobStoreStatus, error) {
+	existing, err := b.fetchBlobStores()
+	if err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+	current := existing[status.Name]
+	status.BlobCount, status.TotalSizeInBytes, status.AvailableSpaceInBytes = current.BlobCount, current.TotalSizeInBytes, current.AvailableSpaceInBytes
+	status.Ready = true
+	return status, nil
+}
+
+// newAPIBlobStore creates the representation of the given blob store to be sent to the server,
+// along with the one to be compared with what the server returns, since it never returns the S3 secrets.
+func (b *blobStoreOperation) newAPIBlobStore(store v1alpha1.BlobStore) (desired, comparable interface{}, err error) {
+	if store.Type == v1alpha1.FileBlobStoreType {
+		file := newAPIFileBlobStore(store, b.nexus.Spec.Persistence.ExtraVolumes)
+		comparableFile := file
+		comparableFile.Name = ""
+		return file, comparableFile, nil
+	}
+
+	s3 := newAPIS3BlobStore(store)
+	comparableS3 := s3
+	comparableS3.Name = ""
+	if ref := store.S3.CredentialsSecret; ref != nil {
+		security, err := b.getS3Credentials(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		s3.BucketConfiguration.BucketSecurity = security
+		comparableS3.BucketConfiguration.BucketSecurity = &apiS3BucketSecurity{AccessKeyID: security.AccessKeyID}
+	}
+	return s3, comparableS3, nil
+}
+
+// getS3Credentials reads the credentials to access a S3 bucket from the referenced Secret
+func (b *blobStoreOperation) getS3Credentials(ref *v1alpha1.S3CredentialsSecret) (*apiS3BucketSecurity, error) {
+	secret := &corev1.Secret{}
+	if err := framework.Fetch(b.k8sclient, types.NamespacedName{Namespace: b.nexus.Namespace, Name: ref.Name}, secret, kind.SecretKind); err != nil {
+		return nil, fmt.Errorf("failed to fetch S3 credentials Secret %s: %v", ref.Name, err)
+	}
+	accessKeyIDKey := stringOrDefault(ref.AccessKeyIDKey, v1alpha1.DefaultS3AccessKeyIDKey)
+	secretAccessKeyKey := stringOrDefault(ref.SecretAccessKeyKey, v1alpha1.DefaultS3SecretAccessKeyKey)
+	security := &apiS3BucketSecurity{
+		AccessKeyID:     string(secret.Data[accessKeyIDKey]),
+		SecretAccessKey: string(secret.Data[secretAccessKeyKey]),
+	}
+	if len(security.AccessKeyID) == 0 || len(security.SecretAccessKey) == 0 {
+		return nil, fmt.Errorf("S3 credentials Secret %s must hold both the '%s' and '%s' keys", ref.Name, accessKeyIDKey, secretAccessKeyKey)
+	}
+	if len(ref.SessionTokenKey) > 0 {
+		security.SessionToken = string(secret.Data[ref.SessionTokenKey])
+	}
+	return security, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const (
+	blobStoresRESTPath = "/blobstores"
+
+	defaultS3Region     = "DEFAULT"
+	defaultS3Expiration = int32(3)
+	bytesInMegabyte     = 1024 * 1024
+)
+
+// apiBlobStoreRef is the short representation of a blob store returned when listing all blob stores in the server, including its usage
+type apiBlobStoreRef struct {
+	Name                  string        `json:"name"`
+	Type                  string        `json:"type"`
+	SoftQuota             *apiSoftQuota `json:"softQuota,omitempty"`
+	BlobCount             int64         `json:"blobCount"`
+	TotalSizeInBytes      int64         `json:"totalSizeInBytes"`
+	AvailableSpaceInBytes int64         `json:"availableSpaceInBytes"`
+}
+
+type apiSoftQuota struct {
+	Type string `json:"type"`
+	// in megabytes
+	Limit int64 `json:"limit"`
+}
+
+type apiFileBlobStore struct {
+	Name      string        `json:"name,omitempty"`
+	Path      string        `json:"path"`
+	SoftQuota *apiSoftQuota `json:"softQuota,omitempty"`
+}
+
+type apiS3BlobStore struct {
+	Name                string                   `json:"name,omitempty"`
+	SoftQuota           *apiSoftQuota            `json:"softQuota,omitempty"`
+	BucketConfiguration apiS3BucketConfiguration `json:"bucketConfiguration"`
+}
+
+type apiS3BucketConfiguration struct {
+	Bucket                   apiS3Bucket                    `json:"bucket"`
+	BucketSecurity           *apiS3BucketSecurity           `json:"bucketSecurity,omitempty"`
+	AdvancedBucketConnection *apiS3AdvancedBucketConnection `json:"advancedBucketConnection,omitempty"`
+}
+
+type apiS3Bucket struct {
+	Region     string `json:"region"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix"`
+	Expiration int32  `json:"expiration"`
+}
+
+type apiS3BucketSecurity struct {
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+}
+
+type apiS3AdvancedBucketConnection struct {
+	Endpoint       string `json:"endpoint,omitempty"`
+	ForcePathStyle bool   `json:"forcePathStyle"`
+}
+
+// blobStorePath returns the REST path for the given blob store type
+func blobStorePath(storeType v1alpha1.BlobStoreType) string {
+	return fmt.Sprintf("%s/%s", blobStoresRESTPath, strings.ToLower(string(storeType)))
+}
+
+// validateBlobStore verifies if the given blob store declaration has everything required by its type
+func validateBlobStore(store v1alpha1.BlobStore, extraVolumes []v1alpha1.NexusVolume) error {
+	switch store.Type {
+	case v1alpha1.FileBlobStoreType:
+		if store.S3 != nil {
+			return fmt.Errorf("blob store %s: 's3' can only be set on blob stores of type %s", store.Name, v1alpha1.S3BlobStoreType)
+		}
+		if store.File != nil && len(store.File.ExtraVolume) > 0 {
+			if findExtraVolume(store.File.ExtraVolume, extraVolumes) == nil {
+				return fmt.Errorf("blob store %s: extra volume %s not found in 'spec.persistence.extraVolumes'", store.Name, store.File.ExtraVolume)
+			}
+			if path.IsAbs(store.File.Path) {
+				return fmt.Errorf("blob store %s: the path must be relative to the extra volume %s", store.Name, store.File.ExtraVolume)
+			}
+		}
+	case v1alpha1.S3BlobStoreType:
+		if store.File != nil {
+			return fmt.Errorf("blob store %s: 'file' can only be set on blob stores of type %s", store.Name, v1alpha1.FileBlobStoreType)
+		}
+		if store.S3 == nil || len(store.S3.Bucket) == 0 {
+			return fmt.Errorf("blob store %s: 's3.bucket' is required for blob stores of type %s", store.Name, v1alpha1.S3BlobStoreType)
+		}
+	default:
+		return fmt.Errorf("blob store %s: unsupported type %s", store.Name, store.Type)
+	}
+	if store.SoftQuota != nil && store.SoftQuota.Limit.Value() < bytesInMegabyte {
+		return fmt.Errorf("blob store %s: the soft quota limit must be at least 1Mi", store.Name)
+	}
+	return nil
+}
+
+func findExtraVolume(name string, extraVolumes []v1alpha1.NexusVolume) *v1alpha1.NexusVolume {
+	for i := range extraVolumes {
+		if extraVolumes[i].Name == name {
+			return &extraVolumes[i]
+		}
+	}
+	return nil
+}
+
+func newAPISoftQuota(quota *v1alpha1.BlobStoreSoftQuota) *apiSoftQuota {
+	if quota == nil {
+		return nil
+	}
+	return &apiSoftQuota{Type: string(quota.Type), Limit: quota.Limit.Value() / bytesInMegabyte}
+}
+
+func newAPIFileBlobStore(store v1alpha1.BlobStore, extraVolumes []v1alpha1.NexusVolume) apiFileBlobStore {
+	storePath := store.Name
+	if store.File != nil {
+		storePath = stringOrDefault(store.File.Path, store.Name)
+		if volume := findExtraVolume(store.File.ExtraVolume, extraVolumes); volume != nil {
+			storePath = path.Join(volume.MountPath, storePath)
+		}
+	}
+	return apiFileBlobStore{Name: store.Name, Path: storePath, SoftQuota: newAPISoftQuota(store.SoftQuota)}
+}
+
+// newAPIS3BlobStore creates the representation of a S3 blob store, credentials must be set by the caller
+func newAPIS3BlobStore(store v1alpha1.BlobStore) apiS3BlobStore {
+	s3 := store.S3
+	api := apiS3BlobStore{
+		Name:      store.Name,
+		SoftQuota: newAPISoftQuota(store.SoftQuota),
+		BucketConfiguration: apiS3BucketConfiguration{
+			Bucket: apiS3Bucket{
+				Region:     stringOrDefault(s3.Region, defaultS3Region),
+				Name:       s3.Bucket,
+				Prefix:     s3.Prefix,
+				Expiration: int32OrDefault(s3.Expiration, defaultS3Expiration),
+			},
+		},
+	}
+	if len(s3.Endpoint) > 0 || s3.ForcePathStyle {
+		api.BucketConfiguration.AdvancedBucketConnection = &apiS3AdvancedBucketConnection{Endpoint: s3.Endpoint, ForcePathStyle: s3.ForcePathStyle}
+	}
+	return api
+}
@@ -0,0 +1,157 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+// createNewServerWithBlobStores creates a new server pointing to a fake Nexus server with the given blob stores declared
+func createNewServerWithBlobStores(t *testing.T, stores ...v1alpha1.BlobStore) (*server, *fakeNexusServer) {
+	s3Secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-credentials", Namespace: t.Name()},
+		Data:       map[string][]byte{v1alpha1.DefaultS3AccessKeyIDKey: []byte("AKIA"), v1alpha1.DefaultS3SecretAccessKeyKey: []byte("s3cr3t")},
+	}
+	server, _ := createNewServerAndKubeCli(t, s3Secret)
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	server.nexus.Spec.BlobStores = stores
+	server.nexus.Spec.Persistence.ExtraVolumes = []v1alpha1.NexusVolume{{Volume: corev1.Volume{Name: "blobs"}, MountPath: "/blobs"}}
+	return server, fake
+}
+
+func Test_blobStoreOperation_EnsureBlobStoresNoBlobStores(t *testing.T) {
+	server, fake := createNewServerWithBlobStores(t)
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	assert.NoError(t, blobStoreOperations(server).PruneBlobStores())
+	assert.Empty(t, fake.requests)
+}
+
+func Test_blobStoreOperation_EnsureBlobStoresCreate(t *testing.T) {
+	server, fake := createNewServerWithBlobStores(t,
+		v1alpha1.BlobStore{Name: "local", Type: v1alpha1.FileBlobStoreType},
+		v1alpha1.BlobStore{
+			Name:      "on-volume",
+			Type:      v1alpha1.FileBlobStoreType,
+			File:      &v1alpha1.FileBlobStore{ExtraVolume: "blobs", Path: "npm"},
+			SoftQuota: &v1alpha1.BlobStoreSoftQuota{Type: v1alpha1.SpaceUsedQuotaType, Limit: resource.MustParse("10Gi")},
+		},
+		v1alpha1.BlobStore{
+			Name: "bucket",
+			Type: v1alpha1.S3BlobStoreType,
+			S3:   &v1alpha1.S3BlobStore{Bucket: "nexus-blobs", CredentialsSecret: &v1alpha1.S3CredentialsSecret{Name: "s3-credentials"}},
+		},
+	)
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+
+	assert.Equal(t, "local", fake.blobStores["local"]["path"])
+	assert.Equal(t, "/blobs/npm", fake.blobStores["on-volume"]["path"])
+	assert.Equal(t, map[string]interface{}{"type": "spaceUsedQuota", "limit": float64(10240)}, fake.blobStores["on-volume"]["softQuota"])
+	bucket := fake.blobStores["bucket"]["bucketConfiguration"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"region": defaultS3Region, "name": "nexus-blobs", "prefix": "", "expiration": float64(3)}, bucket["bucket"])
+	assert.Equal(t, map[string]interface{}{"accessKeyId": "AKIA", "secretAccessKey": "s3cr3t"}, bucket["bucketSecurity"])
+
+	assert.Len(t, server.status.BlobStores, 3)
+	for _, status := range server.status.BlobStores {
+		assert.True(t, status.Ready, status.Reason)
+		assert.True(t, status.Created)
+		assert.Equal(t, int64(10), status.BlobCount)
+		assert.Equal(t, int64(2048), status.TotalSizeInBytes)
+		assert.Equal(t, int64(4096), status.AvailableSpaceInBytes)
+	}
+
+	// nothing changed, the secrets the server never returns don't trigger updates
+	fake.requests = nil
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	for _, request := range fake.requests {
+		assert.NotEqual(t, http.MethodPut, request[:3], request)
+	}
+}
+
+func Test_blobStoreOperation_EnsureBlobStoresUpdate(t *testing.T) {
+	store := v1alpha1.BlobStore{
+		Name:      "local",
+		Type:      v1alpha1.FileBlobStoreType,
+		SoftQuota: &v1alpha1.BlobStoreSoftQuota{Type: v1alpha1.SpaceRemainingQuotaType, Limit: resource.MustParse("1Gi")},
+	}
+	server, fake := createNewServerWithBlobStores(t, store)
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+
+	// changed by hand
+	fake.blobStores["local"]["softQuota"] = map[string]interface{}{"type": "spaceRemainingQuota", "limit": 1}
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	assert.True(t, fake.requested("PUT /blobstores/file/local"))
+	assert.Equal(t, map[string]interface{}{"type": "spaceRemainingQuota", "limit": float64(1024)}, fake.blobStores["local"]["softQuota"])
+
+	// quota removed from the spec
+	server.nexus.Spec.BlobStores[0].SoftQuota = nil
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	assert.NotContains(t, fake.blobStores["local"], "softQuota")
+}
+
+func Test_blobStoreOperation_EnsureBlobStoresInvalid(t *testing.T) {
+	server, fake := createNewServerWithBlobStores(t,
+		v1alpha1.BlobStore{Name: "no-bucket", Type: v1alpha1.S3BlobStoreType},
+		v1alpha1.BlobStore{Name: "no-volume", Type: v1alpha1.FileBlobStoreType, File: &v1alpha1.FileBlobStore{ExtraVolume: "missing"}},
+	)
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	assert.Empty(t, fake.blobStores)
+	for _, status := range server.status.BlobStores {
+		assert.False(t, status.Ready)
+		assert.NotEmpty(t, status.Reason)
+	}
+}
+
+func Test_blobStoreOperation_EnsureBlobStoresTypeMismatch(t *testing.T) {
+	server, fake := createNewServerWithBlobStores(t, v1alpha1.BlobStore{Name: "bucket", Type: v1alpha1.S3BlobStoreType, S3: &v1alpha1.S3BlobStore{Bucket: "nexus-blobs"}})
+	fake.blobStores["bucket"] = map[string]interface{}{"name": "bucket", "type": "File", "path": "bucket"}
+
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	assert.False(t, server.status.BlobStores[0].Ready)
+	assert.Contains(t, server.status.BlobStores[0].Reason, "already exists")
+	assert.Equal(t, "File", fake.blobStores["bucket"]["type"])
+}
+
+func Test_blobStoreOperation_PruneBlobStores(t *testing.T) {
+	server, fake := createNewServerWithBlobStores(t, v1alpha1.BlobStore{Name: "created", Type: v1alpha1.FileBlobStoreType})
+	fake.blobStores["existing"] = map[string]interface{}{"name": "existing", "type": "File", "path": "existing"}
+	server.status.BlobStores = []v1alpha1.BlobStoreStatus{{Name: "existing", Type: v1alpha1.FileBlobStoreType}}
+	server.nexus.Spec.BlobStores = append(server.nexus.Spec.BlobStores, v1alpha1.BlobStore{Name: "existing", Type: v1alpha1.FileBlobStoreType})
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+
+	// both removed from the spec, only the one created by the Operator is removed from the server
+	server.nexus.Spec.BlobStores = nil
+	assert.NoError(t, blobStoreOperations(server).EnsureBlobStores())
+	assert.Len(t, server.status.BlobStores, 2)
+	fake.failures["DELETE /blobstores/created"] = http.StatusBadRequest
+	assert.Error(t, blobStoreOperations(server).PruneBlobStores())
+	assert.Contains(t, fake.blobStores, "created")
+	assert.Len(t, server.status.BlobStores, 1)
+	assert.False(t, server.status.BlobStores[0].Ready)
+
+	delete(fake.failures, "DELETE /blobstores/created")
+	assert.NoError(t, blobStoreOperations(server).PruneBlobStores())
+	assert.NotContains(t, fake.blobStores, "created")
+	assert.Contains(t, fake.blobStores, "existing")
+	assert.Empty(t, server.status.BlobStores)
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const cleanupPoliciesRESTPath = "/cleanup-policies"
+
+// apiCleanupPolicy is the representation of a cleanup policy sent to and read from the Nexus REST API.
+// Criteria not set are sent as null, so that criteria removed from the spec are caught when comparing with the server.
+type apiCleanupPolicy struct {
+	Name                    string  `json:"name"`
+	Notes                   string  `json:"notes"`
+	Format                  string  `json:"format"`
+	CriteriaLastBlobUpdated *int32  `json:"criteriaLastBlobUpdated"`
+	CriteriaLastDownloaded  *int32  `json:"criteriaLastDownloaded"`
+	CriteriaReleaseType     *string `json:"criteriaReleaseType"`
+	CriteriaAssetRegex      *string `json:"criteriaAssetRegex"`
+}
+
+// CleanupPolicyOperations describes the public operations in the cleanup policy domain for the Nexus instance
+type CleanupPolicyOperations interface {
+	// EnsureCleanupPolicies creates or updates the cleanup policies declared in `spec.cleanupPolicies`.
+	// Must be called before the repositories referencing them are handled.
+	EnsureCleanupPolicies() error
+	// PruneCleanupPolicies removes from the server the cleanup policies created by the Operator and no longer declared.
+	// Must be called after the repositories referencing them are updated.
+	PruneCleanupPolicies() error
+}
+
+type cleanupPolicyOperation struct {
+	server
+}
+
+func cleanupPolicyOperations(server *server) CleanupPolicyOperations {
+	return &cleanupPolicyOperation{server: *server}
+}
+
+func (c *cleanupPolicyOperation) EnsureCleanupPolicies() error {
+	previous := c.status.CleanupPolicies
+	if len(c.nexus.Spec.CleanupPolicies) == 0 && len(previous) == 0 {
+		log.Debug("No cleanup policies declared in 'spec.cleanupPolicies', skipping")
+		return nil
+	}
+
+	existing, err := c.fetchCleanupPolicies()
+	if err != nil {
+		return err
+	}
+	created := make(map[string]bool, len(previous))
+	for _, policy := range previous {
+		created[policy.Name] = policy.Created
+	}
+
+	var statuses []v1alpha1.CleanupPolicyStatus
+	var errs []string
+	declared := make(map[string]bool, len(c.nexus.Spec.CleanupPolicies))
+	for _, policy := range c.nexus.Spec.CleanupPolicies {
+		declared[policy.Name] = true
+		status, err := c.ensureCleanupPolicy(policy, existing, created[policy.Name])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		statuses = append(statuses, status)
+	}
+	// kept until pruned
+	for _, policy := range previous {
+		if !declared[policy.Name] {
+			statuses = append(statuses, policy)
+		}
+	}
+
+	c.status.CleanupPolicies = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to ensure cleanup policies: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *cleanupPolicyOperation) PruneCleanupPolicies() error {
+	declared := make(map[string]bool, len(c.nexus.Spec.CleanupPolicies))
+	for _, policy := range c.nexus.Spec.CleanupPolicies {
+		declared[policy.Name] = true
+	}
+
+	var statuses []v1alpha1.CleanupPolicyStatus
+	var errs []string
+	for _, policy := range c.status.CleanupPolicies {
+		if declared[policy.Name] {
+			statuses = append(statuses, policy)
+			continue
+		}
+		if !policy.Created {
+			log.Debug("Cleanup policy removed from the spec wasn't created by the Operator, won't remove it from the server", "CleanupPolicy", policy.Name)
+			continue
+		}
+		log.Debug("Trying to remove cleanup policy", "CleanupPolicy", policy.Name)
+		if err := c.restcli.delete(fmt.Sprintf("%s/%s", cleanupPoliciesRESTPath, policy.Name)); err != nil && !isRESTNotFound(err) {
+			// we keep it in the status to try again in the next reconciliation
+			policy.Ready = false
+			policy.Reason = fmt.Sprintf("Failed to remove cleanup policy from the server: %v", err)
+			statuses = append(statuses, policy)
+			errs = append(errs, err.Error())
+			continue
+		}
+		log.Info("Cleanup policy removed", "CleanupPolicy", policy.Name)
+	}
+
+	c.status.CleanupPolicies = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to remove cleanup policies: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (c *cleanupPolicyOperation) fetchCleanupPolicies() (map[string]map[string]interface{}, error) {
+	log.Debug("Attempt to fetch all cleanup policies from the server")
+	var fetched []map[string]interface{}
+	if err := c.restcli.get(cleanupPoliciesRESTPath, &fetched); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]map[string]interface{}, len(fetched))
+	for _, policy := range fetched {
+		if name, ok := policy["name"].(string); ok {
+			existing[name] = policy
+		}
+	}
+	return existing, nil
+}
+
+func (c *cleanupPolicyOperation) ensureCleanupPolicy(policy v1alpha1.CleanupPolicy, existing map[string]map[string]interface{}, created bool) (v1alpha1.CleanupPolicyStatus, error) {
+	status := v1alpha1.CleanupPolicyStatus{Name: policy.Name, Created: created}
+	if err := validateCleanupPolicy(policy); err != nil {
+		log.Warn("Invalid cleanup policy declared in 'spec.cleanupPolicies'", "CleanupPolicy", policy.Name, "Reason", err.Error())
+		status.Reason = err.Error()
+		return status, nil
+	}
+
+	desired := newAPICleanupPolicy(policy)
+	if actual, found := existing[policy.Name]; !found {
+		log.Debug("Trying to create cleanup policy", "CleanupPolicy", policy.Name)
+		if err := c.restcli.post(cleanupPoliciesRESTPath, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		status.Created = true
+		log.Info("Cleanup policy created", "CleanupPolicy", policy.Name)
+	} else if equal, err := jsonContains(actual, desired); err != nil {
+		status.Reason = err.Error()
+		return status, err
+	} else if !equal {
+		log.Debug("Cleanup policy differs from the desired state, trying to update it", "CleanupPolicy", policy.Name)
+		if err := c.restcli.put(cleanupPoliciesRESTPath+"/"+policy.Name, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		log.Info("Cleanup policy updated", "CleanupPolicy", policy.Name)
+	}
+	status.Ready = true
+	return status, nil
+}
+
+// validateCleanupPolicy verifies if the given cleanup policy can be created in the Nexus server
+func validateCleanupPolicy(policy v1alpha1.CleanupPolicy) error {
+	if policy.LastBlobUpdatedDays == nil && policy.LastDownloadedDays == nil && len(policy.ReleaseType) == 0 && len(policy.AssetRegex) == 0 {
+		return fmt.Errorf("cleanup policy %s must set at least one criteria", policy.Name)
+	}
+	if len(policy.ReleaseType) > 0 && policy.Format != v1alpha1.MavenRepositoryFormat && policy.Format != v1alpha1.NpmRepositoryFormat {
+		return fmt.Errorf("cleanup policy %s can't set 'releaseType' for the %s format", policy.Name, policy.Format)
+	}
+	return nil
+}
+
+// newAPICleanupPolicy converts the given cleanup policy into its Nexus REST API representation
+func newAPICleanupPolicy(policy v1alpha1.CleanupPolicy) apiCleanupPolicy {
+	apiPolicy := apiCleanupPolicy{
+		Name:                    policy.Name,
+		Notes:                   policy.Notes,
+		Format:                  string(policy.Format),
+		CriteriaLastBlobUpdated: policy.LastBlobUpdatedDays,
+		CriteriaLastDownloaded:  policy.LastDownloadedDays,
+	}
+	if len(policy.ReleaseType) > 0 {
+		releaseType := string(policy.ReleaseType)
+		apiPolicy.CriteriaReleaseType = &releaseType
+	}
+	if len(policy.AssetRegex) > 0 {
+		apiPolicy.CriteriaAssetRegex = &policy.AssetRegex
+	}
+	return apiPolicy
+}
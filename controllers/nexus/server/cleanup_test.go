@@ -0,0 +1,89 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+func Test_cleanupPolicyOperation_EnsureCleanupPoliciesNoPolicies(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	assert.NoError(t, cleanupPolicyOperations(server).EnsureCleanupPolicies())
+	assert.NoError(t, cleanupPolicyOperations(server).PruneCleanupPolicies())
+	assert.Empty(t, fake.requests)
+}
+
+func Test_cleanupPolicyOperation_EnsureCleanupPolicies(t *testing.T) {
+	days := int32(30)
+	server, fake := createNewServerWithFakeNexus(t)
+	server.nexus.Spec.CleanupPolicies = []v1alpha1.CleanupPolicy{
+		{Name: "old-snapshots", Format: v1alpha1.MavenRepositoryFormat, LastBlobUpdatedDays: &days, ReleaseType: v1alpha1.PrereleasesCleanupReleaseType},
+		{Name: "invalid", Format: v1alpha1.RawRepositoryFormat},
+	}
+	assert.NoError(t, cleanupPolicyOperations(server).EnsureCleanupPolicies())
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "old-snapshots", "notes": "", "format": "maven2", "criteriaLastBlobUpdated": float64(30),
+		"criteriaLastDownloaded": nil, "criteriaReleaseType": "PRERELEASES", "criteriaAssetRegex": nil,
+	}, fake.cleanupPolicies["old-snapshots"])
+	assert.NotContains(t, fake.cleanupPolicies, "invalid")
+	assert.Equal(t, []v1alpha1.CleanupPolicyStatus{
+		{Name: "old-snapshots", Ready: true, Created: true},
+		{Name: "invalid", Reason: "cleanup policy invalid must set at least one criteria"},
+	}, server.status.CleanupPolicies)
+
+	// nothing changed
+	fake.requests = nil
+	assert.NoError(t, cleanupPolicyOperations(server).EnsureCleanupPolicies())
+	assert.False(t, fake.requested("PUT /cleanup-policies/old-snapshots"))
+
+	// criteria removed from the spec
+	server.nexus.Spec.CleanupPolicies[0].ReleaseType = ""
+	assert.NoError(t, cleanupPolicyOperations(server).EnsureCleanupPolicies())
+	assert.True(t, fake.requested("PUT /cleanup-policies/old-snapshots"))
+	assert.Nil(t, fake.cleanupPolicies["old-snapshots"]["criteriaReleaseType"])
+}
+
+func Test_cleanupPolicyOperation_PruneCleanupPolicies(t *testing.T) {
+	days := int32(30)
+	server, fake := createNewServerWithFakeNexus(t)
+	fake.cleanupPolicies["existing"] = map[string]interface{}{"name": "existing", "format": "npm", "criteriaLastDownloaded": 30}
+	server.nexus.Spec.CleanupPolicies = []v1alpha1.CleanupPolicy{
+		{Name: "created", Format: v1alpha1.NpmRepositoryFormat, LastDownloadedDays: &days},
+		{Name: "existing", Format: v1alpha1.NpmRepositoryFormat, LastDownloadedDays: &days},
+	}
+	assert.NoError(t, cleanupPolicyOperations(server).EnsureCleanupPolicies())
+	assert.False(t, server.status.CleanupPolicies[1].Created)
+
+	server.nexus.Spec.CleanupPolicies = nil
+	assert.NoError(t, cleanupPolicyOperations(server).EnsureCleanupPolicies())
+	assert.Len(t, server.status.CleanupPolicies, 2)
+
+	fake.failures["DELETE /cleanup-policies/created"] = http.StatusInternalServerError
+	assert.Error(t, cleanupPolicyOperations(server).PruneCleanupPolicies())
+	assert.Len(t, server.status.CleanupPolicies, 1)
+	assert.False(t, server.status.CleanupPolicies[0].Ready)
+
+	delete(fake.failures, "DELETE /cleanup-policies/created")
+	assert.NoError(t, cleanupPolicyOperations(server).PruneCleanupPolicies())
+	assert.Empty(t, server.status.CleanupPolicies)
+	assert.NotContains(t, fake.cleanupPolicies, "created")
+	assert.Contains(t, fake.cleanupPolicies, "existing")
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/m88i/nexus-operator/pkg/cluster/kubernetes"
+)
+
+const (
+	passwordRotatedReason        = "OperatorPasswordRotated"
+	passwordRotationFailedReason = "OperatorPasswordRotationFailed"
+)
+
+func (s *server) createPasswordRotatedEvent() {
+	if s.scheme == nil {
+		return
+	}
+	if err := kubernetes.RaiseInfoEventf(s.nexus, s.scheme, s.k8sclient, passwordRotatedReason, "Password of the %s user rotated", operatorUsername); err != nil {
+		log.Error(err, "Unable to raise event for password rotation")
+	}
+}
+
+func (s *server) createPasswordRotationFailureEvent(cause error) {
+	if s.scheme == nil {
+		return
+	}
+	if err := kubernetes.RaiseWarnEventf(s.nexus, s.scheme, s.k8sclient, passwordRotationFailedReason, "Failed to rotate the password of the %s user: %v", operatorUsername, cause); err != nil {
+		log.Error(err, "Unable to raise event for failed password rotation")
+	}
+}
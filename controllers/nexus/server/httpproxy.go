@@ -0,0 +1,238 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const (
+	httpSettingsRESTPath = "/http"
+
+	httpProxyEnvKey  = "HTTP_PROXY"
+	httpsProxyEnvKey = "HTTPS_PROXY"
+	noProxyEnvKey    = "NO_PROXY"
+)
+
+// apiHTTPSettings is the representation of the server HTTP settings sent to and read from the Nexus REST API
+type apiHTTPSettings struct {
+	HTTPProxy     *apiProxyServer `json:"httpProxy"`
+	HTTPSProxy    *apiProxyServer `json:"httpsProxy"`
+	NonProxyHosts []string        `json:"nonProxyHosts"`
+}
+
+type apiProxyServer struct {
+	Enabled  bool              `json:"enabled"`
+	Host     string            `json:"host,omitempty"`
+	Port     int32             `json:"port,omitempty"`
+	AuthInfo *apiProxyAuthInfo `json:"authInfo,omitempty"`
+}
+
+type apiProxyAuthInfo struct {
+	Enabled  bool   `json:"enabled"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// proxyServer is a proxy server resolved either from the spec or from the Operator environment
+type proxyServer struct {
+	host        string
+	port        int32
+	credentials *credentials
+}
+
+// HTTPProxyOperations describes the public operations in the outbound HTTP proxy domain for the Nexus instance
+type HTTPProxyOperations interface {
+	// EnsureHTTPProxy configures the proxy servers used by the Nexus server for outbound requests,
+	// as declared in `spec.serverOperations.httpProxy` or in the Operator environment
+	EnsureHTTPProxy() error
+}
+
+type httpProxyOperation struct {
+	server
+}
+
+func httpProxyOperations(server *server) HTTPProxyOperations {
+	return &httpProxyOperation{server: *server}
+}
+
+func (h *httpProxyOperation) EnsureHTTPProxy() error {
+	desired, err := h.newAPIHTTPSettings()
+	if err != nil {
+		return err
+	}
+	if desired == nil {
+		log.Debug("No outbound proxy declared in 'spec.serverOperations.httpProxy' or in the Operator environment, skipping")
+		return nil
+	}
+
+	log.Debug("Attempt to fetch the HTTP settings from the server")
+	var actual map[string]interface{}
+	if err := h.restcli.get(httpSettingsRESTPath, &actual); err != nil {
+		return err
+	}
+	// the server never returns the passwords
+	comparable := apiHTTPSettings{
+		HTTPProxy:     withoutPassword(desired.HTTPProxy),
+		HTTPSProxy:    withoutPassword(desired.HTTPSProxy),
+		NonProxyHosts: desired.NonProxyHosts,
+	}
+	if equal, err := jsonContains(actual, comparable); err != nil || equal {
+		return err
+	}
+	log.Debug("Outbound proxy settings differ from the desired state, trying to update them")
+	if err := h.restcli.put(httpSettingsRESTPath, desired); err != nil {
+		return err
+	}
+	log.Info("Outbound proxy settings updated")
+	return nil
+}
+
+// newAPIHTTPSettings creates the desired HTTP settings, or nil if there's no proxy declared anywhere
+func (h *httpProxyOperation) newAPIHTTPSettings() (*apiHTTPSettings, error) {
+	opts := h.nexus.Spec.ServerOperations.HTTPProxy
+	if opts == nil {
+		opts = &v1alpha1.HTTPProxy{}
+	}
+	fromEnvironment := !opts.IgnoreEnvironment
+
+	httpProxy, err := h.resolveProxyServer(opts.HTTP, fromEnvironment, httpProxyEnvKey)
+	if err != nil {
+		return nil, err
+	}
+	httpsProxy, err := h.resolveProxyServer(opts.HTTPS, fromEnvironment, httpsProxyEnvKey)
+	if err != nil {
+		return nil, err
+	}
+	nonProxyHosts := opts.NonProxyHosts
+	if len(nonProxyHosts) == 0 && fromEnvironment {
+		nonProxyHosts = nonProxyHostsFromEnv()
+	}
+	if h.nexus.Spec.ServerOperations.HTTPProxy == nil && httpProxy == nil && httpsProxy == nil {
+		return nil, nil
+	}
+
+	settings := &apiHTTPSettings{
+		HTTPProxy:     newAPIProxyServer(httpProxy),
+		HTTPSProxy:    newAPIProxyServer(httpsProxy),
+		NonProxyHosts: []string{},
+	}
+	if len(nonProxyHosts) > 0 {
+		settings.NonProxyHosts = nonProxyHosts
+	}
+	return settings, nil
+}
+
+// resolveProxyServer reads the given proxy server from the spec, falling back to the given environment variable
+func (h *httpProxyOperation) resolveProxyServer(declared *v1alpha1.ProxyServer, fromEnvironment bool, envKey string) (*proxyServer, error) {
+	if declared != nil {
+		proxy := &proxyServer{host: declared.Host, port: declared.Port}
+		if declared.CredentialsSecret != nil {
+			creds, err := h.getCredentials(*declared.CredentialsSecret)
+			if err != nil {
+				return nil, err
+			}
+			proxy.credentials = &creds
+		}
+		return proxy, nil
+	}
+	if !fromEnvironment {
+		return nil, nil
+	}
+	return proxyServerFromEnv(envKey)
+}
+
+// proxyServerFromEnv parses the proxy URL in the given environment variable, also looking for its lower case version
+func proxyServerFromEnv(envKey string) (*proxyServer, error) {
+	value := getEnvAnyCase(envKey)
+	if len(value) == 0 {
+		return nil, nil
+	}
+	if !strings.Contains(value, "://") {
+		value = "http://" + value
+	}
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL in the %s environment variable: %v", envKey, err)
+	}
+	proxy := &proxyServer{host: proxyURL.Hostname()}
+	port := proxyURL.Port()
+	if len(port) == 0 {
+		port = "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	parsedPort, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy port in the %s environment variable: %v", envKey, err)
+	}
+	proxy.port = int32(parsedPort)
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		proxy.credentials = &credentials{username: proxyURL.User.Username(), password: password}
+	}
+	return proxy, nil
+}
+
+// nonProxyHostsFromEnv converts the NO_PROXY environment variable into the patterns understood by the Nexus server
+func nonProxyHostsFromEnv() []string {
+	var hosts []string
+	for _, host := range strings.Split(getEnvAnyCase(noProxyEnvKey), ",") {
+		host = strings.TrimSpace(host)
+		if len(host) == 0 {
+			continue
+		}
+		// ".example.com" means every subdomain of example.com
+		if strings.HasPrefix(host, ".") {
+			host = "*" + host
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+func getEnvAnyCase(key string) string {
+	if value := os.Getenv(key); len(value) > 0 {
+		return value
+	}
+	return os.Getenv(strings.ToLower(key))
+}
+
+func newAPIProxyServer(proxy *proxyServer) *apiProxyServer {
+	if proxy == nil {
+		return &apiProxyServer{Enabled: false}
+	}
+	apiProxy := &apiProxyServer{Enabled: true, Host: proxy.host, Port: proxy.port, AuthInfo: &apiProxyAuthInfo{Enabled: false}}
+	if proxy.credentials != nil {
+		apiProxy.AuthInfo = &apiProxyAuthInfo{Enabled: true, Username: proxy.credentials.username, Password: proxy.credentials.password}
+	}
+	return apiProxy
+}
+
+func withoutPassword(proxy *apiProxyServer) *apiProxyServer {
+	if proxy == nil || proxy.AuthInfo == nil {
+		return proxy
+	}
+	comparable := *proxy
+	comparable.AuthInfo = &apiProxyAuthInfo{Enabled: proxy.AuthInfo.Enabled, Username: proxy.AuthInfo.Username}
+	return &comparable
+}
@@ -0,0 +1,307 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/sha1"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/framework"
+	"github.com/m88i/nexus-operator/pkg/framework/kind"
+)
+
+const (
+	ldapServersRESTPath = "/security/ldap"
+	truststoreRESTPath  = "/security/ssl/truststore"
+
+	defaultLDAPPort                  = 389
+	defaultLDAPSPort                 = 636
+	defaultLDAPConnectionTimeout     = 30
+	defaultLDAPRetryDelay            = 300
+	defaultLDAPMaxIncidentsCount     = 3
+	defaultLDAPUserObjectClass       = "inetOrgPerson"
+	defaultLDAPUserIDAttribute       = "uid"
+	defaultLDAPUserRealNameAttribute = "cn"
+	defaultLDAPUserEmailAttribute    = "mail"
+	defaultLDAPGroupObjectClass      = "groupOfUniqueNames"
+	defaultLDAPGroupIDAttribute      = "cn"
+	defaultLDAPGroupMemberAttribute  = "uniqueMember"
+	defaultLDAPGroupMemberFormat     = "${dn}"
+	defaultLDAPMemberOfAttribute     = "memberOf"
+)
+
+// optionalLDAPAttributes are left out of the requests when not declared, their removal from the spec must be checked by hand
+var optionalLDAPAttributes = []string{"authRealm", "authUsername", "userBaseDn", "userLdapFilter", "userPasswordAttribute", "groupBaseDn"}
+
+// apiLDAPServer is the representation of a LDAP server configuration sent to and read from the Nexus REST API
+type apiLDAPServer struct {
+	ID                          string `json:"id,omitempty"`
+	Name                        string `json:"name"`
+	Protocol                    string `json:"protocol"`
+	UseTrustStore               bool   `json:"useTrustStore"`
+	Host                        string `json:"host"`
+	Port                        int32  `json:"port"`
+	SearchBase                  string `json:"searchBase"`
+	AuthScheme                  string `json:"authScheme"`
+	AuthRealm                   string `json:"authRealm,omitempty"`
+	AuthUsername                string `json:"authUsername,omitempty"`
+	AuthPassword                string `json:"authPassword,omitempty"`
+	ConnectionTimeoutSeconds    int32  `json:"connectionTimeoutSeconds"`
+	ConnectionRetryDelaySeconds int32  `json:"connectionRetryDelaySeconds"`
+	MaxIncidentsCount           int32  `json:"maxIncidentsCount"`
+	UserBaseDN                  string `json:"userBaseDn,omitempty"`
+	UserSubtree                 bool   `json:"userSubtree"`
+	UserObjectClass             string `json:"userObjectClass"`
+	UserLDAPFilter              string `json:"userLdapFilter,omitempty"`
+	UserIDAttribute             string `json:"userIdAttribute"`
+	UserRealNameAttribute       string `json:"userRealNameAttribute"`
+	UserEmailAddressAttribute   string `json:"userEmailAddressAttribute"`
+	UserPasswordAttribute       string `json:"userPasswordAttribute,omitempty"`
+	LDAPGroupsAsRoles           bool   `json:"ldapGroupsAsRoles"`
+	GroupType                   string `json:"groupType,omitempty"`
+	GroupBaseDN                 string `json:"groupBaseDn,omitempty"`
+	GroupSubtree                bool   `json:"groupSubtree,omitempty"`
+	GroupObjectClass            string `json:"groupObjectClass,omitempty"`
+	GroupIDAttribute            string `json:"groupIdAttribute,omitempty"`
+	GroupMemberAttribute        string `json:"groupMemberAttribute,omitempty"`
+	GroupMemberFormat           string `json:"groupMemberFormat,omitempty"`
+	UserMemberOfAttribute       string `json:"userMemberOfAttribute,omitempty"`
+}
+
+// apiCertificate is the representation of a certificate in the Nexus server truststore
+type apiCertificate struct {
+	ID          string `json:"id,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	PEM         string `json:"pem"`
+}
+
+// ensureLDAPServer makes sure that the given LDAP server is configured in the Nexus server as declared, reverting any change made by hand
+func (r *realmOperation) ensureLDAPServer(ldap v1alpha1.LDAPServer) error {
+	desired, err := r.newAPILDAPServer(ldap)
+	if err != nil {
+		return err
+	}
+	if ldap.Connection.CACertificate != nil {
+		if err := r.ensureTrustedCertificates(*ldap.Connection.CACertificate); err != nil {
+			return err
+		}
+	}
+
+	path := fmt.Sprintf("%s/%s", ldapServersRESTPath, url.PathEscape(desired.Name))
+	log.Debug("Attempt to fetch the LDAP server from the server", "LDAPServer", desired.Name)
+	var actual map[string]interface{}
+	if err := r.restcli.get(path, &actual); err != nil {
+		if !isRESTNotFound(err) {
+			return err
+		}
+		log.Debug("LDAP server not found, trying to create it", "LDAPServer", desired.Name)
+		if err := r.restcli.post(ldapServersRESTPath, desired); err != nil {
+			return err
+		}
+		log.Info("LDAP server created", "LDAPServer", desired.Name)
+		return nil
+	}
+
+	// the server never returns the bind password
+	comparable := *desired
+	comparable.AuthPassword = ""
+	if equal, err := jsonContains(actual, comparable); err != nil {
+		return err
+	} else if equal && !hasClearedLDAPAttributes(actual, comparable) {
+		return nil
+	}
+	log.Debug("LDAP server differs from the desired state, trying to update it", "LDAPServer", desired.Name)
+	if id, ok := actual["id"].(string); ok {
+		desired.ID = id
+	}
+	if err := r.restcli.put(path, desired); err != nil {
+		return err
+	}
+	log.Info("LDAP server updated", "LDAPServer", desired.Name)
+	return nil
+}
+
+// newAPILDAPServer converts the given LDAP server into its API representation, filling in the defaults and the bind credentials
+func (r *realmOperation) newAPILDAPServer(ldap v1alpha1.LDAPServer) (*apiLDAPServer, error) {
+	conn := ldap.Connection
+	server := &apiLDAPServer{
+		Name:                        stringOrDefault(ldap.Name, v1alpha1.DefaultLDAPServerName),
+		Protocol:                    string(conn.Protocol),
+		UseTrustStore:               conn.CACertificate != nil,
+		Host:                        conn.Host,
+		Port:                        conn.Port,
+		SearchBase:                  conn.SearchBase,
+		AuthScheme:                  string(conn.AuthScheme),
+		AuthRealm:                   conn.AuthRealm,
+		ConnectionTimeoutSeconds:    int32OrDefault(conn.ConnectionTimeoutSeconds, defaultLDAPConnectionTimeout),
+		ConnectionRetryDelaySeconds: int32OrDefault(conn.RetryDelaySeconds, defaultLDAPRetryDelay),
+		MaxIncidentsCount:           int32OrDefault(conn.MaxIncidentsCount, defaultLDAPMaxIncidentsCount),
+		UserBaseDN:                  ldap.UserMapping.BaseDN,
+		UserSubtree:                 ldap.UserMapping.Subtree,
+		UserObjectClass:             stringOrDefault(ldap.UserMapping.ObjectClass, defaultLDAPUserObjectClass),
+		UserLDAPFilter:              ldap.UserMapping.LDAPFilter,
+		UserIDAttribute:             stringOrDefault(ldap.UserMapping.IDAttribute, defaultLDAPUserIDAttribute),
+		UserRealNameAttribute:       stringOrDefault(ldap.UserMapping.RealNameAttribute, defaultLDAPUserRealNameAttribute),
+		UserEmailAddressAttribute:   stringOrDefault(ldap.UserMapping.EmailAddressAttribute, defaultLDAPUserEmailAttribute),
+		UserPasswordAttribute:       ldap.UserMapping.PasswordAttribute,
+	}
+	if len(server.Protocol) == 0 {
+		server.Protocol = string(v1alpha1.LDAPProtocolLDAP)
+	}
+	if server.Port == 0 {
+		server.Port = defaultLDAPPort
+		if server.Protocol == string(v1alpha1.LDAPProtocolLDAPS) {
+			server.Port = defaultLDAPSPort
+		}
+	}
+	if len(server.AuthScheme) == 0 {
+		server.AuthScheme = string(v1alpha1.LDAPAuthSchemeNone)
+		if conn.BindCredentialsSecret != nil {
+			server.AuthScheme = string(v1alpha1.LDAPAuthSchemeSimple)
+		}
+	}
+	if server.AuthScheme != string(v1alpha1.LDAPAuthSchemeNone) {
+		if conn.BindCredentialsSecret == nil {
+			return nil, fmt.Errorf("'spec.security.ldap.connection.bindCredentialsSecret' is required by the %s auth scheme", server.AuthScheme)
+		}
+		creds, err := r.getCredentials(*conn.BindCredentialsSecret)
+		if err != nil {
+			return nil, err
+		}
+		server.AuthUsername, server.AuthPassword = creds.username, creds.password
+	}
+
+	if groups := ldap.GroupMapping; groups != nil {
+		server.LDAPGroupsAsRoles = true
+		server.GroupType = string(groups.Type)
+		if groups.Type == v1alpha1.LDAPGroupTypeDynamic {
+			server.UserMemberOfAttribute = stringOrDefault(groups.MemberOfAttribute, defaultLDAPMemberOfAttribute)
+		} else {
+			server.GroupBaseDN = groups.BaseDN
+			server.GroupSubtree = groups.Subtree
+			server.GroupObjectClass = stringOrDefault(groups.ObjectClass, defaultLDAPGroupObjectClass)
+			server.GroupIDAttribute = stringOrDefault(groups.IDAttribute, defaultLDAPGroupIDAttribute)
+			server.GroupMemberAttribute = stringOrDefault(groups.MemberAttribute, defaultLDAPGroupMemberAttribute)
+			server.GroupMemberFormat = stringOrDefault(groups.MemberFormat, defaultLDAPGroupMemberFormat)
+		}
+	}
+	return server, nil
+}
+
+// hasClearedLDAPAttributes checks if an optional attribute no longer declared is still set in the server
+func hasClearedLDAPAttributes(actual map[string]interface{}, desired apiLDAPServer) bool {
+	var desiredJSON map[string]interface{}
+	if err := roundTripJSON(desired, &desiredJSON); err != nil {
+		return true
+	}
+	for _, attribute := range optionalLDAPAttributes {
+		if _, declared := desiredJSON[attribute]; declared {
+			continue
+		}
+		if value, ok := actual[attribute].(string); ok && len(value) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureTrustedCertificates adds the given certificates to the Nexus server truststore if they're not there yet
+func (r *realmOperation) ensureTrustedCertificates(source v1alpha1.CertificateSource) error {
+	data, err := r.readCertificates(source)
+	if err != nil {
+		return err
+	}
+	var trusted []apiCertificate
+	if err := r.restcli.get(truststoreRESTPath, &trusted); err != nil {
+		return err
+	}
+	for rest := []byte(data); ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		fingerprint := certificateFingerprint(block.Bytes)
+		if isCertificateTrusted(trusted, fingerprint) {
+			continue
+		}
+		log.Debug("Certificate not in the truststore, trying to add it", "Fingerprint", fingerprint)
+		if err := r.restcli.postPEM(truststoreRESTPath, string(pem.EncodeToMemory(block))); err != nil {
+			return err
+		}
+		log.Info("Certificate added to the truststore", "Fingerprint", fingerprint)
+	}
+}
+
+// readCertificates reads the PEM encoded certificates from the referenced Secret or ConfigMap
+func (r *realmOperation) readCertificates(source v1alpha1.CertificateSource) (string, error) {
+	var data, name string
+	var optional *bool
+	var err error
+	switch {
+	case source.SecretKeyRef != nil:
+		secret := &corev1.Secret{}
+		name, optional = source.SecretKeyRef.Name, source.SecretKeyRef.Optional
+		if err = framework.Fetch(r.k8sclient, types.NamespacedName{Namespace: r.nexus.Namespace, Name: name}, secret, kind.SecretKind); err == nil {
+			data = string(secret.Data[source.SecretKeyRef.Key])
+		}
+	case source.ConfigMapKeyRef != nil:
+		configMap := &corev1.ConfigMap{}
+		name, optional = source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Optional
+		if err = framework.Fetch(r.k8sclient, types.NamespacedName{Namespace: r.nexus.Namespace, Name: name}, configMap, kind.ConfigMapKind); err == nil {
+			data = configMap.Data[source.ConfigMapKeyRef.Key]
+		}
+	default:
+		return "", fmt.Errorf("either a Secret or a ConfigMap must be referenced by the CA certificate")
+	}
+	isOptional := optional != nil && *optional
+	if err != nil && !(errors.IsNotFound(err) && isOptional) {
+		return "", fmt.Errorf("failed to fetch the CA certificate from %s: %v", name, err)
+	}
+	if len(strings.TrimSpace(data)) == 0 && !isOptional {
+		return "", fmt.Errorf("no CA certificate found in %s", name)
+	}
+	return data, nil
+}
+
+// certificateFingerprint calculates the SHA-1 fingerprint of the given DER encoded certificate in the format used by the Nexus server
+func certificateFingerprint(der []byte) string {
+	sum := sha1.Sum(der)
+	hexBytes := make([]string, len(sum))
+	for i, b := range sum {
+		hexBytes[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hexBytes, ":")
+}
+
+func isCertificateTrusted(trusted []apiCertificate, fingerprint string) bool {
+	for _, certificate := range trusted {
+		if strings.EqualFold(certificate.Fingerprint, fingerprint) {
+			return true
+		}
+	}
+	return false
+}
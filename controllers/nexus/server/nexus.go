@@ -16,11 +16,13 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	nexusapi "github.com/m88i/aicura/nexus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -31,8 +33,13 @@ import (
 type server struct {
 	nexus     *v1alpha1.Nexus
 	k8sclient client.Client
-	nexuscli  *nexusapi.Client
-	status    *v1alpha1.OperationsStatus
+	// used to raise events, might be nil when there's nothing to tell
+	scheme   *runtime.Scheme
+	nexuscli *nexusapi.Client
+	restcli  *restClient
+	status   *v1alpha1.OperationsStatus
+	// credentials used to bootstrap the server operations
+	admin credentials
 }
 
 const (
@@ -42,11 +49,15 @@ const (
 	serverURLEnvKey = "NEXUS_SERVER_URL"
 )
 
-func handleServerOperations(nexus *v1alpha1.Nexus, client client.Client, nexusAPIBuilder func(url, user, pass string) *nexusapi.Client) (v1alpha1.OperationsStatus, error) {
-	s := server{nexus: nexus, k8sclient: client, status: &v1alpha1.OperationsStatus{}}
-	if nexus.Spec.GenerateRandomAdminPassword {
-		return *s.status, nil
-	}
+func handleServerOperations(nexus *v1alpha1.Nexus, scheme *runtime.Scheme, client client.Client, nexusAPIBuilder func(url, user, pass string) *nexusapi.Client) (v1alpha1.OperationsStatus, error) {
+	// the repositories previously managed are required to know which ones must be removed from the server
+	s := server{nexus: nexus, k8sclient: client, scheme: scheme, status: &v1alpha1.OperationsStatus{
+		Repositories:        nexus.Status.ServerOperationsStatus.Repositories,
+		BlobStores:          nexus.Status.ServerOperationsStatus.BlobStores,
+		CleanupPolicies:     nexus.Status.ServerOperationsStatus.CleanupPolicies,
+		Tasks:               nexus.Status.ServerOperationsStatus.Tasks,
+		OnboardingCompleted: nexus.Status.ServerOperationsStatus.OnboardingCompleted,
+	}}
 	log.Debug("Initializing server operations")
 	if s.isServerReady() {
 		internalEndpoint, err := s.getNexusEndpoint()
@@ -55,30 +66,182 @@ func handleServerOperations(nexus *v1alpha1.Nexus, client client.Client, nexusAP
 			s.status.ServerReady = false
 			return *s.status, nil
 		}
-		s.nexuscli = nexusAPIBuilder(internalEndpoint, defaultAdminUsername, defaultAdminPassword)
+		s.restcli = newRESTClient(internalEndpoint, defaultAdminUsername, defaultAdminPassword)
+		if err := s.ensureAdminCredentials(); err != nil {
+			s.status.Reason = err.Error()
+			if err == errGeneratedAdminPasswordUnavailable {
+				// nothing to do until the user provides the admin credentials
+				log.Warn("Skipping server operations", "Reason", err.Error())
+				return *s.status, nil
+			}
+			return *s.status, err
+		}
+		s.nexuscli = nexusAPIBuilder(internalEndpoint, s.admin.username, s.admin.password)
+		s.restcli.SetCredentials(s.admin.username, s.admin.password)
 
 		if err := userOperations(&s).EnsureOperatorUser(); err != nil {
 			s.status.Reason = err.Error()
 			return *s.status, err
 		}
+		if err := realmOperations(&s).EnsureRealms(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := realmOperations(&s).EnsureAnonymousAccess(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := httpProxyOperations(&s).EnsureHTTPProxy(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
 		if err := repositoryOperations(&s).EnsureCommunityMavenProxies(); err != nil {
 			s.status.Reason = err.Error()
 			return *s.status, err
 		}
+		if err := blobStoreOperations(&s).EnsureBlobStores(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := cleanupPolicyOperations(&s).EnsureCleanupPolicies(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := repositoryOperations(&s).EnsureRepositories(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := taskOperations(&s).EnsureTasks(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := cleanupPolicyOperations(&s).PruneCleanupPolicies(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
+		if err := blobStoreOperations(&s).PruneBlobStores(); err != nil {
+			s.status.Reason = err.Error()
+			return *s.status, err
+		}
 		s.status.Reason = ""
 	}
 	return *s.status, nil
 }
 
 // HandleServerOperations makes all required operations in the Nexus server side, such as creating the operator user
-func HandleServerOperations(nexus *v1alpha1.Nexus, client client.Client) (v1alpha1.OperationsStatus, error) {
+func HandleServerOperations(nexus *v1alpha1.Nexus, scheme *runtime.Scheme, client client.Client) (v1alpha1.OperationsStatus, error) {
 	log = logger.GetLoggerWithResource(defaultLogName, nexus)
 	defer func() { log = logger.GetLogger(defaultLogName) }()
-	return handleServerOperations(nexus, client, func(url, user, pass string) *nexusapi.Client {
-		return nexusapi.NewClient(url).WithCredentials(user, pass).Build()
+	return handleServerOperations(nexus, scheme, client, func(url, user, pass string) *nexusapi.Client {
+		return nexusapi.NewClient(url).WithCredentials(user, pass).WithHTTPClient(serverHTTPClient).Build()
 	})
 }
 
+// setCredentials sets the credentials used by every client to authenticate against the Nexus server
+func (s *server) setCredentials(user, pass string) {
+	s.nexuscli.SetCredentials(user, pass)
+	if s.restcli != nil {
+		s.restcli.SetCredentials(user, pass)
+	}
+}
+
+// ErrServerNotReady is returned when an operation is requested to a Nexus server that can't receive requests yet
+var ErrServerNotReady = errors.New("nexus server is not ready")
+
+// HandleRepository makes sure that the given repository exists as declared in the Nexus server deployed by the given instance.
+// "created" must be true if the repository was created by the Operator in a previous call.
+func HandleRepository(nexus *v1alpha1.Nexus, repository v1alpha1.Repository, created bool, client client.Client) (v1alpha1.RepositoryStatus, error) {
+	log = logger.GetLoggerWithResource(defaultLogName, nexus)
+	defer func() { log = logger.GetLogger(defaultLogName) }()
+	s, err := newOperatorServer(nexus, client)
+	if err != nil {
+		return v1alpha1.RepositoryStatus{Name: repository.Name, Format: repository.Format, Type: repository.Type, Created: created, Reason: err.Error()}, err
+	}
+	return repositoryOperations(s).EnsureRepository(repository, created)
+}
+
+// RemoveRepository removes the given repository from the Nexus server deployed by the given instance
+func RemoveRepository(nexus *v1alpha1.Nexus, name string, client client.Client) error {
+	log = logger.GetLoggerWithResource(defaultLogName, nexus)
+	defer func() { log = logger.GetLogger(defaultLogName) }()
+	s, err := newOperatorServer(nexus, client)
+	if err != nil {
+		return err
+	}
+	return repositoryOperations(s).RemoveRepository(name)
+}
+
+// HandleUser makes sure that the given user exists as declared in the Nexus server deployed by the given instance.
+// Returns true if the user has been created.
+func HandleUser(nexus *v1alpha1.Nexus, user v1alpha1.NexusUserSpec, password string, client client.Client) (bool, error) {
+	log = logger.GetLoggerWithResource(defaultLogName, nexus)
+	defer func() { log = logger.GetLogger(defaultLogName) }()
+	s, err := newOperatorServer(nexus, client)
+	if err != nil {
+		return false, err
+	}
+	return securityOperations(s).EnsureUser(user, password)
+}
+
+// RemoveUser removes the given user from the Nexus server deployed by the given instance
+func RemoveUser(nexus *v1alpha1.Nexus, userID string, client client.Client) error {
+	log = logger.GetLoggerWithResource(defaultLogName, nexus)
+	defer func() { log = logger.GetLogger(defaultLogName) }()
+	s, err := newOperatorServer(nexus, client)
+	if err != nil {
+		return err
+	}
+	return securityOperations(s).RemoveUser(userID)
+}
+
+// HandleRole makes sure that the given role exists as declared in the Nexus server deployed by the given instance.
+// Returns true if the role has been created.
+func HandleRole(nexus *v1alpha1.Nexus, role v1alpha1.NexusRoleSpec, client client.Client) (bool, error) {
+	log = logger.GetLoggerWithResource(defaultLogName, nexus)
+	defer func() { log = logger.GetLogger(defaultLogName) }()
+	s, err := newOperatorServer(nexus, client)
+	if err != nil {
+		return false, err
+	}
+	return securityOperations(s).EnsureRole(role)
+}
+
+// RemoveRole removes the given role from the Nexus server deployed by the given instance
+func RemoveRole(nexus *v1alpha1.Nexus, roleID string, client client.Client) error {
+	log = logger.GetLoggerWithResource(defaultLogName, nexus)
+	defer func() { log = logger.GetLogger(defaultLogName) }()
+	s, err := newOperatorServer(nexus, client)
+	if err != nil {
+		return err
+	}
+	return securityOperations(s).RemoveRole(roleID)
+}
+
+// newOperatorServer creates a new server authenticated with the operator user credentials, falling back to the admin ones
+func newOperatorServer(nexus *v1alpha1.Nexus, client client.Client) (*server, error) {
+	s := &server{nexus: nexus, k8sclient: client, status: &v1alpha1.OperationsStatus{}}
+	if !s.isServerReady() {
+		return nil, ErrServerNotReady
+	}
+	internalEndpoint, err := s.getNexusEndpoint()
+	if err != nil {
+		return nil, fmt.Errorf("impossible to resolve endpoint for Nexus instance %s: %v", nexus.Name, err)
+	}
+	user, pass, err := s.getOperatorUserCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if len(user) == 0 || len(pass) == 0 {
+		admin, err := s.getAdminCredentials()
+		if err != nil {
+			return nil, err
+		}
+		user, pass = admin.username, admin.password
+	}
+	s.restcli = newRESTClient(internalEndpoint, user, pass)
+	return s, nil
+}
+
 func (s *server) getNexusEndpoint() (string, error) {
 	externalURL := os.Getenv(serverURLEnvKey)
 	if len(externalURL) > 0 {
@@ -89,7 +252,7 @@ func (s *server) getNexusEndpoint() (string, error) {
 	if err := s.k8sclient.Get(context.TODO(), types.NamespacedName{Name: s.nexus.Name, Namespace: s.nexus.Namespace}, svc); err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("http://%s.%s", svc.Name, svc.Namespace), nil
+	return fmt.Sprintf("http://%s.%s%s", svc.Name, svc.Namespace, s.nexus.ContextPath()), nil
 }
 
 // isServerReady checks if the given Nexus instance is ready to receive requests
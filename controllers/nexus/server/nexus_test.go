@@ -16,6 +16,7 @@ package server
 
 import (
 	"net/url"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,8 +46,10 @@ func createNewServerAndKubeCli(t *testing.T, objects ...runtime.Object) (*server
 	server := &server{
 		nexus:     nexusInstance,
 		k8sclient: cli,
+		scheme:    cli.Scheme(),
 		nexuscli:  nexus.NewFakeClient(),
 		status:    &v1alpha1.OperationsStatus{},
+		admin:     defaultAdminCredentials,
 	}
 
 	return server, cli
@@ -89,6 +92,12 @@ func Test_server_getNexusEndpoint(t *testing.T) {
 	assert.Contains(t, URL, instance.Name)
 	_, err = url.Parse(URL)
 	assert.NoError(t, err)
+
+	// the context path is part of the endpoint
+	instance.Spec.Networking.ContextPath = "/nexus"
+	URL, err = s.getNexusEndpoint()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nexus3."+t.Name()+"/nexus", URL)
 }
 
 func Test_server_getNexusEndpointNoURL(t *testing.T) {
@@ -137,12 +146,15 @@ func Test_HandleServerOperationsNoFake(t *testing.T) {
 	}
 	cli := test.NewFakeClientBuilder(instance).Build()
 
-	status, err := HandleServerOperations(instance, cli)
+	status, err := HandleServerOperations(instance, cli.Scheme(), cli)
 	assert.NoError(t, err)
 	assert.False(t, status.ServerReady)
 }
 
 func Test_handleServerOperations(t *testing.T) {
+	fake := newFakeNexusServer(t)
+	assert.NoError(t, os.Setenv(serverURLEnvKey, fake.URL))
+	defer os.Unsetenv(serverURLEnvKey)
 	instance := &v1alpha1.Nexus{
 		Spec:       v1alpha1.NexusSpec{},
 		ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
@@ -170,11 +182,12 @@ func Test_handleServerOperations(t *testing.T) {
 		},
 	}
 	cli := test.NewFakeClientBuilder(instance, svc, &corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace}}).Build()
-	status, err := handleServerOperations(instance, cli, nexusAPIFakeBuilder)
+	status, err := handleServerOperations(instance, cli.Scheme(), cli, nexusAPIFakeBuilder)
 	assert.NoError(t, err)
 	assert.NotNil(t, status)
 	assert.True(t, status.CommunityRepositoriesCreated)
 	assert.True(t, status.OperatorUserCreated)
+	assert.Contains(t, fake.users, operatorUsername)
 	assert.True(t, status.ServerReady)
 	// see: https://github.com/m88i/aicura/issues/18
 	assert.False(t, status.MavenCentralUpdated)
@@ -191,7 +204,7 @@ func Test_handleServerOperationsNoEndpoint(t *testing.T) {
 		},
 	}
 	cli := test.NewFakeClientBuilder(instance).Build()
-	status, err := handleServerOperations(instance, cli, nexusAPIFakeBuilder)
+	status, err := handleServerOperations(instance, cli.Scheme(), cli, nexusAPIFakeBuilder)
 	assert.NoError(t, err)
 	assert.NotNil(t, status)
 	assert.False(t, status.CommunityRepositoriesCreated)
@@ -201,3 +214,39 @@ func Test_handleServerOperationsNoEndpoint(t *testing.T) {
 	// see: https://github.com/m88i/aicura/issues/18
 	assert.False(t, status.MavenCentralUpdated)
 }
+
+func Test_HandleRepository(t *testing.T) {
+	fake := newFakeNexusServer(t)
+	assert.NoError(t, os.Setenv(serverURLEnvKey, fake.URL))
+	defer os.Unsetenv(serverURLEnvKey)
+	instance := &v1alpha1.Nexus{
+		ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
+		Status:     v1alpha1.NexusStatus{DeploymentStatus: appv1.DeploymentStatus{AvailableReplicas: 1}},
+	}
+	cli := test.NewFakeClientBuilder(instance, &corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace}}).Build()
+	repository := v1alpha1.Repository{Name: "raw-hosted", Format: v1alpha1.RawRepositoryFormat, Type: v1alpha1.HostedRepositoryType}
+
+	status, err := HandleRepository(instance, repository, false, cli)
+	assert.NoError(t, err)
+	assert.True(t, status.Ready)
+	assert.True(t, status.Created)
+	assert.Equal(t, fake.URL+"/repository/raw-hosted/", status.URL)
+	assert.Contains(t, fake.repositories, "raw-hosted")
+
+	assert.NoError(t, RemoveRepository(instance, repository.Name, cli))
+	assert.NotContains(t, fake.repositories, "raw-hosted")
+	// removing a repository that doesn't exist is not an error
+	assert.NoError(t, RemoveRepository(instance, repository.Name, cli))
+}
+
+func Test_HandleRepositoryServerNotReady(t *testing.T) {
+	instance := &v1alpha1.Nexus{ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}}
+	cli := test.NewFakeClientBuilder(instance).Build()
+	repository := v1alpha1.Repository{Name: "raw-hosted", Format: v1alpha1.RawRepositoryFormat, Type: v1alpha1.HostedRepositoryType}
+
+	status, err := HandleRepository(instance, repository, true, cli)
+	assert.Equal(t, ErrServerNotReady, err)
+	assert.False(t, status.Ready)
+	assert.True(t, status.Created)
+	assert.Equal(t, ErrServerNotReady, RemoveRepository(instance, repository.Name, cli))
+}
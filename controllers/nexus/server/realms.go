@@ -0,0 +1,131 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/util"
+)
+
+const activeRealmsRESTPath = "/security/realms/active"
+
+// RealmOperations describes the public operations in the security realms domain for the Nexus instance
+type RealmOperations interface {
+	// EnsureRealms configures the authentication sources declared in `spec.security` and makes sure their realms are active,
+	// in the order declared in `spec.security.realms` if any
+	EnsureRealms() error
+	// EnsureAnonymousAccess makes sure that the anonymous access matches `spec.security.anonymousAccess`, if declared
+	EnsureAnonymousAccess() error
+}
+
+type realmOperation struct {
+	server
+}
+
+func realmOperations(server *server) RealmOperations {
+	return &realmOperation{server: *server}
+}
+
+func (r *realmOperation) EnsureRealms() error {
+	security := r.nexus.Spec.Security
+	// realms required by the authentication sources, enabled even if left out of `spec.security.realms`
+	var required []string
+	if security.LDAP != nil {
+		if err := r.ensureLDAPServer(*security.LDAP); err != nil {
+			return err
+		}
+		r.status.LDAPServerConfigured = true
+		required = append(required, v1alpha1.LDAPRealm)
+	}
+	if security.SAML != nil {
+		if err := r.ensureSAML(*security.SAML); err != nil {
+			return err
+		}
+		r.status.SAMLConfigured = true
+		required = append(required, v1alpha1.SAMLRealm)
+	}
+	// the oauth2-proxy sidecar is useless if the server doesn't trust the user ID it sends
+	rutAuthRequired := security.RUTAuth != nil || security.OAuth2Proxy != nil
+	if rutAuthRequired {
+		rutAuth := v1alpha1.RUTAuth{}
+		if security.RUTAuth != nil {
+			rutAuth = *security.RUTAuth
+		}
+		if err := r.ensureRUTAuth(rutAuth); err != nil {
+			return err
+		}
+		r.status.RUTAuthConfigured = true
+		required = append(required, v1alpha1.RUTAuthRealm)
+	}
+
+	if len(security.Realms) == 0 && len(required) == 0 {
+		log.Debug("No realms declared in 'spec.security', skipping")
+		return nil
+	}
+	if err := r.ensureActiveRealms(security.Realms, required); err != nil {
+		return err
+	}
+	r.status.LDAPRealmEnabled = security.LDAP != nil
+	r.status.SAMLRealmEnabled = security.SAML != nil
+	r.status.RUTAuthRealmEnabled = rutAuthRequired
+	return nil
+}
+
+// ensureActiveRealms makes sure that the active realms are the declared ones, in the same order, followed by the required ones left out.
+// The realm of the users stored in the database is always kept, first if left out.
+// If no realms are declared, the required ones are appended to the active ones, keeping the current order.
+func (r *realmOperation) ensureActiveRealms(declared, required []string) error {
+	var active []string
+	if err := r.restcli.get(activeRealmsRESTPath, &active); err != nil {
+		return err
+	}
+	desired := append([]string{}, declared...)
+	if len(declared) == 0 {
+		desired = append(desired, active...)
+	} else if !util.ContainsString(desired, v1alpha1.NexusAuthenticatingRealm) {
+		// the operator signs in with the admin user stored in the database, disabling this realm would lock it out
+		log.Warn("Realm left out of 'spec.security.realms', keeping it first", "realm", v1alpha1.NexusAuthenticatingRealm)
+		desired = append([]string{v1alpha1.NexusAuthenticatingRealm}, desired...)
+	}
+	for _, realm := range required {
+		if !util.ContainsString(desired, realm) {
+			desired = append(desired, realm)
+		}
+	}
+	if equalLists(active, desired) {
+		r.status.ActiveRealms = active
+		return nil
+	}
+	log.Debug("Active realms differ from the desired state, trying to update them", "Active", active, "Desired", desired)
+	if err := r.restcli.put(activeRealmsRESTPath, desired); err != nil {
+		return err
+	}
+	r.status.ActiveRealms = desired
+	log.Info("Active realms updated", "Realms", desired)
+	return nil
+}
+
+// equalLists compares both lists taking the order into account, unlike equalSets
+func equalLists(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,328 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+// newTestCertificate creates a self-signed PEM encoded certificate
+func newTestCertificate(t *testing.T, commonName string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// createNewServerWithLDAPSecrets creates a new server pointing to a fake Nexus server with the Secret and the ConfigMap used by the LDAP server
+func createNewServerWithLDAPSecrets(t *testing.T, caBundle string) (*server, *fakeNexusServer) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ldap-bind", Namespace: t.Name()},
+		Data:       map[string][]byte{"username": []byte("cn=nexus,dc=example,dc=com"), "password": []byte("s3cr3t")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ldap-ca", Namespace: t.Name()},
+		Data:       map[string]string{"ca.crt": caBundle},
+	}
+	server, _ := createNewServerAndKubeCli(t, secret, configMap)
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	return server, fake
+}
+
+func newTestLDAPServer() *v1alpha1.LDAPServer {
+	return &v1alpha1.LDAPServer{
+		Connection: v1alpha1.LDAPConnection{
+			Protocol:              v1alpha1.LDAPProtocolLDAPS,
+			Host:                  "ldap.example.com",
+			SearchBase:            "dc=example,dc=com",
+			BindCredentialsSecret: &v1alpha1.CredentialsSecret{Name: "ldap-bind"},
+			CACertificate: &v1alpha1.CertificateSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ldap-ca"}, Key: "ca.crt"},
+			},
+		},
+		UserMapping:  v1alpha1.LDAPUserMapping{BaseDN: "ou=people"},
+		GroupMapping: &v1alpha1.LDAPGroupMapping{Type: v1alpha1.LDAPGroupTypeDynamic},
+	}
+}
+
+func Test_realmOperation_EnsureRealmsNothingDeclared(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.Empty(t, fake.requests)
+	assert.False(t, server.status.LDAPServerConfigured)
+	assert.False(t, server.status.LDAPRealmEnabled)
+}
+
+func Test_realmOperation_EnsureRealmsLDAP(t *testing.T) {
+	ca := newTestCertificate(t, "Example CA")
+	server, fake := createNewServerWithLDAPSecrets(t, ca+newTestCertificate(t, "Example Intermediate CA"))
+	fake.truststore = []string{ca}
+	server.nexus.Spec.Security.LDAP = newTestLDAPServer()
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+
+	assert.True(t, server.status.LDAPServerConfigured)
+	assert.True(t, server.status.LDAPRealmEnabled)
+	// only the certificate missing from the truststore is added
+	assert.Len(t, fake.truststore, 2)
+	assert.Equal(t, []string{"NexusAuthenticatingRealm", "NexusAuthorizingRealm", v1alpha1.LDAPRealm}, fake.activeRealms)
+	ldap := fake.ldapServers[v1alpha1.DefaultLDAPServerName]
+	assert.NotNil(t, ldap)
+	assert.Equal(t, "ldaps", ldap["protocol"])
+	assert.Equal(t, float64(defaultLDAPSPort), ldap["port"])
+	assert.Equal(t, true, ldap["useTrustStore"])
+	assert.Equal(t, "SIMPLE", ldap["authScheme"])
+	assert.Equal(t, "cn=nexus,dc=example,dc=com", ldap["authUsername"])
+	assert.Equal(t, "s3cr3t", ldap["authPassword"])
+	assert.Equal(t, "ou=people", ldap["userBaseDn"])
+	assert.Equal(t, defaultLDAPUserObjectClass, ldap["userObjectClass"])
+	assert.Equal(t, true, ldap["ldapGroupsAsRoles"])
+	assert.Equal(t, "dynamic", ldap["groupType"])
+	assert.Equal(t, defaultLDAPMemberOfAttribute, ldap["userMemberOfAttribute"])
+	assert.Nil(t, ldap["groupObjectClass"])
+
+	// nothing changed, the bind password never returned by the server doesn't trigger updates
+	fake.requests = nil
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.False(t, fake.requested("PUT /security/ldap/ldap"))
+	assert.False(t, fake.requested("POST /security/ssl/truststore"))
+	assert.False(t, fake.requested("PUT /security/realms/active"))
+}
+
+func Test_realmOperation_EnsureRealmsRevertLDAPChanges(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, newTestCertificate(t, "Example CA"))
+	server.nexus.Spec.Security.LDAP = newTestLDAPServer()
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+
+	// changed by hand
+	fake.ldapServers["ldap"]["host"] = "other.example.com"
+	fake.ldapServers["ldap"]["userLdapFilter"] = "(objectClass=person)"
+	fake.activeRealms = []string{"NexusAuthenticatingRealm"}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.True(t, fake.requested("PUT /security/ldap/ldap"))
+	assert.Equal(t, "ldap.example.com", fake.ldapServers["ldap"]["host"])
+	assert.Nil(t, fake.ldapServers["ldap"]["userLdapFilter"])
+	assert.Equal(t, "s3cr3t", fake.ldapServers["ldap"]["authPassword"])
+	assert.Equal(t, []string{"NexusAuthenticatingRealm", v1alpha1.LDAPRealm}, fake.activeRealms)
+}
+
+func Test_realmOperation_EnsureRealmsAnonymousBind(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	server.nexus.Spec.Security.LDAP = &v1alpha1.LDAPServer{
+		Name:        "corp",
+		Connection:  v1alpha1.LDAPConnection{Host: "ldap.example.com", SearchBase: "dc=example,dc=com"},
+		UserMapping: v1alpha1.LDAPUserMapping{ObjectClass: "user", IDAttribute: "sAMAccountName"},
+	}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.False(t, fake.requested("GET /security/ssl/truststore"))
+	ldap := fake.ldapServers["corp"]
+	assert.Equal(t, "ldap", ldap["protocol"])
+	assert.Equal(t, float64(defaultLDAPPort), ldap["port"])
+	assert.Equal(t, "NONE", ldap["authScheme"])
+	assert.Equal(t, false, ldap["useTrustStore"])
+	assert.Equal(t, false, ldap["ldapGroupsAsRoles"])
+	assert.Equal(t, "sAMAccountName", ldap["userIdAttribute"])
+	assert.Nil(t, ldap["authPassword"])
+}
+
+func Test_realmOperation_EnsureRealmsInvalidLDAP(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	server.nexus.Spec.Security.LDAP = newTestLDAPServer()
+	server.nexus.Spec.Security.LDAP.Connection.BindCredentialsSecret = nil
+	server.nexus.Spec.Security.LDAP.Connection.AuthScheme = v1alpha1.LDAPAuthSchemeSimple
+	assert.Error(t, realmOperations(server).EnsureRealms())
+
+	// no certificates in the ConfigMap
+	server.nexus.Spec.Security.LDAP = newTestLDAPServer()
+	assert.Error(t, realmOperations(server).EnsureRealms())
+	assert.Empty(t, fake.requests)
+	assert.False(t, server.status.LDAPServerConfigured)
+}
+
+func Test_realmOperation_EnsureRealmsServerFailure(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	server.nexus.Spec.Security.LDAP = newTestLDAPServer()
+	server.nexus.Spec.Security.LDAP.Connection.CACertificate = nil
+	fake.failures["PUT /security/realms/active"] = http.StatusInternalServerError
+	assert.Error(t, realmOperations(server).EnsureRealms())
+	assert.True(t, server.status.LDAPServerConfigured)
+	assert.False(t, server.status.LDAPRealmEnabled)
+}
+
+// createNewServerWithSAMLMetadata creates a new server pointing to a fake Nexus server with the ConfigMap holding the identity provider metadata
+func createNewServerWithSAMLMetadata(t *testing.T) (*server, *fakeNexusServer) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "idp", Namespace: t.Name()},
+		Data:       map[string]string{"metadata.xml": "<EntityDescriptor entityID=\"https://idp.example.com\"/>"},
+	}
+	server, _ := createNewServerAndKubeCli(t, configMap)
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	server.nexus.Spec.Security.SAML = &v1alpha1.SAML{
+		IdPMetadata:       corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "idp"}, Key: "metadata.xml"},
+		UsernameAttribute: "username",
+		GroupsAttribute:   "groups",
+	}
+	return server, fake
+}
+
+func Test_realmOperation_EnsureRealmsSAML(t *testing.T) {
+	server, fake := createNewServerWithSAMLMetadata(t)
+	fake.pro = true
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+
+	assert.True(t, server.status.SAMLConfigured)
+	assert.True(t, server.status.SAMLRealmEnabled)
+	assert.Equal(t, "<EntityDescriptor entityID=\"https://idp.example.com\"/>", fake.saml["idpMetadata"])
+	assert.Equal(t, "username", fake.saml["usernameAttribute"])
+	assert.Equal(t, "groups", fake.saml["groupsAttribute"])
+	assert.Contains(t, fake.activeRealms, v1alpha1.SAMLRealm)
+
+	// nothing changed
+	fake.requests = nil
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.False(t, fake.requested("PUT /security/saml"))
+
+	// changed by hand
+	fake.saml["usernameAttribute"] = "uid"
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.Equal(t, "username", fake.saml["usernameAttribute"])
+}
+
+func Test_realmOperation_EnsureRealmsSAMLNotPro(t *testing.T) {
+	server, fake := createNewServerWithSAMLMetadata(t)
+	err := realmOperations(server).EnsureRealms()
+	assert.Equal(t, errSAMLUnavailable, err)
+	assert.False(t, server.status.SAMLConfigured)
+	assert.NotContains(t, fake.activeRealms, v1alpha1.SAMLRealm)
+}
+
+func Test_realmOperation_EnsureRealmsRUTAuth(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	// the sidecar alone enables the realm with the default header
+	server.nexus.Spec.Security.OAuth2Proxy = &v1alpha1.OAuth2Proxy{ClientSecretName: "oauth2-proxy"}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+
+	assert.True(t, server.status.RUTAuthConfigured)
+	assert.True(t, server.status.RUTAuthRealmEnabled)
+	assert.Len(t, fake.capabilities, 1)
+	assert.Equal(t, extDirectCapability{ID: "capability-0", TypeID: rutAuthCapabilityType, Enabled: true, Notes: "Managed by the Nexus Operator",
+		Properties: map[string]string{rutAuthHeaderProperty: v1alpha1.DefaultRUTAuthHeader}}, fake.capabilities["capability-0"])
+	assert.Equal(t, v1alpha1.RUTAuthRealm, fake.activeRealms[len(fake.activeRealms)-1])
+
+	// nothing changed
+	fake.requests = nil
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.False(t, fake.requested("POST capability_Capability.create"))
+	assert.False(t, fake.requested("POST capability_Capability.update"))
+
+	// another header
+	server.nexus.Spec.Security.RUTAuth = &v1alpha1.RUTAuth{HeaderName: "X-Forwarded-Email"}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.True(t, fake.requested("POST capability_Capability.update"))
+	assert.Len(t, fake.capabilities, 1)
+	assert.Equal(t, "X-Forwarded-Email", fake.capabilities["capability-0"].Properties[rutAuthHeaderProperty])
+}
+
+func Test_realmOperation_EnsureRealmsOrder(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	server.nexus.Spec.Security.LDAP = newTestLDAPServer()
+	server.nexus.Spec.Security.LDAP.Connection.CACertificate = nil
+	server.nexus.Spec.Security.Realms = []string{v1alpha1.NexusAuthenticatingRealm, v1alpha1.DockerTokenRealm, v1alpha1.NpmTokenRealm}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+
+	// the realms left out are disabled, except the required ones
+	want := []string{v1alpha1.NexusAuthenticatingRealm, v1alpha1.DockerTokenRealm, v1alpha1.NpmTokenRealm, v1alpha1.LDAPRealm}
+	assert.Equal(t, want, fake.activeRealms)
+	assert.Equal(t, want, server.status.ActiveRealms)
+	assert.True(t, server.status.LDAPRealmEnabled)
+
+	// nothing changed
+	fake.requests = nil
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.False(t, fake.requested("PUT /security/realms/active"))
+
+	// reordered by hand
+	fake.activeRealms = []string{v1alpha1.LDAPRealm, v1alpha1.DockerTokenRealm, v1alpha1.NpmTokenRealm, v1alpha1.NexusAuthenticatingRealm}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.Equal(t, want, fake.activeRealms)
+}
+
+func Test_realmOperation_EnsureRealmsOrderOnly(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	server.nexus.Spec.Security.Realms = []string{v1alpha1.NexusAuthenticatingRealm, v1alpha1.NexusAuthorizingRealm, v1alpha1.DockerTokenRealm}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+	assert.Equal(t, server.nexus.Spec.Security.Realms, fake.activeRealms)
+	assert.False(t, server.status.LDAPRealmEnabled)
+	assert.False(t, fake.requested("GET /security/ldap/ldap"))
+}
+
+func Test_realmOperation_EnsureRealmsWithoutDefaultRealm(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	server.nexus.Spec.Security.Realms = []string{v1alpha1.DockerTokenRealm, v1alpha1.NpmTokenRealm}
+	assert.NoError(t, realmOperations(server).EnsureRealms())
+
+	// the operator would be locked out without it
+	want := []string{v1alpha1.NexusAuthenticatingRealm, v1alpha1.DockerTokenRealm, v1alpha1.NpmTokenRealm}
+	assert.Equal(t, want, fake.activeRealms)
+	assert.Equal(t, want, server.status.ActiveRealms)
+}
+
+func Test_realmOperation_EnsureAnonymousAccess(t *testing.T) {
+	server, fake := createNewServerWithLDAPSecrets(t, "")
+	assert.NoError(t, realmOperations(server).EnsureAnonymousAccess())
+	assert.Empty(t, fake.requests)
+	assert.False(t, server.status.AnonymousAccessConfigured)
+
+	server.nexus.Spec.Security.AnonymousAccess = &v1alpha1.AnonymousAccess{Enabled: false}
+	assert.NoError(t, realmOperations(server).EnsureAnonymousAccess())
+	assert.Equal(t, map[string]interface{}{"enabled": false, "userId": v1alpha1.DefaultAnonymousUserID, "realmName": v1alpha1.DefaultAnonymousRealm}, fake.anonymous)
+	assert.True(t, server.status.AnonymousAccessConfigured)
+
+	// nothing changed
+	fake.requests = nil
+	assert.NoError(t, realmOperations(server).EnsureAnonymousAccess())
+	assert.False(t, fake.requested("PUT /security/anonymous"))
+
+	// changed by hand
+	fake.anonymous["enabled"] = true
+	server.nexus.Spec.Security.AnonymousAccess = &v1alpha1.AnonymousAccess{Enabled: false, UserID: "guest", Realm: v1alpha1.LDAPRealm}
+	assert.NoError(t, realmOperations(server).EnsureAnonymousAccess())
+	assert.Equal(t, map[string]interface{}{"enabled": false, "userId": "guest", "realmName": v1alpha1.LDAPRealm}, fake.anonymous)
+}
@@ -20,21 +20,27 @@ import (
 	"strings"
 
 	"github.com/m88i/aicura/nexus"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
 )
 
-var communityMavenProxies = map[string]nexus.MavenProxyRepository{
-	"apache":  defaultMavenProxyInstance("apache", "https://repo.maven.apache.org/maven2/"),
-	"red-hat": defaultMavenProxyInstance("red-hat", "https://maven.repository.redhat.com/ga/"),
-	"jboss":   defaultMavenProxyInstance("jboss", "https://repository.jboss.org/"),
+// defaultCommunityMavenProxies are created when no proxies are set in `spec.serverOperations.communityMavenProxies`
+var defaultCommunityMavenProxies = []v1alpha1.CommunityMavenProxy{
+	{Name: "apache", RemoteURL: "https://repo.maven.apache.org/maven2/"},
+	{Name: "red-hat", RemoteURL: "https://maven.repository.redhat.com/ga/"},
+	{Name: "jboss", RemoteURL: "https://repository.jboss.org/"},
 }
 
 const (
-	mavenCentralRepoID = "maven-public"
+	defaultCommunityContentMaxAge = int32(-1)
 )
 
 // RepositoryOperations describes the public operations in the repository domain for the Nexus instance
 type RepositoryOperations interface {
 	EnsureCommunityMavenProxies() error
+	EnsureRepositories() error
+	EnsureRepository(repo v1alpha1.Repository, created bool) (v1alpha1.RepositoryStatus, error)
+	RemoveRepository(name string) error
 }
 
 type repositoryOperation struct {
@@ -57,20 +63,22 @@ func (r *repositoryOperation) EnsureCommunityMavenProxies() error {
 }
 
 func (r *repositoryOperation) addCommunityReposToMavenCentralGroup() error {
-	log.Debug("Attempt to fetch the Maven Central group repository")
-	mavenCentral, err := r.nexuscli.MavenGroupRepositoryService.GetRepoByName(mavenCentralRepoID)
+	groupName := r.communityMavenGroupName()
+	log.Debug("Attempt to fetch the Maven Central group repository", "Group", groupName)
+	mavenCentral, err := r.nexuscli.MavenGroupRepositoryService.GetRepoByName(groupName)
 	if err != nil {
 		return err
 	}
 	if mavenCentral == nil {
-		log.Info("Maven Central repository group not found in the server instance, won't add community repos to the group")
+		log.Info("Maven Central repository group not found in the server instance, won't add community repos to the group", "Group", groupName)
 		return nil
 	}
 	if err := r.setMavenPublicURL(mavenCentral); err != nil {
 		return err
 	}
 	var newMembers []string
-	for newMember := range communityMavenProxies {
+	for _, proxy := range r.communityMavenProxies() {
+		newMember := proxy.Name
 		found := false
 		for _, added := range mavenCentral.Group.MemberNames {
 			if newMember == added {
@@ -102,15 +110,30 @@ func (r *repositoryOperation) addCommunityReposToMavenCentralGroup() error {
 func (r *repositoryOperation) createCommunityReposIfNotExists() error {
 	var reposToAdd []nexus.MavenProxyRepository
 	log.Debug("Attempt to create community repositories")
-	for _, repo := range communityMavenProxies {
-		log.Debug("Trying to fetch repository", "Repo", repo.Name)
-		fetchedRepo, err := r.nexuscli.MavenProxyRepositoryService.GetRepoByName(repo.Name)
+	for _, proxy := range r.communityMavenProxies() {
+		log.Debug("Trying to fetch repository", "Repo", proxy.Name)
+		fetchedRepo, err := r.nexuscli.MavenProxyRepositoryService.GetRepoByName(proxy.Name)
+		if err != nil {
+			return err
+		}
+		if fetchedRepo != nil {
+			if err := r.updateCommunityRepoIfChanged(proxy, fetchedRepo); err != nil {
+				return err
+			}
+			continue
+		}
+		if proxy.Authentication == nil {
+			reposToAdd = append(reposToAdd, newMavenProxyInstance(proxy, ""))
+			continue
+		}
+		creds, err := r.getCredentials(proxy.Authentication.CredentialsSecret)
 		if err != nil {
 			return err
 		}
-		if fetchedRepo == nil {
-			reposToAdd = append(reposToAdd, repo)
+		if err := r.addMavenProxyWithPassword(newMavenProxyInstance(proxy, creds.username), creds.password); err != nil {
+			return err
 		}
+		log.Debug("Repository created", "Repo", proxy.Name)
 	}
 	if len(reposToAdd) > 0 {
 		log.Debug("Repositories to add", "Repos", reposToAdd)
@@ -124,50 +147,333 @@ func (r *repositoryOperation) createCommunityReposIfNotExists() error {
 	return nil
 }
 
+// updateCommunityRepoIfChanged reverts the cache settings of an existing community proxy to the ones in the spec.
+// The Nexus server doesn't allow changing the blob store and the version policy of existing repositories.
+func (r *repositoryOperation) updateCommunityRepoIfChanged(proxy v1alpha1.CommunityMavenProxy, current *nexus.MavenProxyRepository) error {
+	desired := newMavenProxyInstance(proxy, "")
+	if current.Storage.BlobStoreName != desired.Storage.BlobStoreName || current.Maven.VersionPolicy != desired.Maven.VersionPolicy {
+		log.Warn("The blob store and the version policy of an existing repository can't be changed, remove it from the server to have it recreated",
+			"Repo", proxy.Name, "BlobStore", current.Storage.BlobStoreName, "VersionPolicy", current.Maven.VersionPolicy)
+	}
+	if current.Proxy.ContentMaxAge == desired.Proxy.ContentMaxAge &&
+		current.Proxy.MetadataMaxAge == desired.Proxy.MetadataMaxAge &&
+		current.NegativeCache.TimeToLive == desired.NegativeCache.TimeToLive {
+		return nil
+	}
+
+	updated := *current
+	updated.Proxy.ContentMaxAge, updated.Proxy.MetadataMaxAge = desired.Proxy.ContentMaxAge, desired.Proxy.MetadataMaxAge
+	updated.NegativeCache.TimeToLive = desired.NegativeCache.TimeToLive
+	password := ""
+	if proxy.Authentication != nil {
+		creds, err := r.getCredentials(proxy.Authentication.CredentialsSecret)
+		if err != nil {
+			return err
+		}
+		updated.HTTPClient.Authentication = newMavenProxyInstance(proxy, creds.username).HTTPClient.Authentication
+		password = creds.password
+	}
+	body, err := mavenProxyBody(updated, password)
+	if err != nil {
+		return err
+	}
+	log.Debug("Community repository differs from the desired state, trying to update it", "Repo", proxy.Name)
+	if err := r.restcli.put(fmt.Sprintf("%s/%s", repositoryPath(v1alpha1.MavenRepositoryFormat, v1alpha1.ProxyRepositoryType), proxy.Name), body); err != nil {
+		return err
+	}
+	log.Info("Community repository updated", "Repo", proxy.Name)
+	return nil
+}
+
+// addMavenProxyWithPassword creates the given proxy with the REST client, since the aicura client can't send the remote repository password
+func (r *repositoryOperation) addMavenProxyWithPassword(repo nexus.MavenProxyRepository, password string) error {
+	body, err := mavenProxyBody(repo, password)
+	if err != nil {
+		return err
+	}
+	return r.restcli.post(repositoryPath(v1alpha1.MavenRepositoryFormat, v1alpha1.ProxyRepositoryType), body)
+}
+
+// mavenProxyBody converts the given proxy to the body sent to the REST API, adding the remote repository password if it authenticates
+func mavenProxyBody(repo nexus.MavenProxyRepository, password string) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	if err := roundTripJSON(repo, &body); err != nil {
+		return nil, err
+	}
+	if repo.HTTPClient.Authentication == nil {
+		return body, nil
+	}
+	httpClient, _ := body["httpClient"].(map[string]interface{})
+	auth, ok := httpClient["authentication"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("repository %s doesn't declare the authentication against the remote repository", repo.Name)
+	}
+	auth["password"] = password
+	return body, nil
+}
+
+// communityMavenProxies returns the community Maven proxies to be created in the server
+func (r *repositoryOperation) communityMavenProxies() []v1alpha1.CommunityMavenProxy {
+	if opts := r.nexus.Spec.ServerOperations.CommunityMavenProxies; opts != nil && len(opts.Proxies) > 0 {
+		return opts.Proxies
+	}
+	return defaultCommunityMavenProxies
+}
+
+// communityMavenGroupName returns the name of the Maven group the community proxies are added to
+func (r *repositoryOperation) communityMavenGroupName() string {
+	if opts := r.nexus.Spec.ServerOperations.CommunityMavenProxies; opts != nil {
+		return stringOrDefault(opts.GroupName, v1alpha1.DefaultCommunityMavenGroupName)
+	}
+	return v1alpha1.DefaultCommunityMavenGroupName
+}
+
 func (r *repositoryOperation) setMavenPublicURL(repository *nexus.MavenGroupRepository) error {
 	if repository == nil || len(*repository.URL) == 0 {
 		return nil
 	}
-	repositoryURL := *repository.URL
+	mavenPublicURL, err := r.toInternalURL(*repository.URL)
+	if err != nil {
+		return err
+	}
+	r.status.MavenPublicURL = mavenPublicURL
+	return nil
+}
+
+// toInternalURL replaces the scheme, host and context path of the given repository URL by the Nexus endpoint reachable within the cluster
+func (r *repositoryOperation) toInternalURL(repositoryURL string) (string, error) {
 	if !strings.HasSuffix(repositoryURL, "/") {
 		repositoryURL += "/"
 	}
 	serverEndpoint, err := r.getNexusEndpoint()
 	if err != nil {
-		return err
+		return "", err
 	}
 	serverEndpoint = strings.TrimSuffix(serverEndpoint, "/")
 	URL, err := url.Parse(repositoryURL)
+	if err != nil {
+		return "", err
+	}
+	path := URL.Path
+	if contextPath := r.nexus.ContextPath(); len(contextPath) > 0 && strings.HasPrefix(path, contextPath+"/") {
+		path = strings.TrimPrefix(path, contextPath)
+	}
+	return fmt.Sprintf("%s%s", serverEndpoint, path), nil
+}
+
+// EnsureRepositories converges the repositories declared in `spec.repositories` with the ones in the Nexus server.
+// Repositories created by the Operator are removed from the server once they're removed from the spec.
+func (r *repositoryOperation) EnsureRepositories() error {
+	previous := r.status.Repositories
+	if len(r.nexus.Spec.Repositories) == 0 && len(previous) == 0 {
+		log.Debug("No repositories declared in 'spec.repositories', skipping")
+		return nil
+	}
+
+	existing, err := r.fetchRepositories()
 	if err != nil {
 		return err
 	}
-	r.status.MavenPublicURL = fmt.Sprintf("%s%s", serverEndpoint, URL.Path)
+	created := make(map[string]bool, len(previous))
+	for _, repo := range previous {
+		created[repo.Name] = repo.Created
+	}
+
+	var statuses []v1alpha1.RepositoryStatus
+	var errs []string
+	// groups must be handled last since their members might be declared in the same list
+	for _, repo := range sortRepositoriesByType(r.nexus.Spec.Repositories) {
+		status, err := r.ensureRepository(repo, existing, created[repo.Name])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		statuses = append(statuses, status)
+	}
+
+	declared := make(map[string]bool, len(r.nexus.Spec.Repositories))
+	for _, repo := range r.nexus.Spec.Repositories {
+		declared[repo.Name] = true
+	}
+	for _, repo := range previous {
+		if declared[repo.Name] {
+			continue
+		}
+		if !repo.Created {
+			log.Debug("Repository removed from the spec wasn't created by the Operator, won't remove it from the server", "Repo", repo.Name)
+			continue
+		}
+		if err := r.RemoveRepository(repo.Name); err != nil {
+			// we keep it in the status to try again in the next reconciliation
+			repo.Ready = false
+			repo.Reason = fmt.Sprintf("Failed to remove repository from the server: %v", err)
+			statuses = append(statuses, repo)
+			errs = append(errs, err.Error())
+		}
+	}
+
+	r.status.Repositories = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to ensure repositories: %s", strings.Join(errs, "; "))
+	}
 	return nil
 }
 
-func defaultMavenProxyInstance(name, url string) nexus.MavenProxyRepository {
-	return nexus.MavenProxyRepository{
+// EnsureRepository converges a single repository with the one in the Nexus server.
+// "created" must be true if the repository was created by the Operator in a previous call.
+func (r *repositoryOperation) EnsureRepository(repo v1alpha1.Repository, created bool) (v1alpha1.RepositoryStatus, error) {
+	existing, err := r.fetchRepositories()
+	if err != nil {
+		return v1alpha1.RepositoryStatus{Name: repo.Name, Format: repo.Format, Type: repo.Type, Created: created, Reason: err.Error()}, err
+	}
+	return r.ensureRepository(repo, existing, created)
+}
+
+func (r *repositoryOperation) fetchRepositories() (map[string]apiRepositoryRef, error) {
+	log.Debug("Attempt to fetch all repositories from the server")
+	var fetched []apiRepositoryRef
+	if err := r.restcli.get(repositoriesRESTPath, &fetched); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]apiRepositoryRef, len(fetched))
+	for _, repo := range fetched {
+		existing[repo.Name] = repo
+	}
+	return existing, nil
+}
+
+func (r *repositoryOperation) ensureRepository(repo v1alpha1.Repository, existing map[string]apiRepositoryRef, created bool) (v1alpha1.RepositoryStatus, error) {
+	status := v1alpha1.RepositoryStatus{Name: repo.Name, Format: repo.Format, Type: repo.Type, Created: created}
+	if err := validateRepository(repo); err != nil {
+		log.Warn("Invalid repository declared in 'spec.repositories'", "Repo", repo.Name, "Reason", err.Error())
+		status.Reason = err.Error()
+		return status, nil
+	}
+
+	desired, comparable, err := r.newAPIRepositoryWithCredentials(repo)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+	path := repositoryPath(repo.Format, repo.Type)
+	if current, found := existing[repo.Name]; !found {
+		log.Debug("Trying to create repository", "Repo", repo.Name)
+		if err := r.restcli.post(path, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		status.Created = true
+		log.Info("Repository created", "Repo", repo.Name)
+	} else if current.Format != string(repo.Format) || current.Type != string(repo.Type) {
+		status.Reason = fmt.Sprintf("A repository named %s already exists in the server with format %s and type %s", repo.Name, current.Format, current.Type)
+		log.Warn(status.Reason)
+		return status, nil
+	} else {
+		var actual map[string]interface{}
+		if err := r.restcli.get(path+"/"+repo.Name, &actual); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		equal, err := jsonContains(actual, comparable)
+		if err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		// cleanup policies and authentication removed from the spec aren't caught by the comparison above
+		if repo.Cleanup == nil && hasCleanupPolicies(actual) {
+			desired.Cleanup = &apiCleanup{PolicyNames: []string{}}
+			equal = false
+		}
+		if desired.HTTPClient != nil && desired.HTTPClient.Authentication == nil && hasAuthentication(actual) {
+			equal = false
+		}
+		if !equal {
+			log.Debug("Repository differs from the desired state, trying to update it", "Repo", repo.Name)
+			if err := r.restcli.put(path+"/"+repo.Name, desired); err != nil {
+				status.Reason = err.Error()
+				return status, err
+			}
+			log.Info("Repository updated", "Repo", repo.Name)
+		}
+	}
+
+	repositoryURL, err := r.toInternalURL(fmt.Sprintf("/repository/%s/", repo.Name))
+	if err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+	status.URL = repositoryURL
+	status.Ready = true
+	return status, nil
+}
+
+// newAPIRepositoryWithCredentials creates the representation of the given repository to be sent to the server with the credentials
+// read from the referenced Secret, along with the one to be compared with what the server returns, since it never returns passwords.
+func (r *repositoryOperation) newAPIRepositoryWithCredentials(repo v1alpha1.Repository) (desired, comparable apiRepository, err error) {
+	desired = newAPIRepository(repo)
+	comparable = desired
+	if desired.HTTPClient == nil || repo.HTTPClient == nil || repo.HTTPClient.Authentication == nil {
+		return desired, comparable, nil
+	}
+	creds, err := r.getCredentials(repo.HTTPClient.Authentication.CredentialsSecret)
+	if err != nil {
+		return desired, comparable, err
+	}
+	httpClient, comparableHTTPClient := *desired.HTTPClient, *desired.HTTPClient
+	httpClient.Authentication = newAPIHTTPClientAuthentication(repo.HTTPClient.Authentication, creds)
+	comparableHTTPClient.Authentication = newAPIHTTPClientAuthentication(repo.HTTPClient.Authentication, credentials{username: creds.username})
+	desired.HTTPClient, comparable.HTTPClient = &httpClient, &comparableHTTPClient
+	return desired, comparable, nil
+}
+
+// RemoveRepository removes the given repository from the Nexus server, if it exists
+func (r *repositoryOperation) RemoveRepository(name string) error {
+	log.Debug("Trying to remove repository", "Repo", name)
+	if err := r.restcli.delete(fmt.Sprintf("%s/%s", repositoriesRESTPath, name)); err != nil && !isRESTNotFound(err) {
+		return err
+	}
+	log.Info("Repository removed", "Repo", name)
+	return nil
+}
+
+// sortRepositoriesByType returns a copy of the given repositories with groups placed after every other type
+func sortRepositoriesByType(repositories []v1alpha1.Repository) []v1alpha1.Repository {
+	sorted := make([]v1alpha1.Repository, 0, len(repositories))
+	var groups []v1alpha1.Repository
+	for _, repo := range repositories {
+		if repo.Type == v1alpha1.GroupRepositoryType {
+			groups = append(groups, repo)
+		} else {
+			sorted = append(sorted, repo)
+		}
+	}
+	return append(sorted, groups...)
+}
+
+// newMavenProxyInstance converts the given community Maven proxy into its aicura representation with defaults set.
+// The username is only used if the proxy authenticates against the remote repository.
+func newMavenProxyInstance(proxy v1alpha1.CommunityMavenProxy, username string) nexus.MavenProxyRepository {
+	repo := nexus.MavenProxyRepository{
 		Proxy: nexus.Proxy{
-			MetadataMaxAge: 1440,
-			RemoteURL:      url,
-			ContentMaxAge:  -1,
+			MetadataMaxAge: int32OrDefault(proxy.MetadataMaxAge, defaultCacheMaxAge),
+			RemoteURL:      proxy.RemoteURL,
+			ContentMaxAge:  int32OrDefault(proxy.ContentMaxAge, defaultCommunityContentMaxAge),
 		},
 		Repository: nexus.Repository{
 			Online: nexus.NewBool(true),
 			Format: nexus.NewRepositoryFormat(nexus.RepositoryFormatMaven2),
-			Name:   name,
+			Name:   proxy.Name,
 			Type:   nexus.NewRepositoryType(nexus.RepositoryTypeProxy),
 		},
 		Storage: nexus.Storage{
-			BlobStoreName:               "default",
+			BlobStoreName:               stringOrDefault(proxy.BlobStoreName, defaultBlobStoreName),
 			StrictContentTypeValidation: true,
 		},
 		NegativeCache: nexus.NegativeCache{
 			Enabled:    true,
-			TimeToLive: 1440,
+			TimeToLive: int32OrDefault(proxy.NegativeCacheTTL, defaultCacheMaxAge),
 		},
 		Maven: nexus.Maven{
-			VersionPolicy: nexus.VersionPolicyRelease,
+			VersionPolicy: nexus.MavenVersionPolicy(stringOrDefault(string(proxy.VersionPolicy), string(v1alpha1.ReleaseVersionPolicy))),
 			LayoutPolicy:  nexus.LayoutPolicyPermissive,
 		},
 		HTTPClient: nexus.HTTPClient{
@@ -175,4 +481,13 @@ func defaultMavenProxyInstance(name, url string) nexus.MavenProxyRepository {
 			AutoBlock: true,
 		},
 	}
+	if auth := proxy.Authentication; auth != nil {
+		repo.HTTPClient.Authentication = &nexus.Authentication{
+			Type:       stringOrDefault(string(auth.Type), string(v1alpha1.UsernameAuthenticationType)),
+			Username:   username,
+			NtlmHost:   auth.NTLMHost,
+			NtlmDomain: auth.NTLMDomain,
+		}
+	}
+	return repo
 }
@@ -0,0 +1,284 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const (
+	repositoriesRESTPath = "/repositories"
+
+	defaultBlobStoreName  = "default"
+	defaultCacheMaxAge    = int32(1440)
+	defaultDockerIndex    = "REGISTRY"
+	dockerHubIndexURL     = "https://index.docker.io/"
+	dockerCustomIndexType = "CUSTOM"
+)
+
+// apiRepositoryRef is the short representation of a repository returned when listing all repositories in the server
+type apiRepositoryRef struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+}
+
+// apiRepository is the representation of a repository sent to and read from the Nexus REST API.
+// Every repository format and type shares this structure, fields not used by a given format/type are left empty.
+type apiRepository struct {
+	Name          string            `json:"name"`
+	Online        bool              `json:"online"`
+	Storage       apiStorage        `json:"storage"`
+	Proxy         *apiProxy         `json:"proxy,omitempty"`
+	NegativeCache *apiNegativeCache `json:"negativeCache,omitempty"`
+	HTTPClient    *apiHTTPClient    `json:"httpClient,omitempty"`
+	Group         *apiGroup         `json:"group,omitempty"`
+	Maven         *apiMaven         `json:"maven,omitempty"`
+	Docker        *apiDocker        `json:"docker,omitempty"`
+	DockerProxy   *apiDockerProxy   `json:"dockerProxy,omitempty"`
+	Cleanup       *apiCleanup       `json:"cleanup,omitempty"`
+}
+
+type apiStorage struct {
+	BlobStoreName               string `json:"blobStoreName"`
+	StrictContentTypeValidation bool   `json:"strictContentTypeValidation"`
+	WritePolicy                 string `json:"writePolicy,omitempty"`
+}
+
+type apiProxy struct {
+	RemoteURL      string `json:"remoteUrl"`
+	ContentMaxAge  int32  `json:"contentMaxAge"`
+	MetadataMaxAge int32  `json:"metadataMaxAge"`
+}
+
+type apiNegativeCache struct {
+	Enabled    bool  `json:"enabled"`
+	TimeToLive int32 `json:"timeToLive"`
+}
+
+type apiHTTPClient struct {
+	Blocked        bool                         `json:"blocked"`
+	AutoBlock      bool                         `json:"autoBlock"`
+	Authentication *apiHTTPClientAuthentication `json:"authentication,omitempty"`
+}
+
+type apiHTTPClientAuthentication struct {
+	Type       string `json:"type"`
+	Username   string `json:"username"`
+	Password   string `json:"password,omitempty"`
+	NTLMHost   string `json:"ntlmHost,omitempty"`
+	NTLMDomain string `json:"ntlmDomain,omitempty"`
+}
+
+type apiGroup struct {
+	MemberNames []string `json:"memberNames"`
+}
+
+type apiCleanup struct {
+	PolicyNames []string `json:"policyNames"`
+}
+
+type apiMaven struct {
+	VersionPolicy string `json:"versionPolicy"`
+	LayoutPolicy  string `json:"layoutPolicy"`
+}
+
+type apiDocker struct {
+	V1Enabled      bool   `json:"v1Enabled"`
+	ForceBasicAuth bool   `json:"forceBasicAuth"`
+	HTTPPort       *int32 `json:"httpPort,omitempty"`
+}
+
+type apiDockerProxy struct {
+	IndexType string `json:"indexType"`
+	IndexURL  string `json:"indexUrl,omitempty"`
+}
+
+// repositoryPath is the REST API path to create a repository with the given format and type
+func repositoryPath(format v1alpha1.RepositoryFormat, repoType v1alpha1.RepositoryType) string {
+	pathFormat := string(format)
+	// the only format whose path differs from its name
+	if format == v1alpha1.MavenRepositoryFormat {
+		pathFormat = "maven"
+	}
+	return fmt.Sprintf("%s/%s/%s", repositoriesRESTPath, pathFormat, repoType)
+}
+
+// validateRepository verifies if the given repository can be created in the Nexus server
+func validateRepository(repo v1alpha1.Repository) error {
+	if len(repo.Name) == 0 {
+		return fmt.Errorf("repository name must not be empty")
+	}
+	if repo.Format == v1alpha1.HelmRepositoryFormat && repo.Type == v1alpha1.GroupRepositoryType {
+		return fmt.Errorf("%s repositories don't support the %s type", repo.Format, repo.Type)
+	}
+	if repo.Format == v1alpha1.GoRepositoryFormat && repo.Type == v1alpha1.HostedRepositoryType {
+		return fmt.Errorf("%s repositories don't support the %s type", repo.Format, repo.Type)
+	}
+	if repo.Type == v1alpha1.GroupRepositoryType && repo.Cleanup != nil {
+		return fmt.Errorf("group repository %s doesn't support cleanup policies", repo.Name)
+	}
+	if repo.Type != v1alpha1.ProxyRepositoryType && repo.HTTPClient != nil {
+		return fmt.Errorf("%s repository %s doesn't support 'httpClient', only proxies do", repo.Type, repo.Name)
+	}
+	switch repo.Type {
+	case v1alpha1.HostedRepositoryType:
+	case v1alpha1.ProxyRepositoryType:
+		if repo.Proxy == nil || len(repo.Proxy.RemoteURL) == 0 {
+			return fmt.Errorf("proxy repository %s requires 'proxy.remoteURL'", repo.Name)
+		}
+		if repo.Format == v1alpha1.DockerRepositoryFormat && repo.Docker != nil &&
+			repo.Docker.IndexType == dockerCustomIndexType && len(repo.Docker.IndexURL) == 0 {
+			return fmt.Errorf("docker proxy repository %s requires 'docker.indexURL' when using a custom index", repo.Name)
+		}
+	case v1alpha1.GroupRepositoryType:
+		if repo.Group == nil || len(repo.Group.MemberNames) == 0 {
+			return fmt.Errorf("group repository %s requires at least one member in 'group.memberNames'", repo.Name)
+		}
+	default:
+		return fmt.Errorf("repository %s has an unsupported type: %s", repo.Name, repo.Type)
+	}
+	return nil
+}
+
+// newAPIRepository converts the given repository into its Nexus REST API representation with defaults set
+func newAPIRepository(repo v1alpha1.Repository) apiRepository {
+	apiRepo := apiRepository{
+		Name:   repo.Name,
+		Online: boolOrDefault(repo.Online, true),
+		Storage: apiStorage{
+			BlobStoreName:               stringOrDefault(repo.Storage.BlobStoreName, defaultBlobStoreName),
+			StrictContentTypeValidation: boolOrDefault(repo.Storage.StrictContentTypeValidation, true),
+		},
+	}
+
+	if repo.Cleanup != nil {
+		apiRepo.Cleanup = &apiCleanup{PolicyNames: repo.Cleanup.PolicyNames}
+	}
+
+	switch repo.Type {
+	case v1alpha1.HostedRepositoryType:
+		apiRepo.Storage.WritePolicy = stringOrDefault(string(repo.Storage.WritePolicy), string(v1alpha1.AllowOnceWritePolicy))
+	case v1alpha1.ProxyRepositoryType:
+		proxy := repo.Proxy
+		if proxy == nil {
+			proxy = &v1alpha1.RepositoryProxy{}
+		}
+		apiRepo.Proxy = &apiProxy{
+			RemoteURL:      proxy.RemoteURL,
+			ContentMaxAge:  int32OrDefault(proxy.ContentMaxAge, defaultCacheMaxAge),
+			MetadataMaxAge: int32OrDefault(proxy.MetadataMaxAge, defaultCacheMaxAge),
+		}
+		apiRepo.NegativeCache = &apiNegativeCache{
+			Enabled:    boolOrDefault(proxy.NegativeCacheEnabled, true),
+			TimeToLive: int32OrDefault(proxy.NegativeCacheTTL, defaultCacheMaxAge),
+		}
+		apiRepo.HTTPClient = &apiHTTPClient{Blocked: false, AutoBlock: true}
+	case v1alpha1.GroupRepositoryType:
+		apiRepo.Group = &apiGroup{}
+		if repo.Group != nil {
+			apiRepo.Group.MemberNames = repo.Group.MemberNames
+		}
+	}
+
+	switch repo.Format {
+	case v1alpha1.MavenRepositoryFormat:
+		if repo.Type != v1alpha1.GroupRepositoryType {
+			maven := repo.Maven
+			if maven == nil {
+				maven = &v1alpha1.RepositoryMaven{}
+			}
+			apiRepo.Maven = &apiMaven{
+				VersionPolicy: stringOrDefault(string(maven.VersionPolicy), string(v1alpha1.ReleaseVersionPolicy)),
+				LayoutPolicy:  stringOrDefault(string(maven.LayoutPolicy), string(v1alpha1.StrictLayoutPolicy)),
+			}
+		}
+	case v1alpha1.DockerRepositoryFormat:
+		docker := repo.Docker
+		if docker == nil {
+			docker = &v1alpha1.RepositoryDocker{}
+		}
+		apiRepo.Docker = &apiDocker{
+			V1Enabled:      docker.V1Enabled,
+			ForceBasicAuth: boolOrDefault(docker.ForceBasicAuth, true),
+			HTTPPort:       docker.HTTPPort,
+		}
+		if repo.Type == v1alpha1.ProxyRepositoryType {
+			apiRepo.DockerProxy = &apiDockerProxy{IndexType: stringOrDefault(docker.IndexType, defaultDockerIndex)}
+			switch apiRepo.DockerProxy.IndexType {
+			case dockerCustomIndexType:
+				apiRepo.DockerProxy.IndexURL = docker.IndexURL
+			case "HUB":
+				apiRepo.DockerProxy.IndexURL = dockerHubIndexURL
+			}
+		}
+	}
+	return apiRepo
+}
+
+// newAPIHTTPClientAuthentication converts the given authentication into its Nexus REST API representation with the given credentials
+func newAPIHTTPClientAuthentication(auth *v1alpha1.RepositoryAuthentication, creds credentials) *apiHTTPClientAuthentication {
+	return &apiHTTPClientAuthentication{
+		Type:       stringOrDefault(string(auth.Type), string(v1alpha1.UsernameAuthenticationType)),
+		Username:   creds.username,
+		Password:   creds.password,
+		NTLMHost:   auth.NTLMHost,
+		NTLMDomain: auth.NTLMDomain,
+	}
+}
+
+// hasAuthentication checks if the given repository, as returned by the Nexus REST API, authenticates against the remote repository
+func hasAuthentication(repo map[string]interface{}) bool {
+	httpClient, ok := repo["httpClient"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	auth, ok := httpClient["authentication"].(map[string]interface{})
+	return ok && auth != nil
+}
+
+// hasCleanupPolicies checks if the given repository, as returned by the Nexus REST API, has cleanup policies applied
+func hasCleanupPolicies(repo map[string]interface{}) bool {
+	cleanup, ok := repo["cleanup"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	policyNames, ok := cleanup["policyNames"].([]interface{})
+	return ok && len(policyNames) > 0
+}
+
+func boolOrDefault(value *bool, defaultValue bool) bool {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func int32OrDefault(value *int32, defaultValue int32) int32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if len(value) == 0 {
+		return defaultValue
+	}
+	return value
+}
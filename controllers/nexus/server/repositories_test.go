@@ -16,6 +16,7 @@ package server
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/m88i/aicura/nexus"
@@ -23,6 +24,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
 )
 
 // TODO: add a test to verify the Maven Central group being updated with the new members. See: https://github.com/m88i/aicura/issues/18
@@ -33,7 +36,61 @@ func TestAddCommReposNoCentralGroup(t *testing.T) {
 	assert.NoError(t, err)
 	repos, err := server.nexuscli.MavenProxyRepositoryService.List()
 	assert.NoError(t, err)
-	assert.Len(t, repos, len(communityMavenProxies))
+	assert.Len(t, repos, len(defaultCommunityMavenProxies))
+}
+
+func Test_repositoryOperation_communityMavenProxies(t *testing.T) {
+	server, _ := createNewServerAndKubeCli(t)
+	operations := &repositoryOperation{server: *server}
+	assert.Len(t, operations.communityMavenProxies(), len(defaultCommunityMavenProxies))
+	assert.Equal(t, v1alpha1.DefaultCommunityMavenGroupName, operations.communityMavenGroupName())
+
+	snapshots, ttl := v1alpha1.SnapshotVersionPolicy, int32(60)
+	server.nexus.Spec.ServerOperations.CommunityMavenProxies = &v1alpha1.CommunityMavenProxies{
+		GroupName: "maven-all",
+		Proxies: []v1alpha1.CommunityMavenProxy{
+			{Name: "google", RemoteURL: "https://maven.google.com/"},
+			{Name: "gradle-plugins", RemoteURL: "https://plugins.gradle.org/m2/", VersionPolicy: snapshots, NegativeCacheTTL: &ttl, BlobStoreName: "plugins"},
+		},
+	}
+	operations = &repositoryOperation{server: *server}
+	assert.Equal(t, "maven-all", operations.communityMavenGroupName())
+	proxies := operations.communityMavenProxies()
+	assert.Len(t, proxies, 2)
+
+	google := newMavenProxyInstance(proxies[0], "")
+	assert.Equal(t, "google", google.Name)
+	assert.Equal(t, int32(-1), google.Proxy.ContentMaxAge)
+	assert.Equal(t, int32(1440), google.Proxy.MetadataMaxAge)
+	assert.Equal(t, nexus.VersionPolicyRelease, google.Maven.VersionPolicy)
+	assert.Equal(t, "default", google.Storage.BlobStoreName)
+
+	plugins := newMavenProxyInstance(proxies[1], "")
+	assert.Equal(t, "https://plugins.gradle.org/m2/", plugins.Proxy.RemoteURL)
+	assert.Equal(t, nexus.VersionPolicySnapshot, plugins.Maven.VersionPolicy)
+	assert.Equal(t, int32(60), plugins.NegativeCache.TimeToLive)
+	assert.Equal(t, "plugins", plugins.Storage.BlobStoreName)
+}
+
+func TestUpdateCommRepos(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	ttl := int32(60)
+	proxy := v1alpha1.CommunityMavenProxy{Name: "google-" + strings.ToLower(t.Name()), RemoteURL: "https://maven.google.com/"}
+	server.nexus.Spec.ServerOperations.CommunityMavenProxies = &v1alpha1.CommunityMavenProxies{Proxies: []v1alpha1.CommunityMavenProxy{proxy}}
+	existing := newMavenProxyInstance(proxy, "")
+	assert.NoError(t, server.nexuscli.MavenProxyRepositoryService.Add(existing))
+	fake.addRepository("maven2", "proxy", existing)
+
+	// nothing changed, nothing to do
+	assert.NoError(t, repositoryOperations(server).EnsureCommunityMavenProxies())
+	assert.False(t, fake.requested("PUT /repositories/maven/proxy/"+proxy.Name))
+
+	server.nexus.Spec.ServerOperations.CommunityMavenProxies.Proxies[0].NegativeCacheTTL = &ttl
+	assert.NoError(t, repositoryOperations(server).EnsureCommunityMavenProxies())
+	assert.True(t, fake.requested("PUT /repositories/maven/proxy/"+proxy.Name))
+	repo := fake.repositories[proxy.Name]
+	assert.Equal(t, float64(60), repo["negativeCache"].(map[string]interface{})["timeToLive"])
+	assert.Equal(t, "https://maven.google.com/", repo["proxy"].(map[string]interface{})["remoteUrl"])
 }
 
 func Test_repositoryOperation_setMavenPublicURL(t *testing.T) {
@@ -47,4 +104,221 @@ func Test_repositoryOperation_setMavenPublicURL(t *testing.T) {
 	err = operations.setMavenPublicURL(repository)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedURL, operations.status.MavenPublicURL)
+
+	// the context path isn't repeated
+	operations.nexus.Spec.Networking.ContextPath = "/nexus"
+	*repository.URL = "http://localhost:8081/nexus/repository/maven-public"
+	err = operations.setMavenPublicURL(repository)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nexus3."+t.Name()+"/nexus/repository/maven-public/", operations.status.MavenPublicURL)
+
+	repositoryURL, err := operations.toInternalURL("/repository/maven-public/")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nexus3."+t.Name()+"/nexus/repository/maven-public/", repositoryURL)
+}
+
+// createNewServerWithFakeNexus creates a new server whose REST client points to a fake Nexus server
+func createNewServerWithFakeNexus(t *testing.T, repositories ...v1alpha1.Repository) (*server, *fakeNexusServer) {
+	server, _ := createNewServerAndKubeCli(t, &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}})
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	server.nexus.Spec.Repositories = repositories
+	return server, fake
+}
+
+func Test_repositoryOperation_EnsureRepositoriesNoRepositories(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.Empty(t, fake.requests)
+	assert.Empty(t, server.status.Repositories)
+}
+
+func Test_repositoryOperation_EnsureRepositoriesCreate(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t,
+		v1alpha1.Repository{Name: "npm-group", Format: v1alpha1.NpmRepositoryFormat, Type: v1alpha1.GroupRepositoryType, Group: &v1alpha1.RepositoryGroup{MemberNames: []string{"npm-hosted"}}},
+		v1alpha1.Repository{Name: "npm-hosted", Format: v1alpha1.NpmRepositoryFormat, Type: v1alpha1.HostedRepositoryType},
+		v1alpha1.Repository{Name: "maven-proxy", Format: v1alpha1.MavenRepositoryFormat, Type: v1alpha1.ProxyRepositoryType, Proxy: &v1alpha1.RepositoryProxy{RemoteURL: "https://repo.example.com/"}},
+	)
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+
+	// groups are created after their members
+	assert.Equal(t, "POST /repositories/npm/hosted", fake.requests[1])
+	assert.Equal(t, "POST /repositories/maven/proxy", fake.requests[2])
+	assert.Equal(t, "POST /repositories/npm/group", fake.requests[3])
+	assert.Len(t, fake.repositories, 3)
+	assert.Equal(t, "ALLOW_ONCE", fake.repositories["npm-hosted"]["storage"].(map[string]interface{})["writePolicy"])
+	assert.Equal(t, "RELEASE", fake.repositories["maven-proxy"]["maven"].(map[string]interface{})["versionPolicy"])
+
+	assert.Len(t, server.status.Repositories, 3)
+	for _, status := range server.status.Repositories {
+		assert.True(t, status.Ready)
+		assert.True(t, status.Created)
+		assert.Empty(t, status.Reason)
+		assert.Equal(t, "http://nexus3."+t.Name()+"/repository/"+status.Name+"/", status.URL)
+	}
+}
+
+func Test_repositoryOperation_EnsureRepositoriesUpdate(t *testing.T) {
+	repo := v1alpha1.Repository{Name: "raw-hosted", Format: v1alpha1.RawRepositoryFormat, Type: v1alpha1.HostedRepositoryType}
+	server, fake := createNewServerWithFakeNexus(t, repo)
+	fake.addRepository("raw", "hosted", newAPIRepository(repo))
+
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.False(t, fake.requested("PUT /repositories/raw/hosted/raw-hosted"))
+	assert.True(t, server.status.Repositories[0].Ready)
+	// the repository already existed, it's not ours
+	assert.False(t, server.status.Repositories[0].Created)
+
+	server.nexus.Spec.Repositories[0].Storage.WritePolicy = v1alpha1.AllowWritePolicy
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.True(t, fake.requested("PUT /repositories/raw/hosted/raw-hosted"))
+	assert.Equal(t, "ALLOW", fake.repositories["raw-hosted"]["storage"].(map[string]interface{})["writePolicy"])
+}
+
+func Test_repositoryOperation_EnsureRepositoriesRemove(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t,
+		v1alpha1.Repository{Name: "pypi-hosted", Format: v1alpha1.PyPIRepositoryFormat, Type: v1alpha1.HostedRepositoryType},
+	)
+	fake.addRepository("raw", "hosted", apiRepository{Name: "not-ours"})
+	server.status.Repositories = []v1alpha1.RepositoryStatus{{Name: "not-ours"}}
+
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.Len(t, server.status.Repositories, 1)
+	assert.True(t, server.status.Repositories[0].Created)
+
+	server.nexus.Spec.Repositories = nil
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.Empty(t, server.status.Repositories)
+	assert.True(t, fake.requested("DELETE /repositories/pypi-hosted"))
+	assert.False(t, fake.requested("DELETE /repositories/not-ours"))
+	assert.Len(t, fake.repositories, 1)
+}
+
+func Test_repositoryOperation_EnsureRepositoriesRemoveFailure(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	fake.failures["DELETE /repositories/helm-hosted"] = 500
+	server.status.Repositories = []v1alpha1.RepositoryStatus{{Name: "helm-hosted", Ready: true, Created: true}}
+
+	assert.Error(t, repositoryOperations(server).EnsureRepositories())
+	// kept in the status to be removed in the next reconciliation
+	assert.Len(t, server.status.Repositories, 1)
+	assert.False(t, server.status.Repositories[0].Ready)
+	assert.NotEmpty(t, server.status.Repositories[0].Reason)
+}
+
+func Test_repositoryOperation_EnsureRepositoriesInvalid(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t,
+		v1alpha1.Repository{Name: "go-hosted", Format: v1alpha1.GoRepositoryFormat, Type: v1alpha1.HostedRepositoryType},
+		v1alpha1.Repository{Name: "npm-proxy", Format: v1alpha1.NpmRepositoryFormat, Type: v1alpha1.ProxyRepositoryType},
+		v1alpha1.Repository{Name: "docker", Format: v1alpha1.DockerRepositoryFormat, Type: v1alpha1.HostedRepositoryType},
+	)
+	fake.addRepository("raw", "hosted", apiRepository{Name: "docker"})
+
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.Empty(t, fake.repositories["go-hosted"])
+	assert.Empty(t, fake.repositories["npm-proxy"])
+	assert.Len(t, server.status.Repositories, 3)
+	for _, status := range server.status.Repositories {
+		assert.False(t, status.Ready)
+		assert.NotEmpty(t, status.Reason)
+	}
+}
+
+func Test_repositoryOperation_EnsureRepositoriesServerError(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t,
+		v1alpha1.Repository{Name: "raw-hosted", Format: v1alpha1.RawRepositoryFormat, Type: v1alpha1.HostedRepositoryType},
+	)
+	fake.failures["POST /repositories/raw/hosted"] = 500
+
+	assert.Error(t, repositoryOperations(server).EnsureRepositories())
+	assert.Len(t, server.status.Repositories, 1)
+	assert.False(t, server.status.Repositories[0].Ready)
+	assert.False(t, server.status.Repositories[0].Created)
+}
+
+func Test_repositoryOperation_EnsureRepositoriesCleanupPolicies(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t,
+		v1alpha1.Repository{Name: "maven-snapshots", Format: v1alpha1.MavenRepositoryFormat, Type: v1alpha1.HostedRepositoryType, Cleanup: &v1alpha1.RepositoryCleanup{PolicyNames: []string{"old-snapshots"}}},
+		v1alpha1.Repository{Name: "maven-all", Format: v1alpha1.MavenRepositoryFormat, Type: v1alpha1.GroupRepositoryType, Group: &v1alpha1.RepositoryGroup{MemberNames: []string{"maven-snapshots"}}, Cleanup: &v1alpha1.RepositoryCleanup{PolicyNames: []string{"old-snapshots"}}},
+	)
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.Equal(t, map[string]interface{}{"policyNames": []interface{}{"old-snapshots"}}, fake.repositories["maven-snapshots"]["cleanup"])
+	// groups can't be cleaned up
+	assert.NotContains(t, fake.repositories, "maven-all")
+	assert.False(t, server.status.Repositories[1].Ready)
+
+	// policies removed from the spec are removed from the repository
+	server.nexus.Spec.Repositories[0].Cleanup = nil
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.True(t, fake.requested("PUT /repositories/maven/hosted/maven-snapshots"))
+	assert.Equal(t, map[string]interface{}{"policyNames": []interface{}{}}, fake.repositories["maven-snapshots"]["cleanup"])
+
+	fake.requests = nil
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.False(t, fake.requested("PUT /repositories/maven/hosted/maven-snapshots"))
+}
+
+func Test_repositoryOperation_EnsureRepositoriesAuthentication(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "upstream-credentials", Namespace: t.Name()},
+		Data:       map[string][]byte{"user": []byte("jdoe"), "password": []byte("s3cr3t")},
+	}
+	server, _ := createNewServerAndKubeCli(t, secret, &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}})
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	server.nexus.Spec.Repositories = []v1alpha1.Repository{{
+		Name:   "private-maven",
+		Format: v1alpha1.MavenRepositoryFormat,
+		Type:   v1alpha1.ProxyRepositoryType,
+		Proxy:  &v1alpha1.RepositoryProxy{RemoteURL: "https://artifactory.example.com/maven/"},
+		HTTPClient: &v1alpha1.RepositoryHTTPClient{Authentication: &v1alpha1.RepositoryAuthentication{
+			CredentialsSecret: v1alpha1.CredentialsSecret{Name: "upstream-credentials", UsernameKey: "user"},
+		}},
+	}}
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.Equal(t, map[string]interface{}{"type": "username", "username": "jdoe", "password": "s3cr3t"},
+		fake.repositories["private-maven"]["httpClient"].(map[string]interface{})["authentication"])
+
+	// nothing changed, the password never returned by the server doesn't trigger updates
+	fake.requests = nil
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.False(t, fake.requested("PUT /repositories/maven/proxy/private-maven"))
+
+	// authentication removed from the spec
+	server.nexus.Spec.Repositories[0].HTTPClient = nil
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.True(t, fake.requested("PUT /repositories/maven/proxy/private-maven"))
+	assert.NotContains(t, fake.repositories["private-maven"]["httpClient"], "authentication")
+
+	// only proxies authenticate
+	server.nexus.Spec.Repositories = append(server.nexus.Spec.Repositories, v1alpha1.Repository{
+		Name: "maven-hosted", Format: v1alpha1.MavenRepositoryFormat, Type: v1alpha1.HostedRepositoryType, HTTPClient: &v1alpha1.RepositoryHTTPClient{},
+	})
+	assert.NoError(t, repositoryOperations(server).EnsureRepositories())
+	assert.False(t, server.status.Repositories[1].Ready)
+}
+
+func TestAddCommReposWithAuthentication(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "red-hat-credentials", Namespace: t.Name()},
+		Data:       map[string][]byte{"username": []byte("jdoe"), "password": []byte("s3cr3t")},
+	}
+	server, _ := createNewServerAndKubeCli(t, secret)
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	server.nexus.Spec.ServerOperations.CommunityMavenProxies = &v1alpha1.CommunityMavenProxies{
+		Proxies: []v1alpha1.CommunityMavenProxy{{
+			Name:           "red-hat-authenticated",
+			RemoteURL:      "https://maven.repository.redhat.com/earlyaccess/",
+			Authentication: &v1alpha1.RepositoryAuthentication{CredentialsSecret: v1alpha1.CredentialsSecret{Name: "red-hat-credentials"}},
+		}},
+	}
+	assert.NoError(t, repositoryOperations(server).EnsureCommunityMavenProxies())
+
+	// created with the REST client, since the aicura client can't send passwords
+	assert.True(t, fake.requested("POST /repositories/maven/proxy"))
+	repo := fake.repositories["red-hat-authenticated"]
+	assert.Equal(t, map[string]interface{}{"type": "username", "username": "jdoe", "password": "s3cr3t"}, repo["httpClient"].(map[string]interface{})["authentication"])
+	assert.Equal(t, float64(-1), repo["proxy"].(map[string]interface{})["contentMaxAge"])
+	assert.True(t, server.status.CommunityRepositoriesCreated)
 }
@@ -0,0 +1,250 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const (
+	restAPIPath = "/service/rest/v1"
+	// the endpoint used by the UI, the only way to reach the capabilities
+	extDirectPath = "/service/extdirect"
+)
+
+// serverHTTPClient reaches the Nexus server directly. The proxy settings in the Operator environment (e.g. `HTTP_PROXY`)
+// are meant for the outbound requests made by the Nexus server and usually don't exclude the in-cluster Service.
+var serverHTTPClient = &http.Client{Transport: newDirectTransport()}
+
+func newDirectTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	return transport
+}
+
+// restClient performs requests against the Nexus REST API endpoints not covered by the aicura client
+type restClient struct {
+	httpClient *http.Client
+	baseURL    string
+	username   string
+	password   string
+}
+
+// restError describes an unexpected status code returned by the Nexus REST API
+type restError struct {
+	statusCode int
+	method     string
+	path       string
+	message    string
+}
+
+func (e *restError) Error() string {
+	return fmt.Sprintf("%s %s returned %d: %s", e.method, e.path, e.statusCode, e.message)
+}
+
+// isRESTNotFound checks if the given error is a Not Found (404) response from the Nexus REST API
+func isRESTNotFound(err error) bool {
+	if restErr, ok := err.(*restError); ok {
+		return restErr.statusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// isRESTAuthenticationError checks if the given error is related to authentication problems (401 or 403)
+func isRESTAuthenticationError(err error) bool {
+	if restErr, ok := err.(*restError); ok {
+		return restErr.statusCode == http.StatusUnauthorized || restErr.statusCode == http.StatusForbidden
+	}
+	return false
+}
+
+func newRESTClient(url, user, pass string) *restClient {
+	return &restClient{
+		httpClient: serverHTTPClient,
+		baseURL:    strings.TrimSuffix(url, "/") + restAPIPath,
+		username:   user,
+		password:   pass,
+	}
+}
+
+// SetCredentials sets the credentials used to authenticate against the Nexus server
+func (c *restClient) SetCredentials(user, pass string) {
+	c.username = user
+	c.password = pass
+}
+
+func (c *restClient) get(path string, v interface{}) error {
+	return c.do(http.MethodGet, path, nil, v)
+}
+
+func (c *restClient) post(path string, body interface{}) error {
+	return c.do(http.MethodPost, path, body, nil)
+}
+
+func (c *restClient) put(path string, body interface{}) error {
+	return c.do(http.MethodPut, path, body, nil)
+}
+
+func (c *restClient) delete(path string) error {
+	return c.do(http.MethodDelete, path, nil, nil)
+}
+
+// putText sends the given text as is, required by the few endpoints not accepting JSON (e.g. changing passwords)
+func (c *restClient) putText(path, text string) error {
+	return c.send(http.MethodPut, path, strings.NewReader(text), "text/plain", nil)
+}
+
+// postPEM sends the given PEM encoded certificate as is, the truststore endpoint reads it as a raw string
+func (c *restClient) postPEM(path, pem string) error {
+	return c.send(http.MethodPost, path, strings.NewReader(pem), "application/json", nil)
+}
+
+// extDirectRequest is a remote procedure call sent to the endpoint used by the UI
+type extDirectRequest struct {
+	Action string      `json:"action"`
+	Method string      `json:"method"`
+	Data   interface{} `json:"data"`
+	Type   string      `json:"type"`
+	TID    int         `json:"tid"`
+}
+
+type extDirectResponse struct {
+	Result *struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"result"`
+	Message string `json:"message"`
+}
+
+// extDirect calls the given method of the given action through the endpoint used by the UI, for the few features not available in the REST API.
+// Errors are reported in the response body instead of the status code.
+func (c *restClient) extDirect(action, method string, data, v interface{}) error {
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(extDirectRequest{Action: action, Method: method, Data: data, Type: "rpc", TID: 1}); err != nil {
+		return err
+	}
+	// the calls are relative to the server root, not to the REST API
+	path := strings.TrimSuffix(c.baseURL, restAPIPath) + extDirectPath
+	resp := &extDirectResponse{}
+	if err := c.sendTo(http.MethodPost, path, buf, "application/json", resp); err != nil {
+		return err
+	}
+	if resp.Result == nil || !resp.Result.Success {
+		message := resp.Message
+		if resp.Result != nil && len(resp.Result.Message) > 0 {
+			message = resp.Result.Message
+		}
+		return fmt.Errorf("%s.%s failed: %s", action, method, message)
+	}
+	if v != nil && len(resp.Result.Data) > 0 {
+		return json.Unmarshal(resp.Result.Data, v)
+	}
+	return nil
+}
+
+func (c *restClient) do(method, path string, body, v interface{}) error {
+	if body == nil {
+		return c.send(method, path, nil, "", v)
+	}
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(body); err != nil {
+		return err
+	}
+	return c.send(method, path, buf, "application/json", v)
+}
+
+func (c *restClient) send(method, path string, body io.Reader, contentType string, v interface{}) error {
+	return c.sendTo(method, c.baseURL+path, body, contentType, v)
+}
+
+func (c *restClient) sendTo(method, url string, body io.Reader, contentType string, v interface{}) error {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if len(c.username) > 0 && len(c.password) > 0 {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		message, _ := ioutil.ReadAll(resp.Body)
+		return &restError{statusCode: resp.StatusCode, method: method, path: strings.TrimPrefix(url, c.baseURL), message: strings.TrimSpace(string(message))}
+	}
+	if v != nil && resp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(resp.Body).Decode(v)
+	}
+	return nil
+}
+
+// jsonContains verifies if every field set in "expected" has the same value in "actual" once both are represented as JSON.
+// Fields only present in "actual" are ignored, which allows us to compare our desired state against a server
+// response that holds more attributes than we manage.
+func jsonContains(actual, expected interface{}) (bool, error) {
+	var actualJSON, expectedJSON interface{}
+	if err := roundTripJSON(actual, &actualJSON); err != nil {
+		return false, err
+	}
+	if err := roundTripJSON(expected, &expectedJSON); err != nil {
+		return false, err
+	}
+	return containsValue(actualJSON, expectedJSON), nil
+}
+
+func roundTripJSON(in, out interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func containsValue(actual, expected interface{}) bool {
+	// the server doesn't always echo enum values in the same case they were sent (e.g. "ALLOW_ONCE" and "allow_once")
+	if expectedStr, ok := expected.(string); ok {
+		actualStr, ok := actual.(string)
+		return ok && strings.EqualFold(actualStr, expectedStr)
+	}
+	expectedMap, ok := expected.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(actual, expected)
+	}
+	actualMap, ok := actual.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, value := range expectedMap {
+		if !containsValue(actualMap[key], value) {
+			return false
+		}
+	}
+	return true
+}
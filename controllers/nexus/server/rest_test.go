@@ -0,0 +1,713 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNexusServer is a minimal in-memory implementation of the Nexus REST API endpoints used by the restClient
+type fakeNexusServer struct {
+	*httptest.Server
+	mutex        sync.Mutex
+	repositories map[string]map[string]interface{}
+	// blob stores by name, holding their type in the "type" key
+	blobStores      map[string]map[string]interface{}
+	cleanupPolicies map[string]map[string]interface{}
+	// tasks by ID, holding what has been sent by the client plus the state of the task
+	tasks map[string]map[string]interface{}
+	users map[string]apiUser
+	roles map[string]apiRole
+	// password of the admin user
+	adminPassword string
+	// contents of the admin.password file, removed by the server once the admin password changes
+	adminPasswordFile string
+	// anonymous access configuration
+	anonymous map[string]interface{}
+	// outbound HTTP settings, including the proxy servers
+	httpSettings map[string]interface{}
+	// passwords of the users other than the admin
+	passwords map[string]string
+	// LDAP servers by name, holding their bind password
+	ldapServers map[string]map[string]interface{}
+	// IDs of the active realms, in order
+	activeRealms []string
+	// PEM encoded certificates in the truststore
+	truststore []string
+	// pro enables the features only available in Nexus Repository Pro, such as SAML
+	pro bool
+	// SAML configuration, nil if not configured
+	saml map[string]interface{}
+	// capabilities by ID, only reachable through the endpoint used by the UI
+	capabilities map[string]extDirectCapability
+	// requests holds every "METHOD path" received by the server
+	requests []string
+	// failures maps a "METHOD path" to the status code the server must respond with
+	failures map[string]int
+}
+
+func newFakeNexusServer(t *testing.T) *fakeNexusServer {
+	fake := &fakeNexusServer{
+		repositories:    map[string]map[string]interface{}{},
+		blobStores:      map[string]map[string]interface{}{},
+		cleanupPolicies: map[string]map[string]interface{}{},
+		tasks:           map[string]map[string]interface{}{},
+		users:           map[string]apiUser{},
+		roles:           map[string]apiRole{},
+		passwords:       map[string]string{},
+		ldapServers:     map[string]map[string]interface{}{},
+		activeRealms:    []string{"NexusAuthenticatingRealm", "NexusAuthorizingRealm"},
+		capabilities:    map[string]extDirectCapability{},
+		failures:        map[string]int{},
+		adminPassword:   defaultAdminPassword,
+		anonymous:       map[string]interface{}{"enabled": true, "userId": "anonymous", "realmName": "NexusAuthorizingRealm"},
+		httpSettings: map[string]interface{}{
+			"httpProxy":     map[string]interface{}{"enabled": false},
+			"httpsProxy":    map[string]interface{}{"enabled": false},
+			"nonProxyHosts": []interface{}{},
+		},
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+// client creates a new restClient pointing to this fake server
+func (f *fakeNexusServer) client() *restClient {
+	return newRESTClient(f.URL, defaultAdminUsername, defaultAdminPassword)
+}
+
+// addRepository adds a repository to the server as if it had been created by a third party
+func (f *fakeNexusServer) addRepository(format, repoType string, repo interface{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	var stored map[string]interface{}
+	raw, _ := json.Marshal(repo)
+	_ = json.Unmarshal(raw, &stored)
+	stored["format"] = format
+	stored["type"] = repoType
+	f.repositories[stored["name"].(string)] = stored
+}
+
+func (f *fakeNexusServer) requested(request string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, r := range f.requests {
+		if r == request {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeNexusServer) handle(w http.ResponseWriter, req *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, restAPIPath)
+	request := fmt.Sprintf("%s %s", req.Method, path)
+	f.requests = append(f.requests, request)
+	if status, ok := f.failures[request]; ok {
+		w.WriteHeader(status)
+		return
+	}
+	user, pass, ok := req.BasicAuth()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if user != defaultAdminUsername || pass != f.adminPassword {
+		// disabled users can't log in, whatever their password
+		if password, found := f.passwords[user]; !found || password != pass || f.users[user].Status == userStatusDisabled {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !f.isAdmin(user) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(path, repositoriesRESTPath):
+		f.handleRepositories(w, req, strings.Split(strings.Trim(strings.TrimPrefix(path, repositoriesRESTPath), "/"), "/"))
+	case strings.HasPrefix(path, blobStoresRESTPath):
+		f.handleBlobStores(w, req, strings.Split(strings.Trim(strings.TrimPrefix(path, blobStoresRESTPath), "/"), "/"))
+	case strings.HasPrefix(path, cleanupPoliciesRESTPath):
+		f.handleCleanupPolicies(w, req, strings.Trim(strings.TrimPrefix(path, cleanupPoliciesRESTPath), "/"))
+	case strings.HasPrefix(path, tasksRESTPath):
+		f.handleTasks(w, req, strings.Trim(strings.TrimPrefix(path, tasksRESTPath), "/"))
+	case strings.HasPrefix(path, usersRESTPath):
+		f.handleUsers(w, req, strings.Split(strings.Trim(strings.TrimPrefix(path, usersRESTPath), "/"), "/"))
+	case path == anonymousRESTPath && req.Method == http.MethodGet:
+		writeJSON(w, f.anonymous)
+	case path == anonymousRESTPath && req.Method == http.MethodPut:
+		if err := json.NewDecoder(req.Body).Decode(&f.anonymous); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, f.anonymous)
+	case path == httpSettingsRESTPath && req.Method == http.MethodGet:
+		// the server never returns the passwords
+		writeJSON(w, withoutPasswords(f.httpSettings))
+	case path == httpSettingsRESTPath && req.Method == http.MethodPut:
+		settings := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&settings); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.httpSettings = settings
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasPrefix(path, ldapServersRESTPath):
+		f.handleLDAPServers(w, req, strings.Trim(strings.TrimPrefix(path, ldapServersRESTPath), "/"))
+	case path == activeRealmsRESTPath && req.Method == http.MethodGet:
+		writeJSON(w, f.activeRealms)
+	case path == activeRealmsRESTPath && req.Method == http.MethodPut:
+		if err := json.NewDecoder(req.Body).Decode(&f.activeRealms); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case path == truststoreRESTPath && req.Method == http.MethodGet:
+		certificates := []apiCertificate{}
+		for i, certificate := range f.truststore {
+			block, _ := pem.Decode([]byte(certificate))
+			certificates = append(certificates, apiCertificate{ID: strconv.Itoa(i), Fingerprint: certificateFingerprint(block.Bytes), PEM: certificate})
+		}
+		writeJSON(w, certificates)
+	case path == truststoreRESTPath && req.Method == http.MethodPost:
+		certificate, _ := ioutil.ReadAll(req.Body)
+		if block, _ := pem.Decode(certificate); block == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.truststore = append(f.truststore, string(certificate))
+		w.WriteHeader(http.StatusCreated)
+	case path == samlRESTPath && f.pro && req.Method == http.MethodGet:
+		if f.saml == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, f.saml)
+	case path == samlRESTPath && f.pro && req.Method == http.MethodPut:
+		if err := json.NewDecoder(req.Body).Decode(&f.saml); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case path == extDirectPath && req.Method == http.MethodPost:
+		f.handleExtDirect(w, req)
+	case strings.HasPrefix(path, rolesRESTPath):
+		f.handleRoles(w, req, strings.Trim(strings.TrimPrefix(path, rolesRESTPath), "/"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// isAdmin verifies if the given user holds the admin role, being allowed to do anything in this server
+func (f *fakeNexusServer) isAdmin(userID string) bool {
+	for _, role := range f.users[userID].Roles {
+		if role == adminRole {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeNexusServer) handleUsers(w http.ResponseWriter, req *http.Request, segments []string) {
+	switch {
+	case req.Method == http.MethodGet && segments[0] == "":
+		users := []apiUser{}
+		for id, user := range f.users {
+			if strings.HasPrefix(id, req.URL.Query().Get("userId")) {
+				users = append(users, user)
+			}
+		}
+		writeJSON(w, users)
+	case req.Method == http.MethodPost && segments[0] == "":
+		user := apiUser{}
+		if err := json.NewDecoder(req.Body).Decode(&user); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.passwords[user.UserID] = user.Password
+		user.Password = ""
+		f.users[user.UserID] = user
+		writeJSON(w, user)
+	case req.Method == http.MethodPut && len(segments) == 2 && segments[1] == "change-password" && segments[0] == defaultAdminUsername:
+		password, _ := ioutil.ReadAll(req.Body)
+		f.adminPassword = string(password)
+		f.adminPasswordFile = ""
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodPut && len(segments) == 2 && segments[1] == "change-password":
+		if _, ok := f.users[segments[0]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		password, _ := ioutil.ReadAll(req.Body)
+		f.passwords[segments[0]] = string(password)
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 1:
+		if _, ok := f.users[segments[0]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Method == http.MethodDelete {
+			delete(f.users, segments[0])
+			delete(f.passwords, segments[0])
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		user := apiUser{}
+		if err := json.NewDecoder(req.Body).Decode(&user); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.users[segments[0]] = user
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeNexusServer) handleRoles(w http.ResponseWriter, req *http.Request, id string) {
+	if len(id) == 0 {
+		role := apiRole{}
+		if err := json.NewDecoder(req.Body).Decode(&role); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.roles[role.ID] = role
+		writeJSON(w, role)
+		return
+	}
+	role, ok := f.roles[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(w, role)
+	case http.MethodDelete:
+		delete(f.roles, id)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPut:
+		if err := json.NewDecoder(req.Body).Decode(&role); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.roles[id] = role
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeNexusServer) handleLDAPServers(w http.ResponseWriter, req *http.Request, name string) {
+	if req.Method == http.MethodPost && len(name) == 0 {
+		ldap := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&ldap); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := f.ldapServers[ldap["name"].(string)]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ldap["id"] = fmt.Sprintf("ldap-%d", len(f.ldapServers))
+		f.ldapServers[ldap["name"].(string)] = ldap
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	current, ok := f.ldapServers[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		// the server never returns the bind password
+		masked := map[string]interface{}{}
+		for key, value := range current {
+			if key != "authPassword" {
+				masked[key] = value
+			}
+		}
+		writeJSON(w, masked)
+	case http.MethodPut:
+		ldap := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&ldap); err != nil || ldap["id"] != current["id"] {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.ldapServers[name] = ldap
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(f.ldapServers, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleExtDirect implements the capability methods of the endpoint used by the UI, which always responds with 200
+func (f *fakeNexusServer) handleExtDirect(w http.ResponseWriter, req *http.Request) {
+	call := struct {
+		Action string                `json:"action"`
+		Method string                `json:"method"`
+		Data   []extDirectCapability `json:"data"`
+	}{}
+	if err := json.NewDecoder(req.Body).Decode(&call); err != nil || call.Action != capabilityExtDirectAction {
+		writeJSON(w, map[string]interface{}{"type": "exception", "message": "unknown action"})
+		return
+	}
+	f.requests = append(f.requests, fmt.Sprintf("%s %s.%s", req.Method, call.Action, call.Method))
+	switch call.Method {
+	case "read":
+		capabilities := []extDirectCapability{}
+		for _, capability := range f.capabilities {
+			capabilities = append(capabilities, capability)
+		}
+		writeJSON(w, map[string]interface{}{"type": "rpc", "result": map[string]interface{}{"success": true, "data": capabilities}})
+	case "create", "update":
+		capability := call.Data[0]
+		if call.Method == "create" {
+			capability.ID = fmt.Sprintf("capability-%d", len(f.capabilities))
+		} else if _, ok := f.capabilities[capability.ID]; !ok {
+			writeJSON(w, map[string]interface{}{"type": "rpc", "result": map[string]interface{}{"success": false, "message": "capability not found"}})
+			return
+		}
+		f.capabilities[capability.ID] = capability
+		writeJSON(w, map[string]interface{}{"type": "rpc", "result": map[string]interface{}{"success": true, "data": capability}})
+	default:
+		writeJSON(w, map[string]interface{}{"type": "exception", "message": "unknown method"})
+	}
+}
+
+func (f *fakeNexusServer) handleRepositories(w http.ResponseWriter, req *http.Request, segments []string) {
+	switch {
+	case req.Method == http.MethodGet && segments[0] == "":
+		var refs []apiRepositoryRef
+		for name, repo := range f.repositories {
+			refs = append(refs, apiRepositoryRef{Name: name, Format: repo["format"].(string), Type: repo["type"].(string), URL: fmt.Sprintf("%s/repository/%s", f.URL, name)})
+		}
+		writeJSON(w, refs)
+	case req.Method == http.MethodDelete && len(segments) == 1:
+		if _, ok := f.repositories[segments[0]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.repositories, segments[0])
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodPost && len(segments) == 2:
+		repo := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&repo); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := f.repositories[repo["name"].(string)]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		repo["format"], repo["type"] = restFormat(segments[0]), segments[1]
+		f.repositories[repo["name"].(string)] = repo
+		w.WriteHeader(http.StatusCreated)
+	case len(segments) == 3:
+		current, ok := f.repositories[segments[2]]
+		if !ok || current["format"] != restFormat(segments[0]) || current["type"] != segments[1] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Method == http.MethodGet {
+			writeJSON(w, withoutPasswords(current))
+			return
+		}
+		repo := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&repo); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		repo["format"], repo["type"] = current["format"], current["type"]
+		f.repositories[segments[2]] = repo
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeNexusServer) handleBlobStores(w http.ResponseWriter, req *http.Request, segments []string) {
+	switch {
+	case req.Method == http.MethodGet && segments[0] == "":
+		refs := []map[string]interface{}{}
+		for name, store := range f.blobStores {
+			refs = append(refs, map[string]interface{}{"name": name, "type": store["type"], "softQuota": store["softQuota"], "blobCount": 10, "totalSizeInBytes": 2048, "availableSpaceInBytes": 4096})
+		}
+		writeJSON(w, refs)
+	case req.Method == http.MethodDelete && len(segments) == 1:
+		if _, ok := f.blobStores[segments[0]]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.blobStores, segments[0])
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodPost && len(segments) == 1:
+		store := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&store); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := f.blobStores[store["name"].(string)]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		store["type"] = blobStoreType(segments[0])
+		f.blobStores[store["name"].(string)] = store
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 2:
+		current, ok := f.blobStores[segments[1]]
+		if !ok || current["type"] != blobStoreType(segments[0]) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Method == http.MethodGet {
+			// the server never returns the secrets
+			raw, _ := json.Marshal(current)
+			masked := map[string]interface{}{}
+			_ = json.Unmarshal(raw, &masked)
+			delete(masked, "name")
+			if bucketConfiguration, ok := masked["bucketConfiguration"].(map[string]interface{}); ok {
+				if security, ok := bucketConfiguration["bucketSecurity"].(map[string]interface{}); ok {
+					delete(security, "secretAccessKey")
+					delete(security, "sessionToken")
+				}
+			}
+			writeJSON(w, masked)
+			return
+		}
+		store := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&store); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		store["type"] = current["type"]
+		f.blobStores[segments[1]] = store
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeNexusServer) handleCleanupPolicies(w http.ResponseWriter, req *http.Request, name string) {
+	switch {
+	case req.Method == http.MethodGet && len(name) == 0:
+		policies := []map[string]interface{}{}
+		for _, policy := range f.cleanupPolicies {
+			policies = append(policies, policy)
+		}
+		writeJSON(w, policies)
+	case req.Method == http.MethodPost && len(name) == 0:
+		policy := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&policy); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, ok := f.cleanupPolicies[policy["name"].(string)]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.cleanupPolicies[policy["name"].(string)] = policy
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut:
+		if _, ok := f.cleanupPolicies[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		policy := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&policy); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.cleanupPolicies[name] = policy
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodDelete:
+		if _, ok := f.cleanupPolicies[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.cleanupPolicies, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// fakeTaskPageSize is small enough to make the client go through more than one page
+const fakeTaskPageSize = 2
+
+func (f *fakeNexusServer) handleTasks(w http.ResponseWriter, req *http.Request, id string) {
+	switch {
+	case req.Method == http.MethodGet && len(id) == 0:
+		var ids []string
+		for taskID := range f.tasks {
+			ids = append(ids, taskID)
+		}
+		sort.Strings(ids)
+		start, _ := strconv.Atoi(req.URL.Query().Get("continuationToken"))
+		page := map[string]interface{}{"items": []map[string]interface{}{}}
+		for i := start; i < len(ids) && i < start+fakeTaskPageSize; i++ {
+			task := f.tasks[ids[i]]
+			page["items"] = append(page["items"].([]map[string]interface{}), map[string]interface{}{
+				"id": ids[i], "name": task["name"], "type": task["type"], "currentState": "WAITING",
+				"lastRunResult": task["lastRunResult"], "lastRun": task["lastRun"], "nextRun": task["nextRun"],
+			})
+		}
+		if start+fakeTaskPageSize < len(ids) {
+			page["continuationToken"] = strconv.Itoa(start + fakeTaskPageSize)
+		}
+		writeJSON(w, page)
+	case req.Method == http.MethodPost && len(id) == 0:
+		task := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&task); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.tasks[fmt.Sprintf("task-%d", len(f.requests))] = task
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut:
+		if _, ok := f.tasks[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		task := map[string]interface{}{}
+		if err := json.NewDecoder(req.Body).Decode(&task); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.tasks[id] = task
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodDelete:
+		if _, ok := f.tasks[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.tasks, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// taskByName finds a task in the server by its name
+func (f *fakeNexusServer) taskByName(name string) (string, map[string]interface{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for id, task := range f.tasks {
+		if task["name"] == name {
+			return id, task
+		}
+	}
+	return "", nil
+}
+
+// withoutPasswords returns a copy of the given JSON object without the "password" keys
+func withoutPasswords(object map[string]interface{}) map[string]interface{} {
+	masked := map[string]interface{}{}
+	for key, value := range object {
+		if key == "password" {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = withoutPasswords(nested)
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+func blobStoreType(pathType string) string {
+	if pathType == "s3" {
+		return "S3"
+	}
+	return "File"
+}
+
+func restFormat(pathFormat string) string {
+	if pathFormat == "maven" {
+		return "maven2"
+	}
+	return pathFormat
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func Test_restClient_errors(t *testing.T) {
+	fake := newFakeNexusServer(t)
+	cli := fake.client()
+
+	err := cli.delete(repositoriesRESTPath + "/nonexistent")
+	assert.Error(t, err)
+	assert.True(t, isRESTNotFound(err))
+	assert.False(t, isRESTAuthenticationError(err))
+
+	cli.SetCredentials("admin", "wrong")
+	err = cli.get(repositoriesRESTPath, &[]apiRepositoryRef{})
+	assert.Error(t, err)
+	assert.True(t, isRESTAuthenticationError(err))
+	assert.False(t, isRESTNotFound(err))
+}
+
+func Test_jsonContains(t *testing.T) {
+	actual := map[string]interface{}{
+		"name":    "npm-proxy",
+		"online":  true,
+		"storage": map[string]interface{}{"blobStoreName": "default", "writePolicy": "allow_once"},
+		"group":   map[string]interface{}{"memberNames": []string{"a", "b"}},
+	}
+	tests := []struct {
+		name     string
+		expected interface{}
+		want     bool
+	}{
+		{"subset", map[string]interface{}{"name": "npm-proxy"}, true},
+		{"missing nested field", apiRepository{Name: "npm-proxy", Online: true, Storage: apiStorage{BlobStoreName: "default", WritePolicy: "ALLOW_ONCE"}}, false},
+		{"enum case", map[string]interface{}{"storage": map[string]interface{}{"writePolicy": "ALLOW_ONCE"}}, true},
+		{"different value", map[string]interface{}{"online": false}, false},
+		{"missing field", map[string]interface{}{"proxy": map[string]interface{}{"remoteUrl": "http://example.com"}}, false},
+		{"different list", map[string]interface{}{"group": map[string]interface{}{"memberNames": []string{"b", "a"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonContains(actual, tt.expected)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
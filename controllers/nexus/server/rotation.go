@@ -0,0 +1,149 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/m88i/nexus-operator/pkg/framework"
+	"github.com/m88i/nexus-operator/pkg/framework/kind"
+)
+
+const (
+	// SecretKeyPendingPassword secret key for the Operator User password while it's being rotated
+	SecretKeyPendingPassword = "server-user-password-pending"
+	// PasswordRotatedAtAnnotation holds when the Operator User password has been rotated for the last time, in RFC 3339 format
+	PasswordRotatedAtAnnotation = "nexus.apps.m88i.io/server-user-password-rotated-at"
+)
+
+// ensurePasswordRotation rotates the operator user password if the rotation period has elapsed.
+// The rotation happens in three steps, so that a failure in any of them can be recovered from in the next reconciliation:
+//  1. the new password is stored in the Secret as pending;
+//  2. the password is changed in the server;
+//  3. the pending password replaces the current one in the Secret.
+func (u *userOperation) ensurePasswordRotation() error {
+	secret := &corev1.Secret{}
+	if err := framework.Fetch(u.k8sclient, framework.Key(u.nexus), secret, kind.SecretKind); err != nil {
+		return err
+	}
+	current, pending := string(secret.Data[SecretKeyPassword]), string(secret.Data[SecretKeyPendingPassword])
+	if len(current) == 0 {
+		return nil
+	}
+	if len(pending) > 0 {
+		if recovered, err := u.recoverPasswordRotation(secret, current, pending); err != nil || recovered {
+			return err
+		}
+	}
+
+	rotatedAt := passwordRotatedAt(secret)
+	u.status.OperatorUserPasswordRotatedAt = rotatedAt
+	rotation := u.nexus.Spec.ServerOperations.CredentialRotation
+	if rotation == nil || rotation.Period.Duration <= 0 {
+		return nil
+	}
+	if rotatedAt != nil && time.Now().Before(rotatedAt.Add(rotation.Period.Duration)) {
+		return nil
+	}
+
+	log.Info("Rotating the operator user password", "Period", rotation.Period.Duration.String())
+	password, err := GenerateRandomPassword()
+	if err != nil {
+		return err
+	}
+	setSecretData(secret, SecretKeyPendingPassword, password)
+	if err := u.k8sclient.Update(context.TODO(), secret); err != nil {
+		return fmt.Errorf("failed to store the pending operator user password: %v", err)
+	}
+	if err := u.restcli.putText(fmt.Sprintf("%s/%s/change-password", usersRESTPath, operatorUsername), password); err != nil {
+		u.createPasswordRotationFailureEvent(err)
+		return fmt.Errorf("failed to rotate the operator user password: %v", err)
+	}
+	return u.commitPasswordRotation(password)
+}
+
+// recoverPasswordRotation completes or discards a rotation interrupted in a previous reconciliation.
+// Returns true if the rotation has been completed.
+func (u *userOperation) recoverPasswordRotation(secret *corev1.Secret, current, pending string) (bool, error) {
+	log.Debug("Found a pending operator user password, recovering from an interrupted rotation")
+	if authenticated, err := u.restcli.authenticates(operatorUsername, pending); err != nil {
+		return false, err
+	} else if authenticated {
+		// the server already has the new password, only the Secret is behind
+		return true, u.commitPasswordRotation(pending)
+	}
+	if authenticated, err := u.restcli.authenticates(operatorUsername, current); err != nil {
+		return false, err
+	} else if !authenticated {
+		return false, fmt.Errorf("neither the current nor the pending operator user password are accepted by the server")
+	}
+	// the server never got the new password, start over
+	delete(secret.Data, SecretKeyPendingPassword)
+	delete(secret.StringData, SecretKeyPendingPassword)
+	if err := u.k8sclient.Update(context.TODO(), secret); err != nil {
+		return false, err
+	}
+	log.Info("Discarded the pending operator user password, the server never accepted it")
+	return false, nil
+}
+
+// commitPasswordRotation replaces the current operator user password in the Secret by the given one in a single update
+func (u *userOperation) commitPasswordRotation(password string) error {
+	secret := &corev1.Secret{}
+	if err := framework.Fetch(u.k8sclient, framework.Key(u.nexus), secret, kind.SecretKind); err != nil {
+		return err
+	}
+	delete(secret.Data, SecretKeyPendingPassword)
+	delete(secret.StringData, SecretKeyPendingPassword)
+	setSecretData(secret, SecretKeyPassword, password)
+	setPasswordRotatedAt(secret, time.Now())
+	if err := u.k8sclient.Update(context.TODO(), secret); err != nil {
+		u.createPasswordRotationFailureEvent(err)
+		return fmt.Errorf("failed to store the rotated operator user password: %v", err)
+	}
+	u.status.OperatorUserPasswordRotatedAt = passwordRotatedAt(secret)
+	u.setCredentials(operatorUsername, password)
+	log.Info("Operator user password rotated")
+	u.createPasswordRotatedEvent()
+	return nil
+}
+
+func setSecretData(secret *corev1.Secret, key, value string) {
+	if secret.StringData == nil {
+		secret.StringData = make(map[string]string)
+	}
+	secret.StringData[key] = value
+}
+
+func setPasswordRotatedAt(secret *corev1.Secret, t time.Time) {
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[PasswordRotatedAtAnnotation] = t.UTC().Format(time.RFC3339)
+}
+
+// passwordRotatedAt reads when the operator user password has been rotated for the last time, nil if unknown
+func passwordRotatedAt(secret *corev1.Secret) *metav1.Time {
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[PasswordRotatedAtAnnotation])
+	if err != nil {
+		return nil
+	}
+	return &metav1.Time{Time: rotatedAt}
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/framework"
+)
+
+// createNewServerWithOperatorUser creates a new server authenticated as the operator user in a fake Nexus server
+func createNewServerWithOperatorUser(t *testing.T, secretData map[string][]byte, rotatedAt time.Time) (*userOperation, *fakeNexusServer, client.Client) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}, Data: secretData}
+	if !rotatedAt.IsZero() {
+		setPasswordRotatedAt(secret, rotatedAt)
+	}
+	server, cli := createNewServerAndKubeCli(t, secret)
+	fake := newFakeNexusServer(t)
+	fake.users[operatorUsername] = apiUser{UserID: operatorUsername, Roles: []string{adminRole}}
+	fake.passwords[operatorUsername] = "old"
+	server.restcli = fake.client()
+	server.setCredentials(operatorUsername, "old")
+	return &userOperation{server: *server}, fake, cli
+}
+
+func fetchInstanceSecret(t *testing.T, cli client.Client, nexus *v1alpha1.Nexus) *corev1.Secret {
+	secret := &corev1.Secret{}
+	assert.NoError(t, cli.Get(context.TODO(), framework.Key(nexus), secret))
+	return secret
+}
+
+func withRotationPeriod(u *userOperation, period time.Duration) {
+	u.nexus.Spec.ServerOperations.CredentialRotation = &v1alpha1.CredentialRotation{Period: metav1.Duration{Duration: period}}
+}
+
+func Test_userOperation_ensurePasswordRotationDisabled(t *testing.T) {
+	u, fake, _ := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old")}, time.Time{})
+	assert.NoError(t, u.ensurePasswordRotation())
+	assert.Empty(t, fake.requests)
+	assert.Equal(t, "old", fake.passwords[operatorUsername])
+}
+
+func Test_userOperation_ensurePasswordRotationPeriodNotElapsed(t *testing.T) {
+	u, fake, _ := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old")}, time.Now().Add(-time.Hour))
+	withRotationPeriod(u, 24*time.Hour)
+	assert.NoError(t, u.ensurePasswordRotation())
+	assert.Empty(t, fake.requests)
+}
+
+func Test_userOperation_ensurePasswordRotation(t *testing.T) {
+	u, fake, cli := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old")}, time.Now().Add(-25*time.Hour))
+	withRotationPeriod(u, 24*time.Hour)
+	assert.NoError(t, u.ensurePasswordRotation())
+
+	rotated := fake.passwords[operatorUsername]
+	assert.NotEqual(t, "old", rotated)
+	secret := fetchInstanceSecret(t, cli, u.nexus)
+	assert.Equal(t, rotated, secret.StringData[SecretKeyPassword])
+	assert.NotContains(t, secret.StringData, SecretKeyPendingPassword)
+	rotatedAt, err := time.Parse(time.RFC3339, secret.Annotations[PasswordRotatedAtAnnotation])
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), rotatedAt, time.Minute)
+	assert.Equal(t, rotated, u.restcli.password)
+
+	events := &corev1.EventList{}
+	assert.NoError(t, cli.List(context.TODO(), events))
+	assert.Len(t, events.Items, 1)
+	assert.Equal(t, passwordRotatedReason, events.Items[0].Reason)
+}
+
+func Test_userOperation_ensurePasswordRotationNeverRotated(t *testing.T) {
+	u, fake, _ := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old")}, time.Time{})
+	withRotationPeriod(u, 24*time.Hour)
+	assert.NoError(t, u.ensurePasswordRotation())
+	assert.NotEqual(t, "old", fake.passwords[operatorUsername])
+}
+
+func Test_userOperation_ensurePasswordRotationServerFailure(t *testing.T) {
+	u, fake, cli := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old")}, time.Time{})
+	withRotationPeriod(u, 24*time.Hour)
+	fake.failures["PUT /security/users/nexus-operator/change-password"] = http.StatusInternalServerError
+
+	assert.Error(t, u.ensurePasswordRotation())
+	assert.Equal(t, "old", fake.passwords[operatorUsername])
+	// kept to be recovered from in the next reconciliation
+	secret := fetchInstanceSecret(t, cli, u.nexus)
+	assert.NotEmpty(t, secret.StringData[SecretKeyPendingPassword])
+
+	events := &corev1.EventList{}
+	assert.NoError(t, cli.List(context.TODO(), events))
+	assert.Len(t, events.Items, 1)
+	assert.Equal(t, passwordRotationFailedReason, events.Items[0].Reason)
+}
+
+func Test_userOperation_ensurePasswordRotationRecoversCommittedPassword(t *testing.T) {
+	// the server accepted the new password, but the Secret was never updated
+	u, fake, cli := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old"), SecretKeyPendingPassword: []byte("new")}, time.Now().Add(-25*time.Hour))
+	fake.passwords[operatorUsername] = "new"
+
+	assert.NoError(t, u.ensurePasswordRotation())
+	secret := fetchInstanceSecret(t, cli, u.nexus)
+	assert.Equal(t, "new", secret.StringData[SecretKeyPassword])
+	assert.NotContains(t, secret.Data, SecretKeyPendingPassword)
+	assert.Equal(t, "new", u.restcli.password)
+	assert.False(t, fake.requested("PUT /security/users/nexus-operator/change-password"))
+}
+
+func Test_userOperation_ensurePasswordRotationDiscardsPendingPassword(t *testing.T) {
+	// the server never got the new password
+	u, fake, cli := createNewServerWithOperatorUser(t, map[string][]byte{SecretKeyPassword: []byte("old"), SecretKeyPendingPassword: []byte("new")}, time.Now().Add(-time.Hour))
+	withRotationPeriod(u, 24*time.Hour)
+
+	assert.NoError(t, u.ensurePasswordRotation())
+	secret := fetchInstanceSecret(t, cli, u.nexus)
+	assert.NotContains(t, secret.Data, SecretKeyPendingPassword)
+	assert.Equal(t, "old", fake.passwords[operatorUsername])
+	assert.Equal(t, "old", u.restcli.password)
+}
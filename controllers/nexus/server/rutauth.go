@@ -0,0 +1,72 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const (
+	// the Remote User Token header can only be set through its capability, which isn't available in the REST API
+	capabilityExtDirectAction = "capability_Capability"
+	rutAuthCapabilityType     = "rutauth"
+	rutAuthHeaderProperty     = "httpHeader"
+)
+
+// extDirectCapability is the representation of a capability sent to and read from the endpoint used by the UI
+type extDirectCapability struct {
+	ID         string            `json:"id,omitempty"`
+	TypeID     string            `json:"typeId"`
+	Enabled    bool              `json:"enabled"`
+	Notes      string            `json:"notes"`
+	Properties map[string]string `json:"properties"`
+}
+
+// ensureRUTAuth makes sure that the Remote User Token capability reads the user ID from the given header
+func (r *realmOperation) ensureRUTAuth(rutAuth v1alpha1.RUTAuth) error {
+	header := stringOrDefault(rutAuth.HeaderName, v1alpha1.DefaultRUTAuthHeader)
+	log.Debug("Attempt to fetch the Remote User Token capability from the server")
+	var capabilities []extDirectCapability
+	if err := r.restcli.extDirect(capabilityExtDirectAction, "read", nil, &capabilities); err != nil {
+		return err
+	}
+	desired := extDirectCapability{
+		TypeID:     rutAuthCapabilityType,
+		Enabled:    true,
+		Notes:      "Managed by the Nexus Operator",
+		Properties: map[string]string{rutAuthHeaderProperty: header},
+	}
+	for _, capability := range capabilities {
+		if capability.TypeID != rutAuthCapabilityType {
+			continue
+		}
+		if capability.Enabled && capability.Properties[rutAuthHeaderProperty] == header {
+			return nil
+		}
+		log.Debug("Remote User Token capability differs from the desired state, trying to update it")
+		desired.ID, desired.Notes = capability.ID, capability.Notes
+		if err := r.restcli.extDirect(capabilityExtDirectAction, "update", []extDirectCapability{desired}, nil); err != nil {
+			return err
+		}
+		log.Info("Remote User Token capability updated", "Header", header)
+		return nil
+	}
+	log.Debug("Remote User Token capability not found, trying to create it")
+	if err := r.restcli.extDirect(capabilityExtDirectAction, "create", []extDirectCapability{desired}, nil); err != nil {
+		return err
+	}
+	log.Info("Remote User Token capability created", "Header", header)
+	return nil
+}
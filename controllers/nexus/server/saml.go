@@ -0,0 +1,95 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/framework"
+	"github.com/m88i/nexus-operator/pkg/framework/kind"
+)
+
+const samlRESTPath = "/security/saml"
+
+// errSAMLUnavailable is returned when the server doesn't know the SAML endpoints, which are only available in Nexus Repository Pro
+var errSAMLUnavailable = errors.New("SAML is only available in Nexus Repository Pro, check the server license or remove 'spec.security.saml'")
+
+// apiSAML is the representation of the SAML configuration sent to and read from the Nexus REST API
+type apiSAML struct {
+	IdPMetadata                string `json:"idpMetadata"`
+	EntityID                   string `json:"entityId,omitempty"`
+	ValidateResponseSignature  *bool  `json:"validateResponseSignature,omitempty"`
+	ValidateAssertionSignature *bool  `json:"validateAssertionSignature,omitempty"`
+	UsernameAttribute          string `json:"usernameAttribute"`
+	FirstNameAttribute         string `json:"firstNameAttribute,omitempty"`
+	LastNameAttribute          string `json:"lastNameAttribute,omitempty"`
+	EmailAttribute             string `json:"emailAttribute,omitempty"`
+	GroupsAttribute            string `json:"groupsAttribute,omitempty"`
+}
+
+// ensureSAML makes sure that the SAML configuration in the Nexus server matches the given one, reverting any change made by hand
+func (r *realmOperation) ensureSAML(saml v1alpha1.SAML) error {
+	desired, err := r.newAPISAML(saml)
+	if err != nil {
+		return err
+	}
+	log.Debug("Attempt to fetch the SAML configuration from the server")
+	var actual map[string]interface{}
+	if err := r.restcli.get(samlRESTPath, &actual); err != nil && !isRESTNotFound(err) {
+		return err
+	} else if err == nil {
+		if equal, err := jsonContains(actual, desired); err != nil || equal {
+			return err
+		}
+	}
+	log.Debug("SAML configuration differs from the desired state, trying to update it")
+	if err := r.restcli.put(samlRESTPath, desired); err != nil {
+		if isRESTNotFound(err) {
+			return errSAMLUnavailable
+		}
+		return err
+	}
+	log.Info("SAML configuration updated")
+	return nil
+}
+
+// newAPISAML converts the given SAML configuration into its API representation, reading the identity provider metadata from its ConfigMap
+func (r *realmOperation) newAPISAML(saml v1alpha1.SAML) (*apiSAML, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := framework.Fetch(r.k8sclient, types.NamespacedName{Namespace: r.nexus.Namespace, Name: saml.IdPMetadata.Name}, configMap, kind.ConfigMapKind); err != nil {
+		return nil, fmt.Errorf("failed to fetch the SAML identity provider metadata from %s: %v", saml.IdPMetadata.Name, err)
+	}
+	metadata := configMap.Data[saml.IdPMetadata.Key]
+	if len(strings.TrimSpace(metadata)) == 0 {
+		return nil, fmt.Errorf("no SAML identity provider metadata found in the key %s of %s", saml.IdPMetadata.Key, saml.IdPMetadata.Name)
+	}
+	return &apiSAML{
+		IdPMetadata:                metadata,
+		EntityID:                   saml.EntityID,
+		ValidateResponseSignature:  saml.ValidateResponseSignature,
+		ValidateAssertionSignature: saml.ValidateAssertionSignature,
+		UsernameAttribute:          saml.UsernameAttribute,
+		FirstNameAttribute:         saml.FirstNameAttribute,
+		LastNameAttribute:          saml.LastNameAttribute,
+		EmailAttribute:             saml.EmailAttribute,
+		GroupsAttribute:            saml.GroupsAttribute,
+	}, nil
+}
@@ -0,0 +1,272 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const (
+	usersRESTPath = "/security/users"
+	rolesRESTPath = "/security/roles"
+
+	userStatusActive   = "active"
+	userStatusDisabled = "disabled"
+)
+
+type apiUser struct {
+	UserID        string   `json:"userId"`
+	FirstName     string   `json:"firstName"`
+	LastName      string   `json:"lastName"`
+	EmailAddress  string   `json:"emailAddress"`
+	Source        string   `json:"source,omitempty"`
+	Status        string   `json:"status"`
+	ReadOnly      bool     `json:"readOnly,omitempty"`
+	Roles         []string `json:"roles"`
+	ExternalRoles []string `json:"externalRoles,omitempty"`
+	Password      string   `json:"password,omitempty"`
+}
+
+type apiRole struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Privileges  []string `json:"privileges"`
+	Roles       []string `json:"roles"`
+}
+
+// SecurityOperations describes the public operations in the security domain (users and roles) for the Nexus instance
+type SecurityOperations interface {
+	EnsureUser(user v1alpha1.NexusUserSpec, password string) (created bool, err error)
+	RemoveUser(userID string) error
+	EnsureRole(role v1alpha1.NexusRoleSpec) (created bool, err error)
+	RemoveRole(roleID string) error
+}
+
+type securityOperation struct {
+	server
+}
+
+func securityOperations(server *server) SecurityOperations {
+	return &securityOperation{server: *server}
+}
+
+// EnsureUser makes sure that the given user exists in the Nexus server as declared, reverting any change made by hand.
+// Users from sources other than the default one must already exist in the server, only their roles are managed.
+func (s *securityOperation) EnsureUser(user v1alpha1.NexusUserSpec, password string) (bool, error) {
+	source := stringOrDefault(user.Source, v1alpha1.DefaultUserSource)
+	current, err := s.fetchUser(user.UserID, source)
+	if err != nil {
+		return false, err
+	}
+	desired := newAPIUser(user, source)
+
+	if current == nil {
+		if source != v1alpha1.DefaultUserSource {
+			return false, fmt.Errorf("user %s not found in source %s, only users from the %s source can be created", user.UserID, source, v1alpha1.DefaultUserSource)
+		}
+		if len(password) == 0 {
+			return false, fmt.Errorf("a password is required to create user %s", user.UserID)
+		}
+		log.Debug("Trying to create user", "User", user.UserID)
+		desired.Password = password
+		if err := s.restcli.post(usersRESTPath, desired); err != nil {
+			return false, err
+		}
+		log.Info("User created", "User", user.UserID)
+		return true, nil
+	}
+
+	if source != v1alpha1.DefaultUserSource {
+		// these attributes are owned by the external source
+		desired.FirstName, desired.LastName, desired.EmailAddress, desired.Status = current.FirstName, current.LastName, current.EmailAddress, current.Status
+	}
+	desired.ReadOnly, desired.ExternalRoles = current.ReadOnly, current.ExternalRoles
+	if !usersEqual(current, &desired) {
+		log.Debug("User differs from the desired state, trying to update it", "User", user.UserID)
+		if err := s.restcli.put(fmt.Sprintf("%s/%s", usersRESTPath, url.PathEscape(user.UserID)), desired); err != nil {
+			return false, err
+		}
+		log.Info("User updated", "User", user.UserID)
+	}
+
+	// disabled users can't authenticate, their password would be changed on every reconciliation
+	if source == v1alpha1.DefaultUserSource && len(password) > 0 && !desired.ReadOnly && desired.Status != userStatusDisabled {
+		if authenticated, err := s.restcli.authenticates(user.UserID, password); err != nil {
+			return false, err
+		} else if !authenticated {
+			log.Debug("User password differs from the desired one, trying to change it", "User", user.UserID)
+			if err := s.restcli.putText(fmt.Sprintf("%s/%s/change-password", usersRESTPath, url.PathEscape(user.UserID)), password); err != nil {
+				return false, err
+			}
+			log.Info("User password changed", "User", user.UserID)
+		}
+	}
+	return false, nil
+}
+
+// RemoveUser removes the given user from the Nexus server, if it exists
+func (s *securityOperation) RemoveUser(userID string) error {
+	log.Debug("Trying to remove user", "User", userID)
+	if err := s.restcli.delete(fmt.Sprintf("%s/%s", usersRESTPath, url.PathEscape(userID))); err != nil && !isRESTNotFound(err) {
+		return err
+	}
+	log.Info("User removed", "User", userID)
+	return nil
+}
+
+// EnsureRole makes sure that the given role exists in the Nexus server as declared, reverting any change made by hand
+func (s *securityOperation) EnsureRole(role v1alpha1.NexusRoleSpec) (bool, error) {
+	desired := newAPIRole(role)
+	path := fmt.Sprintf("%s/%s", rolesRESTPath, url.PathEscape(role.RoleID))
+	current := &apiRole{}
+	if err := s.restcli.get(path, current); err != nil {
+		if !isRESTNotFound(err) {
+			return false, err
+		}
+		log.Debug("Trying to create role", "Role", role.RoleID)
+		if err := s.restcli.post(rolesRESTPath, desired); err != nil {
+			return false, err
+		}
+		log.Info("Role created", "Role", role.RoleID)
+		return true, nil
+	}
+
+	if !rolesEqual(current, &desired) {
+		log.Debug("Role differs from the desired state, trying to update it", "Role", role.RoleID)
+		if err := s.restcli.put(path, desired); err != nil {
+			return false, err
+		}
+		log.Info("Role updated", "Role", role.RoleID)
+	}
+	return false, nil
+}
+
+// RemoveRole removes the given role from the Nexus server, if it exists
+func (s *securityOperation) RemoveRole(roleID string) error {
+	log.Debug("Trying to remove role", "Role", roleID)
+	if err := s.restcli.delete(fmt.Sprintf("%s/%s", rolesRESTPath, url.PathEscape(roleID))); err != nil && !isRESTNotFound(err) {
+		return err
+	}
+	log.Info("Role removed", "Role", roleID)
+	return nil
+}
+
+func (s *server) fetchUser(userID, source string) (*apiUser, error) {
+	var users []apiUser
+	query := url.Values{"userId": {userID}, "source": {source}}
+	if err := s.restcli.get(fmt.Sprintf("%s?%s", usersRESTPath, query.Encode()), &users); err != nil {
+		return nil, err
+	}
+	// the server searches for users whose ID starts with the given one
+	for i := range users {
+		if users[i].UserID == userID {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// authenticates verifies if the given credentials are accepted by the Nexus server.
+// A user lacking privileges to read users is still authenticated, the server would reply with 403 instead of 401.
+func (c *restClient) authenticates(user, pass string) (bool, error) {
+	userClient := &restClient{httpClient: c.httpClient, baseURL: c.baseURL, username: user, password: pass}
+	err := userClient.get(fmt.Sprintf("%s?%s", usersRESTPath, url.Values{"userId": {user}}.Encode()), &[]apiUser{})
+	if err == nil {
+		return true, nil
+	}
+	if restErr, ok := err.(*restError); ok {
+		switch restErr.statusCode {
+		case http.StatusUnauthorized:
+			return false, nil
+		case http.StatusForbidden:
+			return true, nil
+		}
+	}
+	return false, err
+}
+
+func newAPIUser(user v1alpha1.NexusUserSpec, source string) apiUser {
+	status := userStatusActive
+	if user.Disabled {
+		status = userStatusDisabled
+	}
+	roles := user.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	return apiUser{
+		UserID:       user.UserID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		EmailAddress: user.Email,
+		Source:       source,
+		Status:       status,
+		Roles:        roles,
+	}
+}
+
+func newAPIRole(role v1alpha1.NexusRoleSpec) apiRole {
+	privileges, roles := role.Privileges, role.Roles
+	if privileges == nil {
+		privileges = []string{}
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	return apiRole{
+		ID:          role.RoleID,
+		Name:        stringOrDefault(role.Name, role.RoleID),
+		Description: role.Description,
+		Privileges:  privileges,
+		Roles:       roles,
+	}
+}
+
+func usersEqual(current, desired *apiUser) bool {
+	return current.FirstName == desired.FirstName &&
+		current.LastName == desired.LastName &&
+		current.EmailAddress == desired.EmailAddress &&
+		current.Status == desired.Status &&
+		equalSets(current.Roles, desired.Roles)
+}
+
+func rolesEqual(current, desired *apiRole) bool {
+	return current.Name == desired.Name &&
+		current.Description == desired.Description &&
+		equalSets(current.Privileges, desired.Privileges) &&
+		equalSets(current.Roles, desired.Roles)
+}
+
+// equalSets verifies if both slices hold the same elements regardless of their order
+func equalSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA, sortedB := append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+func newTestUser() v1alpha1.NexusUserSpec {
+	return v1alpha1.NexusUserSpec{
+		UserID:    "jdoe",
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "jdoe@example.com",
+		Roles:     []string{"nx-anonymous", "deployer"},
+	}
+}
+
+func Test_securityOperation_EnsureUser(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	user := newTestUser()
+
+	created, err := securityOperations(server).EnsureUser(user, "secret")
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "active", fake.users["jdoe"].Status)
+	assert.Equal(t, "default", fake.users["jdoe"].Source)
+	assert.Equal(t, "secret", fake.passwords["jdoe"])
+
+	// nothing changed, nothing to do
+	fake.requests = nil
+	created, err = securityOperations(server).EnsureUser(user, "secret")
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.False(t, fake.requested("PUT /security/users/jdoe"))
+	assert.False(t, fake.requested("PUT /security/users/jdoe/change-password"))
+}
+
+func Test_securityOperation_EnsureUserRevertDrift(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	user := newTestUser()
+	_, err := securityOperations(server).EnsureUser(user, "secret")
+	assert.NoError(t, err)
+
+	// changed by hand in the UI
+	drifted := fake.users["jdoe"]
+	drifted.Roles = []string{"nx-admin"}
+	drifted.Status = "disabled"
+	fake.users["jdoe"] = drifted
+	fake.passwords["jdoe"] = "changed"
+
+	created, err := securityOperations(server).EnsureUser(user, "secret")
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.ElementsMatch(t, user.Roles, fake.users["jdoe"].Roles)
+	assert.Equal(t, "active", fake.users["jdoe"].Status)
+	assert.Equal(t, "secret", fake.passwords["jdoe"])
+}
+
+func Test_securityOperation_EnsureUserDisabled(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	user := newTestUser()
+	user.Disabled = true
+
+	created, err := securityOperations(server).EnsureUser(user, "secret")
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "disabled", fake.users["jdoe"].Status)
+
+	// disabled users can't authenticate, the password isn't changed on every reconciliation
+	fake.requests = nil
+	created, err = securityOperations(server).EnsureUser(user, "secret")
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.False(t, fake.requested("PUT /security/users/jdoe"))
+	assert.False(t, fake.requested("PUT /security/users/jdoe/change-password"))
+}
+
+func Test_securityOperation_EnsureUserExternalSource(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	user := v1alpha1.NexusUserSpec{UserID: "ldap-user", Source: "LDAP", Roles: []string{"deployer"}}
+
+	_, err := securityOperations(server).EnsureUser(user, "")
+	assert.Error(t, err)
+	assert.Empty(t, fake.users)
+
+	fake.users["ldap-user"] = apiUser{UserID: "ldap-user", FirstName: "Ldap", EmailAddress: "ldap@example.com", Source: "LDAP", Status: "active", Roles: []string{}}
+	created, err := securityOperations(server).EnsureUser(user, "")
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, []string{"deployer"}, fake.users["ldap-user"].Roles)
+	// owned by the LDAP server
+	assert.Equal(t, "Ldap", fake.users["ldap-user"].FirstName)
+}
+
+func Test_securityOperation_EnsureUserNoPassword(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	_, err := securityOperations(server).EnsureUser(newTestUser(), "")
+	assert.Error(t, err)
+	assert.Empty(t, fake.users)
+}
+
+func Test_securityOperation_RemoveUser(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	_, err := securityOperations(server).EnsureUser(newTestUser(), "secret")
+	assert.NoError(t, err)
+
+	assert.NoError(t, securityOperations(server).RemoveUser("jdoe"))
+	assert.Empty(t, fake.users)
+	assert.NoError(t, securityOperations(server).RemoveUser("jdoe"))
+}
+
+func Test_securityOperation_EnsureRole(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	role := v1alpha1.NexusRoleSpec{RoleID: "deployer", Privileges: []string{"nx-repository-view-*-*-add", "nx-repository-view-*-*-edit"}}
+
+	created, err := securityOperations(server).EnsureRole(role)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	// name defaults to the ID
+	assert.Equal(t, "deployer", fake.roles["deployer"].Name)
+	assert.Empty(t, fake.roles["deployer"].Roles)
+
+	// same privileges in a different order are not a change
+	drifted := fake.roles["deployer"]
+	drifted.Privileges = []string{"nx-repository-view-*-*-edit", "nx-repository-view-*-*-add"}
+	fake.roles["deployer"] = drifted
+	fake.requests = nil
+	created, err = securityOperations(server).EnsureRole(role)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.False(t, fake.requested("PUT /security/roles/deployer"))
+
+	drifted.Privileges = []string{"nx-all"}
+	fake.roles["deployer"] = drifted
+	_, err = securityOperations(server).EnsureRole(role)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, role.Privileges, fake.roles["deployer"].Privileges)
+
+	assert.NoError(t, securityOperations(server).RemoveRole("deployer"))
+	assert.Empty(t, fake.roles)
+}
@@ -0,0 +1,243 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+const (
+	tasksRESTPath = "/tasks"
+
+	cronTaskSchedule   = "cron"
+	manualTaskSchedule = "manual"
+)
+
+// apiTaskRef is the representation of a task returned when listing all tasks in the server
+type apiTaskRef struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Type          string     `json:"type"`
+	Message       string     `json:"message,omitempty"`
+	CurrentState  string     `json:"currentState,omitempty"`
+	LastRunResult string     `json:"lastRunResult,omitempty"`
+	NextRun       *time.Time `json:"nextRun,omitempty"`
+	LastRun       *time.Time `json:"lastRun,omitempty"`
+}
+
+// apiTaskPage is a page of tasks returned by the Nexus REST API
+type apiTaskPage struct {
+	Items             []apiTaskRef `json:"items"`
+	ContinuationToken string       `json:"continuationToken,omitempty"`
+}
+
+// apiTask is the representation of a task sent to the Nexus REST API when creating or updating it
+type apiTask struct {
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	Enabled    bool              `json:"enabled"`
+	Frequency  apiTaskFrequency  `json:"frequency"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type apiTaskFrequency struct {
+	Schedule       string `json:"schedule"`
+	CronExpression string `json:"cronExpression,omitempty"`
+}
+
+// TaskOperations describes the public operations in the scheduled task domain for the Nexus instance
+type TaskOperations interface {
+	// EnsureTasks converges the tasks declared in `spec.tasks` with the ones in the Nexus server.
+	// Tasks created by the Operator are removed from the server once they're removed from the spec.
+	EnsureTasks() error
+}
+
+type taskOperation struct {
+	server
+}
+
+func taskOperations(server *server) TaskOperations {
+	return &taskOperation{server: *server}
+}
+
+func (t *taskOperation) EnsureTasks() error {
+	previous := t.status.Tasks
+	if len(t.nexus.Spec.Tasks) == 0 && len(previous) == 0 {
+		log.Debug("No tasks declared in 'spec.tasks', skipping")
+		return nil
+	}
+
+	existing, err := t.fetchTasks()
+	if err != nil {
+		return err
+	}
+	previousByName := make(map[string]v1alpha1.TaskStatus, len(previous))
+	for _, task := range previous {
+		previousByName[task.Name] = task
+	}
+
+	var statuses []v1alpha1.TaskStatus
+	var errs []string
+	declared := make(map[string]bool, len(t.nexus.Spec.Tasks))
+	for _, task := range t.nexus.Spec.Tasks {
+		declared[task.Name] = true
+		status, err := t.ensureTask(task, existing, previousByName[task.Name])
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, task := range previous {
+		if declared[task.Name] {
+			continue
+		}
+		if !task.Created {
+			log.Debug("Task removed from the spec wasn't created by the Operator, won't remove it from the server", "Task", task.Name)
+			continue
+		}
+		current, found := existing[task.Name]
+		if !found {
+			log.Debug("Task removed from the spec no longer exists in the server", "Task", task.Name)
+			continue
+		}
+		log.Debug("Trying to remove task", "Task", task.Name)
+		if err := t.restcli.delete(fmt.Sprintf("%s/%s", tasksRESTPath, current.ID)); err != nil && !isRESTNotFound(err) {
+			// we keep it in the status to try again in the next reconciliation
+			task.Ready = false
+			task.Reason = fmt.Sprintf("Failed to remove task from the server: %v", err)
+			statuses = append(statuses, task)
+			errs = append(errs, err.Error())
+			continue
+		}
+		log.Info("Task removed", "Task", task.Name)
+	}
+
+	t.status.Tasks = statuses
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to ensure tasks: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// fetchTasks reads every task in the server, going through all pages
+func (t *taskOperation) fetchTasks() (map[string]apiTaskRef, error) {
+	log.Debug("Attempt to fetch all tasks from the server")
+	existing := map[string]apiTaskRef{}
+	path := tasksRESTPath
+	for {
+		page := apiTaskPage{}
+		if err := t.restcli.get(path, &page); err != nil {
+			return nil, err
+		}
+		for _, task := range page.Items {
+			existing[task.Name] = task
+		}
+		if len(page.ContinuationToken) == 0 {
+			return existing, nil
+		}
+		path = fmt.Sprintf("%s?continuationToken=%s", tasksRESTPath, url.QueryEscape(page.ContinuationToken))
+	}
+}
+
+func (t *taskOperation) ensureTask(task v1alpha1.Task, existing map[string]apiTaskRef, previous v1alpha1.TaskStatus) (v1alpha1.TaskStatus, error) {
+	status := v1alpha1.TaskStatus{Name: task.Name, Type: task.Type, Created: previous.Created, ConfigurationHash: previous.ConfigurationHash}
+	desired := newAPITask(task)
+	hash, err := hashTask(desired)
+	if err != nil {
+		status.Reason = err.Error()
+		return status, err
+	}
+
+	current, found := existing[task.Name]
+	if !found {
+		log.Debug("Trying to create task", "Task", task.Name)
+		if err := t.restcli.post(tasksRESTPath, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		status.Created = true
+		status.ConfigurationHash = hash
+		log.Info("Task created", "Task", task.Name)
+		// the server assigns the ID, so we need to read the task back
+		if existing, err = t.fetchTasks(); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		current = existing[task.Name]
+	} else if current.Type != task.Type {
+		status.Reason = fmt.Sprintf("A task named %s already exists in the server with type %s", task.Name, current.Type)
+		log.Warn(status.Reason)
+		return status, nil
+	} else if hash != previous.ConfigurationHash {
+		// the server doesn't return the task configuration, so we can only tell that the spec changed since the last update
+		log.Debug("Task differs from the last state sent to the server, trying to update it", "Task", task.Name)
+		if err := t.restcli.put(tasksRESTPath+"/"+current.ID, desired); err != nil {
+			status.Reason = err.Error()
+			return status, err
+		}
+		status.ConfigurationHash = hash
+		log.Info("Task updated", "Task", task.Name)
+	}
+
+	status.ID = current.ID
+	status.CurrentState = current.CurrentState
+	status.LastRunResult = current.LastRunResult
+	status.LastRun = toMetaTime(current.LastRun)
+	status.NextRun = toMetaTime(current.NextRun)
+	status.Ready = true
+	return status, nil
+}
+
+// newAPITask converts the given task into its Nexus REST API representation with defaults set
+func newAPITask(task v1alpha1.Task) apiTask {
+	apiTask := apiTask{
+		Type:       task.Type,
+		Name:       task.Name,
+		Enabled:    boolOrDefault(task.Enabled, true),
+		Frequency:  apiTaskFrequency{Schedule: manualTaskSchedule},
+		Properties: task.Properties,
+	}
+	if len(task.Cron) > 0 {
+		apiTask.Frequency = apiTaskFrequency{Schedule: cronTaskSchedule, CronExpression: task.Cron}
+	}
+	return apiTask
+}
+
+// hashTask calculates the hash of the given task configuration. The properties map is marshalled with sorted keys.
+func hashTask(task apiTask) (string, error) {
+	raw, err := json.Marshal(task)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", md5.Sum(raw)), nil
+}
+
+func toMetaTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	metaTime := metav1.NewTime(*t)
+	return &metaTime
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/m88i/nexus-operator/api/v1alpha1"
+)
+
+func Test_taskOperation_EnsureTasksNoTasks(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+	assert.Empty(t, fake.requests)
+}
+
+func Test_taskOperation_EnsureTasks(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	// enough tasks to go through more than one page
+	fake.tasks["task-a"] = map[string]interface{}{"name": "existing-a", "type": "db.backup"}
+	fake.tasks["task-b"] = map[string]interface{}{"name": "existing-b", "type": "db.backup"}
+	server.nexus.Spec.Tasks = []v1alpha1.Task{
+		{Name: "compact", Type: "blobstore.compact", Cron: "0 0 1 * * ?", Properties: map[string]string{"blobstoreName": "default"}},
+		{Name: "cleanup", Type: "repository.cleanup"},
+	}
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+
+	id, compact := fake.taskByName("compact")
+	assert.Equal(t, map[string]interface{}{
+		"type": "blobstore.compact", "name": "compact", "enabled": true,
+		"frequency":  map[string]interface{}{"schedule": "cron", "cronExpression": "0 0 1 * * ?"},
+		"properties": map[string]interface{}{"blobstoreName": "default"},
+	}, compact)
+	_, cleanup := fake.taskByName("cleanup")
+	assert.Equal(t, map[string]interface{}{"schedule": "manual"}, cleanup["frequency"])
+
+	assert.Len(t, server.status.Tasks, 2)
+	assert.Equal(t, id, server.status.Tasks[0].ID)
+	for _, status := range server.status.Tasks {
+		assert.True(t, status.Ready, status.Reason)
+		assert.True(t, status.Created)
+		assert.NotEmpty(t, status.ID)
+		assert.NotEmpty(t, status.ConfigurationHash)
+		assert.Equal(t, "WAITING", status.CurrentState)
+	}
+
+	// the last run is reported
+	lastRun := time.Date(2020, 10, 2, 1, 0, 0, 0, time.UTC)
+	fake.tasks[id]["lastRun"], fake.tasks[id]["lastRunResult"] = lastRun.Format(time.RFC3339), "OK"
+	fake.requests = nil
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+	for _, request := range fake.requests {
+		assert.NotEqual(t, http.MethodPut, request[:3], request)
+	}
+	assert.Equal(t, "OK", server.status.Tasks[0].LastRunResult)
+	assert.True(t, lastRun.Equal(server.status.Tasks[0].LastRun.Time))
+
+	// schedule changed
+	server.nexus.Spec.Tasks[0].Cron = "0 0 2 * * ?"
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+	assert.True(t, fake.requested("PUT /tasks/"+id))
+	assert.Equal(t, "0 0 2 * * ?", fake.tasks[id]["frequency"].(map[string]interface{})["cronExpression"])
+}
+
+func Test_taskOperation_EnsureTasksExisting(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	fake.tasks["task-a"] = map[string]interface{}{"name": "compact", "type": "blobstore.compact"}
+	fake.tasks["task-b"] = map[string]interface{}{"name": "backup", "type": "db.backup"}
+	server.nexus.Spec.Tasks = []v1alpha1.Task{
+		{Name: "compact", Type: "blobstore.compact", Cron: "0 0 1 * * ?"},
+		{Name: "backup", Type: "blobstore.compact"},
+	}
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+
+	// updated once to match the spec
+	assert.True(t, fake.requested("PUT /tasks/task-a"))
+	assert.True(t, server.status.Tasks[0].Ready)
+	assert.False(t, server.status.Tasks[0].Created)
+	// a different type can't be changed
+	assert.False(t, server.status.Tasks[1].Ready)
+	assert.Contains(t, server.status.Tasks[1].Reason, "already exists")
+	assert.False(t, fake.requested("PUT /tasks/task-b"))
+
+	// tasks not created by the Operator are never removed
+	server.nexus.Spec.Tasks = nil
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+	assert.Len(t, fake.tasks, 2)
+	assert.Empty(t, server.status.Tasks)
+}
+
+func Test_taskOperation_EnsureTasksRemove(t *testing.T) {
+	server, fake := createNewServerWithFakeNexus(t)
+	server.nexus.Spec.Tasks = []v1alpha1.Task{{Name: "compact", Type: "blobstore.compact"}}
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+	id, _ := fake.taskByName("compact")
+
+	server.nexus.Spec.Tasks = nil
+	fake.failures["DELETE /tasks/"+id] = http.StatusInternalServerError
+	assert.Error(t, taskOperations(server).EnsureTasks())
+	assert.Len(t, server.status.Tasks, 1)
+	assert.False(t, server.status.Tasks[0].Ready)
+
+	delete(fake.failures, "DELETE /tasks/"+id)
+	assert.NoError(t, taskOperations(server).EnsureTasks())
+	assert.Empty(t, fake.tasks)
+	assert.Empty(t, server.status.Tasks)
+}
@@ -16,9 +16,10 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
-	"github.com/m88i/aicura/nexus"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/m88i/nexus-operator/pkg/framework"
@@ -59,96 +60,140 @@ func (u *userOperation) EnsureOperatorUser() error {
 		return nil
 	}
 
-	if _, err := u.createOperatorUserIfNotExists(); err != nil {
+	if err := u.createOperatorUserIfNotExists(); err != nil {
 		return err
 	}
 
 	if userID, pass, err := u.getOperatorUserCredentials(); err != nil {
 		return err
 	} else if len(userID) > 0 && len(pass) > 0 {
-		u.nexuscli.SetCredentials(userID, pass)
+		u.setCredentials(userID, pass)
 	}
-	return nil
+	return u.ensurePasswordRotation()
 }
 
-func (u *userOperation) createOperatorUserIfNotExists() (*nexus.User, error) {
-	// TODO: handle access to a custom admin credentials to be used by the operator
-	u.nexuscli.SetCredentials(defaultAdminUsername, defaultAdminPassword)
+// createOperatorUserIfNotExists creates the operator user and stores its credentials in the instance Secret.
+// The user and the Secret are kept consistent: the user is removed if its credentials can't be stored,
+// and its password is reset if it exists in the server while the Secret lacks valid credentials.
+func (u *userOperation) createOperatorUserIfNotExists() error {
+	u.setCredentials(u.admin.username, u.admin.password)
 	log.Debug("Attempt to create operator user. Checking if it already exists.")
-	user, err := u.nexuscli.UserService.GetUserByID(operatorUsername)
+	user, err := u.fetchUser(operatorUsername, defaultSource)
 	if err != nil {
-		if nexus.IsAuthenticationError(err) {
-			log.Debug("Failed to fetch user with admin default credentials, skipping trying to create operator user.")
-			return nil, nil
+		if isRESTAuthenticationError(err) {
+			log.Debug("Failed to fetch user with admin credentials, skipping trying to create operator user.")
+			return nil
 		}
-		return nil, err
+		return err
 	}
 	if user != nil {
 		log.Debug("Operator user already exists")
+		if known, err := u.isOperatorUserPasswordKnown(); err != nil {
+			return err
+		} else if !known {
+			if err := u.resetOperatorUserPassword(); err != nil {
+				return err
+			}
+		}
 		u.status.OperatorUserCreated = true
-		return user, nil
+		return nil
 	}
+
 	user, err = u.createOperatorUserInstance()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	log.Debug("Trying to create operator user")
-	if err := u.nexuscli.UserService.Add(*user); err != nil {
-		return nil, err
+	if err := u.restcli.post(usersRESTPath, user); err != nil {
+		return err
 	}
-	if err := u.storeOperatorUserCredentials(user); err != nil {
-		//  TODO: in case of an error here, we should remove the user from the Nexus database. Edge case: an user could manually add the credentials later to the secret with a manually created user for us.
-		return nil, err
+	if err := u.storeOperatorUserCredentials(user.UserID, user.Password); err != nil {
+		log.Debug("Failed to store operator user credentials, removing the user from the server")
+		if removeErr := u.restcli.delete(fmt.Sprintf("%s/%s", usersRESTPath, operatorUsername)); removeErr != nil && !isRESTNotFound(removeErr) {
+			// the password will be reset in the next reconciliation, since the Secret lacks the credentials
+			log.Error(removeErr, "Failed to remove operator user after failing to store its credentials")
+		}
+		return err
 	}
 	log.Debug("Operator user successfully created!")
 	u.status.OperatorUserCreated = true
-	return user, nil
+	return nil
 }
 
-func (u *userOperation) storeOperatorUserCredentials(user *nexus.User) error {
+// isOperatorUserPasswordKnown verifies if the instance Secret holds the operator user password accepted by the server.
+// An interrupted password rotation is left to be recovered by the rotation itself.
+func (u *userOperation) isOperatorUserPasswordKnown() (bool, error) {
 	secret := &corev1.Secret{}
-	log.Debug("Attempt to store operator user credentials into Secret")
 	if err := framework.Fetch(u.k8sclient, framework.Key(u.nexus), secret, kind.SecretKind); err != nil {
+		return false, err
+	}
+	password := string(secret.Data[SecretKeyPassword])
+	if len(password) == 0 {
+		return false, nil
+	}
+	if len(secret.Data[SecretKeyPendingPassword]) > 0 {
+		return true, nil
+	}
+	return u.restcli.authenticates(operatorUsername, password)
+}
+
+// resetOperatorUserPassword sets a new password to the operator user and stores it in the instance Secret.
+// If storing the password fails, it's reset again in the next reconciliation.
+func (u *userOperation) resetOperatorUserPassword() error {
+	log.Info("The operator user password is unknown, resetting it")
+	password, err := GenerateRandomPassword()
+	if err != nil {
 		return err
 	}
-	if secret.StringData == nil {
-		secret.StringData = make(map[string]string)
+	if err := u.restcli.putText(fmt.Sprintf("%s/%s/change-password", usersRESTPath, operatorUsername), password); err != nil {
+		return fmt.Errorf("failed to reset the operator user password: %v", err)
+	}
+	return u.storeOperatorUserCredentials(operatorUsername, password)
+}
+
+func (u *userOperation) storeOperatorUserCredentials(userID, password string) error {
+	secret := &corev1.Secret{}
+	log.Debug("Attempt to store operator user credentials into Secret")
+	if err := framework.Fetch(u.k8sclient, framework.Key(u.nexus), secret, kind.SecretKind); err != nil {
+		return err
 	}
-	secret.StringData[SecretKeyPassword] = user.Password
-	secret.StringData[SecretKeyUsername] = user.UserID
+	setSecretData(secret, SecretKeyPassword, password)
+	setSecretData(secret, SecretKeyUsername, userID)
+	setPasswordRotatedAt(secret, time.Now())
 	log.Debug("Updating Secret with user credentials")
 	return u.k8sclient.Update(context.TODO(), secret)
 }
 
-func (u *userOperation) getOperatorUserCredentials() (user, password string, err error) {
+func (s *server) getOperatorUserCredentials() (user, password string, err error) {
 	secret := &corev1.Secret{}
-	if err := framework.Fetch(u.k8sclient, framework.Key(u.nexus), secret, kind.SecretKind); err != nil {
+	if err := framework.Fetch(s.k8sclient, framework.Key(s.nexus), secret, kind.SecretKind); err != nil {
 		return "", "", err
 	}
 	return string(secret.Data[SecretKeyUsername]), string(secret.Data[SecretKeyPassword]), nil
 }
 
-func (u *userOperation) createOperatorUserInstance() (*nexus.User, error) {
-	password, err := u.generateRandomPassword()
+func (u *userOperation) createOperatorUserInstance() (*apiUser, error) {
+	password, err := GenerateRandomPassword()
 	if err != nil {
 		return nil, err
 	}
-	return &nexus.User{
-		Email:     operatorEmail,
-		Roles:     []string{adminRole},
-		FirstName: operatorName,
-		LastName:  operatorLastName,
-		Password:  password,
-		Source:    defaultSource,
-		Status:    operatorStatus,
-		UserID:    operatorUsername,
+	return &apiUser{
+		EmailAddress: operatorEmail,
+		Roles:        []string{adminRole},
+		FirstName:    operatorName,
+		LastName:     operatorLastName,
+		Password:     password,
+		Source:       defaultSource,
+		Status:       operatorStatus,
+		UserID:       operatorUsername,
 	}, nil
 }
 
-func (u *userOperation) generateRandomPassword() (string, error) {
+// GenerateRandomPassword generates a random password for the users managed by the Operator
+func GenerateRandomPassword() (string, error) {
 	uid, err := uuid.NewRandom()
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 	return uid.String(), nil
 }
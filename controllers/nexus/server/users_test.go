@@ -15,39 +15,125 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// failingUpdateClient fails to update any object
+type failingUpdateClient struct {
+	client.Client
+}
+
+func (c *failingUpdateClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	return fmt.Errorf("mock update failure")
+}
+
+// createNewServerWithSecret creates a new server pointing to a fake Nexus server, with the instance Secret holding the given data
+func createNewServerWithSecret(t *testing.T, data map[string][]byte) (*server, *fakeNexusServer, client.Client) {
+	server, cli := createNewServerAndKubeCli(t, &corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}, Data: data})
+	fake := newFakeNexusServer(t)
+	server.restcli = fake.client()
+	return server, fake, cli
+}
+
 func Test_userOperation_EnsureOperatorUser(t *testing.T) {
-	server, _ := createNewServerAndKubeCli(t, &corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()}})
-
-	err := userOperations(server).EnsureOperatorUser()
-	assert.NoError(t, err)
-	user, err := server.nexuscli.UserService.GetUserByID(operatorUsername)
-	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, operatorUsername, user.UserID)
+	server, fake, cli := createNewServerWithSecret(t, nil)
+
+	assert.NoError(t, userOperations(server).EnsureOperatorUser())
+	assert.Contains(t, fake.users, operatorUsername)
+	assert.Equal(t, []string{adminRole}, fake.users[operatorUsername].Roles)
 	assert.True(t, server.status.OperatorUserCreated)
+	secret := fetchInstanceSecret(t, cli, server.nexus)
+	assert.Equal(t, operatorUsername, secret.StringData[SecretKeyUsername])
+	assert.Equal(t, fake.passwords[operatorUsername], secret.StringData[SecretKeyPassword])
 }
 
 func Test_userOperation_EnsureOperatorUser_AlreadyExists(t *testing.T) {
-	server, _ := createNewServerAndKubeCli(t,
-		&corev1.Secret{
-			ObjectMeta: v1.ObjectMeta{Name: "nexus3", Namespace: t.Name()},
-			Data: map[string][]byte{
-				SecretKeyPassword: []byte("12345"),
-				SecretKeyUsername: []byte(operatorUsername),
-			}})
-
-	err := userOperations(server).EnsureOperatorUser()
-	assert.NoError(t, err)
-	user, err := server.nexuscli.UserService.GetUserByID(operatorUsername)
-	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, operatorUsername, user.UserID)
+	server, fake, _ := createNewServerWithSecret(t, map[string][]byte{
+		SecretKeyPassword: []byte("12345"),
+		SecretKeyUsername: []byte(operatorUsername),
+	})
+	fake.users[operatorUsername] = apiUser{UserID: operatorUsername, Roles: []string{adminRole}}
+	fake.passwords[operatorUsername] = "12345"
+
+	assert.NoError(t, userOperations(server).EnsureOperatorUser())
+	assert.Equal(t, "12345", fake.passwords[operatorUsername])
+	assert.False(t, fake.requested("PUT /security/users/nexus-operator/change-password"))
+	assert.True(t, server.status.OperatorUserCreated)
+	assert.Equal(t, operatorUsername, server.restcli.username)
+	assert.Equal(t, "12345", server.restcli.password)
+}
+
+func Test_userOperation_EnsureOperatorUser_RemovedIfCredentialsNotStored(t *testing.T) {
+	server, fake, cli := createNewServerWithSecret(t, nil)
+	server.k8sclient = &failingUpdateClient{Client: cli}
+
+	assert.Error(t, userOperations(server).EnsureOperatorUser())
+	assert.True(t, fake.requested("POST /security/users"))
+	assert.NotContains(t, fake.users, operatorUsername)
+	assert.False(t, server.status.OperatorUserCreated)
+}
+
+func Test_userOperation_EnsureOperatorUser_ResetIfRemovalFails(t *testing.T) {
+	server, fake, cli := createNewServerWithSecret(t, nil)
+	server.k8sclient = &failingUpdateClient{Client: cli}
+	fake.failures["DELETE /security/users/nexus-operator"] = http.StatusInternalServerError
+
+	// the user is left in the server with a password nobody knows
+	assert.Error(t, userOperations(server).EnsureOperatorUser())
+	assert.Contains(t, fake.users, operatorUsername)
+	lost := fake.passwords[operatorUsername]
+
+	// next reconciliation
+	server.k8sclient = cli
+	assert.NoError(t, userOperations(server).EnsureOperatorUser())
+	assert.True(t, server.status.OperatorUserCreated)
+	assert.NotEqual(t, lost, fake.passwords[operatorUsername])
+	secret := fetchInstanceSecret(t, cli, server.nexus)
+	assert.Equal(t, fake.passwords[operatorUsername], secret.StringData[SecretKeyPassword])
+}
+
+func Test_userOperation_EnsureOperatorUser_ResetIfSecretLacksCredentials(t *testing.T) {
+	server, fake, cli := createNewServerWithSecret(t, nil)
+	fake.users[operatorUsername] = apiUser{UserID: operatorUsername, Roles: []string{adminRole}}
+	fake.passwords[operatorUsername] = "unknown"
+
+	assert.NoError(t, userOperations(server).EnsureOperatorUser())
+	assert.True(t, fake.requested("PUT /security/users/nexus-operator/change-password"))
+	assert.NotEqual(t, "unknown", fake.passwords[operatorUsername])
+	secret := fetchInstanceSecret(t, cli, server.nexus)
+	assert.Equal(t, fake.passwords[operatorUsername], secret.StringData[SecretKeyPassword])
+	assert.Equal(t, operatorUsername, secret.StringData[SecretKeyUsername])
 	assert.True(t, server.status.OperatorUserCreated)
 }
+
+func Test_userOperation_EnsureOperatorUser_ResetIfStoredPasswordIsWrong(t *testing.T) {
+	server, fake, cli := createNewServerWithSecret(t, map[string][]byte{
+		SecretKeyPassword: []byte("stale"),
+		SecretKeyUsername: []byte(operatorUsername),
+	})
+	fake.users[operatorUsername] = apiUser{UserID: operatorUsername, Roles: []string{adminRole}}
+	fake.passwords[operatorUsername] = "unknown"
+
+	assert.NoError(t, userOperations(server).EnsureOperatorUser())
+	secret := fetchInstanceSecret(t, cli, server.nexus)
+	assert.Equal(t, fake.passwords[operatorUsername], secret.StringData[SecretKeyPassword])
+	assert.NotEqual(t, "unknown", fake.passwords[operatorUsername])
+}
+
+func Test_userOperation_EnsureOperatorUser_AdminCredentialsRejected(t *testing.T) {
+	server, fake, _ := createNewServerWithSecret(t, nil)
+	fake.adminPassword = "changed"
+
+	assert.NoError(t, userOperations(server).EnsureOperatorUser())
+	assert.NotContains(t, fake.users, operatorUsername)
+	assert.False(t, server.status.OperatorUserCreated)
+}
@@ -122,8 +122,8 @@ func alreadyUpdating(nexus *v1alpha1.Nexus) bool {
 }
 
 func isNewUpdate(deployed, required *appsv1.Deployment) (updating bool, previousTag, targetTag string) {
-	depImage := deployed.Spec.Template.Spec.Containers[0].Image
-	reqImage := required.Spec.Template.Spec.Containers[0].Image
+	depImage := nexusImage(deployed)
+	reqImage := nexusImage(required)
 	deployedImageParts := strings.Split(depImage, ":")
 	requiredImageParts := strings.Split(reqImage, ":")
 
@@ -143,8 +143,8 @@ func getUpdateTags(nexus *v1alpha1.Nexus) (previousTag, targetTag string, err er
 }
 
 func differentImagesOrMinors(deployed, required *appsv1.Deployment) bool {
-	depImage := deployed.Spec.Template.Spec.Containers[0].Image
-	reqImage := required.Spec.Template.Spec.Containers[0].Image
+	depImage := nexusImage(deployed)
+	reqImage := nexusImage(required)
 	requiredImageParts := strings.Split(reqImage, ":")
 	deployedImageParts := strings.Split(depImage, ":")
 
@@ -170,6 +170,16 @@ func differentImagesOrMinors(deployed, required *appsv1.Deployment) bool {
 	return reqMinor != depMinor
 }
 
+// nexusImage returns the image of the Nexus server container, which isn't necessarily the first one of the pods
+func nexusImage(deployment *appsv1.Deployment) string {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == v1alpha1.NexusContainerName {
+			return container.Image
+		}
+	}
+	return ""
+}
+
 func rollback(nexus *v1alpha1.Nexus, tag string, c client.Client) error {
 	// disable automatic updates
 	nexus.Spec.AutomaticUpdate.Disabled = true
@@ -33,7 +33,7 @@ func TestMonitorUpdate(t *testing.T) {
 		Spec: appsv1.DeploymentSpec{
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{{}},
+					Containers: []corev1.Container{{Name: v1alpha1.NexusContainerName}},
 				},
 			},
 		},
@@ -155,7 +155,7 @@ func Test_isNewUpdate(t *testing.T) {
 		Spec: appsv1.DeploymentSpec{
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{{}},
+					Containers: []corev1.Container{{Name: v1alpha1.NexusContainerName}},
 				},
 			},
 		},
@@ -177,7 +177,7 @@ func Test_differentImagesOrMinors(t *testing.T) {
 		Spec: appsv1.DeploymentSpec{
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{{}},
+					Containers: []corev1.Container{{Name: v1alpha1.NexusContainerName}},
 				},
 			},
 		},
@@ -201,4 +201,17 @@ func Test_differentImagesOrMinors(t *testing.T) {
 	// same minor
 	deployedDep.Spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s:%s", image, "3.25.0")
 	assert.False(t, differentImagesOrMinors(deployedDep, requiredDep))
+
+	// the Nexus container isn't the first one
+	sidecar := corev1.Container{Name: "log-shipper", Image: fmt.Sprintf("%s:%s", "fluent-bit", "1.7.0")}
+	deployedDep.Spec.Template.Spec.Containers = append([]corev1.Container{sidecar}, deployedDep.Spec.Template.Spec.Containers...)
+	assert.False(t, differentImagesOrMinors(deployedDep, requiredDep))
+	requiredDep.Spec.Template.Spec.InitContainers = []corev1.Container{sidecar}
+	requiredDep.Spec.Template.Spec.Containers = append(requiredDep.Spec.Template.Spec.Containers, sidecar)
+	requiredDep.Spec.Template.Spec.Containers[0].Image = fmt.Sprintf("%s:%s", image, "3.25.1")
+	assert.False(t, differentImagesOrMinors(deployedDep, requiredDep))
+	updating, previousTag, targetTag := isNewUpdate(deployedDep, requiredDep)
+	assert.True(t, updating)
+	assert.Equal(t, "3.25.0", previousTag)
+	assert.Equal(t, "3.25.1", targetTag)
 }
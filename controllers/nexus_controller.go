@@ -37,8 +37,11 @@ import (
 
 	appsv1alpha1 "github.com/m88i/nexus-operator/api/v1alpha1"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource"
+	nexusdeployment "github.com/m88i/nexus-operator/controllers/nexus/resource/deployment"
+	nexusnetworking "github.com/m88i/nexus-operator/controllers/nexus/resource/networking"
 	"github.com/m88i/nexus-operator/controllers/nexus/server"
 	"github.com/m88i/nexus-operator/controllers/nexus/update"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
 	"github.com/m88i/nexus-operator/pkg/cluster/discovery"
 	"github.com/m88i/nexus-operator/pkg/cluster/kubernetes"
 	"github.com/m88i/nexus-operator/pkg/cluster/openshift"
@@ -50,6 +53,10 @@ import (
 const (
 	updatePollWaitTimeout = 500 * time.Millisecond
 	updateCancelTimeout   = 30 * time.Second
+
+	// the Operator isn't notified about the server TLS Secret, which it doesn't own, so it checks it periodically
+	serverSecretRetryPeriod  = 30 * time.Second
+	serverSecretResyncPeriod = 10 * time.Minute
 )
 
 // NexusReconciler reconciles a Nexus object
@@ -65,13 +72,16 @@ type NexusReconciler struct {
 // +kubebuilder:rbac:groups=apps.m88i.io,resources=nexus/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=services;persistentvolumeclaims;events;secrets;serviceaccounts,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;delete;get;list;patch;update;watch
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get
 // +kubebuilder:rbac:groups=apps,resources=deployments/finalizers,verbs=update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=create;delete;get;list;patch;update;watch
 
 func (r *NexusReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
@@ -156,6 +166,10 @@ func (r *NexusReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	if err = r.ensureServerUpdates(validatedNexus); err != nil {
 		return result, err
 	}
+	result.RequeueAfter = nextPasswordRotation(validatedNexus)
+	if next := r.nextServerSecretCheck(validatedNexus); next > 0 && (result.RequeueAfter == 0 || next < result.RequeueAfter) {
+		result.RequeueAfter = next
+	}
 
 	// Check if we are performing an update and act upon it if needed
 	err = r.handleUpdate(validatedNexus, requiredRes, deployedRes)
@@ -180,6 +194,14 @@ func (r *NexusReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	} else {
 		b.Owns(&networking.Ingress{})
 	}
+
+	certManager, err := discovery.IsCertManagerAvailable()
+	if err != nil {
+		return err
+	}
+	if certManager {
+		b.Owns(certmanager.NewCertificate())
+	}
 	return b.Complete(r)
 }
 
@@ -196,15 +218,43 @@ func (r *NexusReconciler) handleUpdate(nexus *appsv1alpha1.Nexus, required, depl
 
 func (r *NexusReconciler) ensureServerUpdates(instance *appsv1alpha1.Nexus) error {
 	r.Log.Info("Performing Nexus server operations if needed")
-	status, err := server.HandleServerOperations(instance, r)
+	status, err := server.HandleServerOperations(instance, r.Scheme, r)
+	// the status must be kept even on errors, otherwise we lose track of the repositories managed by the operator
+	instance.Status.ServerOperationsStatus = status
 	if err != nil {
 		return err
 	}
 	r.Log.Info("Server Operations finished", "Status", status)
-	instance.Status.ServerOperationsStatus = status
 	return nil
 }
 
+// nextPasswordRotation calculates how long until the operator user password must be rotated, zero if it's not rotated
+func nextPasswordRotation(nexus *appsv1alpha1.Nexus) time.Duration {
+	rotation := nexus.Spec.ServerOperations.CredentialRotation
+	rotatedAt := nexus.Status.ServerOperationsStatus.OperatorUserPasswordRotatedAt
+	if rotation == nil || rotation.Period.Duration <= 0 || rotatedAt == nil {
+		return 0
+	}
+	if next := time.Until(rotatedAt.Add(rotation.Period.Duration)); next > 0 {
+		return next
+	}
+	// overdue, most likely the rotation failed: try again soon
+	return time.Minute
+}
+
+// nextServerSecretCheck calculates how long until the server TLS Secret must be checked again, zero if the Nexus server doesn't serve HTTPS.
+// The keystore is generated from it, so it's checked soon while missing and then periodically to catch up with its renewals.
+func (r *NexusReconciler) nextServerSecretCheck(nexus *appsv1alpha1.Nexus) time.Duration {
+	if !nexusdeployment.ServerTLSEnabled(nexus) {
+		return 0
+	}
+	key := types.NamespacedName{Namespace: nexus.Namespace, Name: nexus.Spec.Networking.TLS.ServerSecretName}
+	if err := r.Get(context.TODO(), key, &corev1.Secret{}); err != nil {
+		return serverSecretRetryPeriod
+	}
+	return serverSecretResyncPeriod
+}
+
 func (r *NexusReconciler) updateNexus(nexus *appsv1alpha1.Nexus, originalNexus *appsv1alpha1.Nexus, err *error) {
 	r.Log.Info("Updating application status before leaving")
 
@@ -224,6 +274,10 @@ func (r *NexusReconciler) updateNexus(nexus *appsv1alpha1.Nexus, originalNexus *
 		}
 	}
 
+	if certErr := r.getTLSCertificateStatus(nexus); certErr != nil {
+		r.Log.Error(certErr, "Error while fetching the TLS Certificate status")
+	}
+
 	if urlErr := r.getNexusURL(nexus); urlErr != nil {
 		r.Log.Error(urlErr, "Error while fetching Nexus URL status")
 	}
@@ -293,21 +347,62 @@ func (r *NexusReconciler) getNexusDeploymentStatus(nexus *appsv1alpha1.Nexus) er
 	return nil
 }
 
+// getTLSCertificateStatus checks if cert-manager has issued the certificate for the exposed hosts.
+// Until then the Nexus instance is not flagged as ready, since its clients would face an invalid certificate.
+func (r *NexusReconciler) getTLSCertificateStatus(nexus *appsv1alpha1.Nexus) error {
+	nexus.Status.TLSCertificateReady = false
+	if !nexus.Spec.Networking.Expose || nexus.Spec.Networking.TLS.CertManager == nil {
+		return nil
+	}
+	r.Log.Info("Checking TLS Certificate Status")
+	ready, err := certmanager.IsCertificateReady(r, types.NamespacedName{Namespace: nexus.Namespace, Name: nexus.Name})
+	if err != nil {
+		return err
+	}
+	nexus.Status.TLSCertificateReady = ready
+	if !ready && nexus.Status.NexusStatus == appsv1alpha1.NexusStatusOK {
+		nexus.Status.NexusStatus = appsv1alpha1.NexusStatusPending
+		nexus.Status.Reason = "Waiting for cert-manager to issue the TLS certificate"
+	}
+	return nil
+}
+
 func (r *NexusReconciler) getNexusURL(nexus *appsv1alpha1.Nexus) error {
 	if nexus.Spec.Networking.Expose {
-		var err error
-		uri := ""
+		var uris []string
 		if nexus.Spec.Networking.ExposeAs == appsv1alpha1.RouteExposeType {
 			r.Log.Info("Checking Route Status")
-			uri, err = openshift.GetRouteURI(r, types.NamespacedName{Namespace: nexus.Namespace, Name: nexus.Name})
+			for _, name := range nexusnetworking.RouteNames(nexus) {
+				uri, err := openshift.GetRouteURI(r, types.NamespacedName{Namespace: nexus.Namespace, Name: name})
+				if err != nil {
+					return err
+				}
+				if len(uri) > 0 {
+					uris = append(uris, uri)
+				}
+			}
 		} else if nexus.Spec.Networking.ExposeAs == appsv1alpha1.IngressExposeType {
 			r.Log.Info("Checking Ingress Status")
-			uri, err = kubernetes.GetIngressURI(r, types.NamespacedName{Namespace: nexus.Namespace, Name: nexus.Name})
+			var err error
+			uris, err = kubernetes.GetIngressURIs(r, types.NamespacedName{Namespace: nexus.Namespace, Name: nexus.Name}, nexusnetworking.NexusHosts(nexus))
+			if err != nil {
+				return err
+			}
+		} else if nexus.Spec.Networking.ExposeAs == appsv1alpha1.LoadBalancerExposeType {
+			r.Log.Info("Checking Load Balancer Status")
+			uri, err := kubernetes.GetLoadBalancerURI(r, types.NamespacedName{Namespace: nexus.Namespace, Name: nexus.Name}, nexusdeployment.ExposedPort(nexus), nexus.ContextPath())
+			if err != nil {
+				return err
+			}
+			if len(uri) > 0 {
+				uris = append(uris, uri)
+			}
 		}
-		if err != nil {
-			return err
+		nexus.Status.URLs = uris
+		nexus.Status.NexusRoute = ""
+		if len(uris) > 0 {
+			nexus.Status.NexusRoute = uris[0]
 		}
-		nexus.Status.NexusRoute = uri
 	}
 	return nil
 }
@@ -0,0 +1,137 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	appsv1alpha1 "github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/server"
+)
+
+// NexusRepositoryReconciler reconciles a NexusRepository object
+type NexusRepositoryReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexusrepositories,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexusrepositories/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexusrepositories/finalizers,verbs=get;update;patch
+
+func (r *NexusRepositoryReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("nexusrepository", req.NamespacedName)
+	log.Info("Reconciling NexusRepository")
+
+	repository := &appsv1alpha1.NexusRepository{}
+	if err := r.Get(context.TODO(), req.NamespacedName, repository); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	nexus, err := fetchReferencedNexus(r, repository.Namespace, repository.Spec.NexusName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !repository.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(log, repository, nexus)
+	}
+	if !controllerutil.ContainsFinalizer(repository, appsv1alpha1.NexusRepositoryFinalizer) {
+		controllerutil.AddFinalizer(repository, appsv1alpha1.NexusRepositoryFinalizer)
+		if err := r.Update(context.TODO(), repository); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := repository.Status.DeepCopy()
+	repository.Status.ObservedGeneration = repository.Generation
+	var opErr error
+	if nexus != nil {
+		var status appsv1alpha1.RepositoryStatus
+		status, opErr = server.HandleRepository(nexus, repository.Spec.Repository, repository.Status.Created, r)
+		repository.Status.URL = status.URL
+		repository.Status.Created = status.Created
+		if opErr == nil && !status.Ready {
+			// invalid declarations or conflicts with existing repositories are failures as well
+			opErr = fmt.Errorf("%s", status.Reason)
+		}
+	}
+	result, err := handleServerOperationResult(&repository.Status.Conditions, repository.Generation, repository.Spec.NexusName, nexus, opErr, "Repository matches its desired state")
+
+	if !reflect.DeepEqual(original, &repository.Status) {
+		log.Info("Updating NexusRepository status")
+		if statusErr := r.Status().Update(context.TODO(), repository); statusErr != nil {
+			log.Error(statusErr, "Error while updating NexusRepository status")
+			if err == nil {
+				err = statusErr
+			}
+		}
+	}
+	return result, err
+}
+
+// finalize removes the repository from the Nexus server and then releases the CR to be deleted
+func (r *NexusRepositoryReconciler) finalize(log logr.Logger, repository *appsv1alpha1.NexusRepository, nexus *appsv1alpha1.Nexus) error {
+	if !controllerutil.ContainsFinalizer(repository, appsv1alpha1.NexusRepositoryFinalizer) {
+		return nil
+	}
+	if canRemoveFromServer(nexus, repository.Status.Created) {
+		log.Info("Removing repository from the Nexus server", "Repository", repository.Spec.Name)
+		if err := server.RemoveRepository(nexus, repository.Spec.Name, r); err != nil {
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(repository, appsv1alpha1.NexusRepositoryFinalizer)
+	return r.Update(context.TODO(), repository)
+}
+
+// nexusToRepositories maps a Nexus instance to the NexusRepositories that reference it
+func (r *NexusRepositoryReconciler) nexusToRepositories(obj handler.MapObject) []reconcile.Request {
+	repositories := &appsv1alpha1.NexusRepositoryList{}
+	if err := r.List(context.TODO(), repositories, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		r.Log.Error(err, "Error while listing NexusRepositories", "Namespace", obj.Meta.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, repository := range repositories.Items {
+		if repository.Spec.NexusName == obj.Meta.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: repository.Namespace, Name: repository.Name}})
+		}
+	}
+	return requests
+}
+
+func (r *NexusRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.NexusRepository{}).
+		Watches(&source.Kind{Type: &appsv1alpha1.Nexus{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.nexusToRepositories)}).
+		Complete(r)
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	appsv1alpha1 "github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/server"
+)
+
+// NexusRoleReconciler reconciles a NexusRole object
+type NexusRoleReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexusroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexusroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexusroles/finalizers,verbs=get;update;patch
+
+func (r *NexusRoleReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("nexusrole", req.NamespacedName)
+	log.Info("Reconciling NexusRole")
+
+	role := &appsv1alpha1.NexusRole{}
+	if err := r.Get(context.TODO(), req.NamespacedName, role); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	nexus, err := fetchReferencedNexus(r, role.Namespace, role.Spec.NexusName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	spec := role.Spec.DeepCopy()
+	if len(spec.RoleID) == 0 {
+		spec.RoleID = role.Name
+	}
+
+	if !role.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(log, role, spec.RoleID, nexus)
+	}
+	if !controllerutil.ContainsFinalizer(role, appsv1alpha1.NexusRoleFinalizer) {
+		controllerutil.AddFinalizer(role, appsv1alpha1.NexusRoleFinalizer)
+		if err := r.Update(context.TODO(), role); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := role.Status.DeepCopy()
+	role.Status.ObservedGeneration = role.Generation
+	var opErr error
+	if nexus != nil {
+		var created bool
+		created, opErr = server.HandleRole(nexus, *spec, r)
+		role.Status.Created = role.Status.Created || created
+	}
+	result, err := handleServerOperationResult(&role.Status.Conditions, role.Generation, role.Spec.NexusName, nexus, opErr, "Role matches its desired state")
+
+	if !reflect.DeepEqual(original, &role.Status) {
+		log.Info("Updating NexusRole status")
+		if statusErr := r.Status().Update(context.TODO(), role); statusErr != nil {
+			log.Error(statusErr, "Error while updating NexusRole status")
+			if err == nil {
+				err = statusErr
+			}
+		}
+	}
+	return result, err
+}
+
+// finalize removes the role from the Nexus server and then releases the CR to be deleted
+func (r *NexusRoleReconciler) finalize(log logr.Logger, role *appsv1alpha1.NexusRole, roleID string, nexus *appsv1alpha1.Nexus) error {
+	if !controllerutil.ContainsFinalizer(role, appsv1alpha1.NexusRoleFinalizer) {
+		return nil
+	}
+	if canRemoveFromServer(nexus, role.Status.Created) {
+		log.Info("Removing role from the Nexus server", "Role", roleID)
+		if err := server.RemoveRole(nexus, roleID, r); err != nil {
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(role, appsv1alpha1.NexusRoleFinalizer)
+	return r.Update(context.TODO(), role)
+}
+
+// nexusToRoles maps a Nexus instance to the NexusRoles that reference it
+func (r *NexusRoleReconciler) nexusToRoles(obj handler.MapObject) []reconcile.Request {
+	roles := &appsv1alpha1.NexusRoleList{}
+	if err := r.List(context.TODO(), roles, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		r.Log.Error(err, "Error while listing NexusRoles", "Namespace", obj.Meta.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, role := range roles.Items {
+		if role.Spec.NexusName == obj.Meta.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: role.Namespace, Name: role.Name}})
+		}
+	}
+	return requests
+}
+
+func (r *NexusRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.NexusRole{}).
+		Watches(&source.Kind{Type: &appsv1alpha1.Nexus{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.nexusToRoles)}).
+		Complete(r)
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	appsv1alpha1 "github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/server"
+	"github.com/m88i/nexus-operator/pkg/framework"
+	"github.com/m88i/nexus-operator/pkg/framework/kind"
+)
+
+// NexusUserReconciler reconciles a NexusUser object
+type NexusUserReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexususers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexususers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.m88i.io,resources=nexususers/finalizers,verbs=get;update;patch
+
+func (r *NexusUserReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("nexususer", req.NamespacedName)
+	log.Info("Reconciling NexusUser")
+
+	user := &appsv1alpha1.NexusUser{}
+	if err := r.Get(context.TODO(), req.NamespacedName, user); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	nexus, err := fetchReferencedNexus(r, user.Namespace, user.Spec.NexusName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	spec := userSpecWithDefaults(user)
+
+	if !user.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(log, user, spec, nexus)
+	}
+	if !controllerutil.ContainsFinalizer(user, appsv1alpha1.NexusUserFinalizer) {
+		controllerutil.AddFinalizer(user, appsv1alpha1.NexusUserFinalizer)
+		if err := r.Update(context.TODO(), user); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := user.Status.DeepCopy()
+	user.Status.ObservedGeneration = user.Generation
+	var opErr error
+	if nexus != nil {
+		var password string
+		if password, opErr = r.ensurePassword(user); opErr == nil {
+			var created bool
+			created, opErr = server.HandleUser(nexus, spec, password, r)
+			user.Status.Created = user.Status.Created || created
+		}
+	}
+	result, err := handleServerOperationResult(&user.Status.Conditions, user.Generation, user.Spec.NexusName, nexus, opErr, "User matches its desired state")
+
+	if !reflect.DeepEqual(original, &user.Status) {
+		log.Info("Updating NexusUser status")
+		if statusErr := r.Status().Update(context.TODO(), user); statusErr != nil {
+			log.Error(statusErr, "Error while updating NexusUser status")
+			if err == nil {
+				err = statusErr
+			}
+		}
+	}
+	return result, err
+}
+
+// finalize removes the user from the Nexus server and then releases the CR to be deleted
+func (r *NexusUserReconciler) finalize(log logr.Logger, user *appsv1alpha1.NexusUser, spec appsv1alpha1.NexusUserSpec, nexus *appsv1alpha1.Nexus) error {
+	if !controllerutil.ContainsFinalizer(user, appsv1alpha1.NexusUserFinalizer) {
+		return nil
+	}
+	if canRemoveFromServer(nexus, user.Status.Created) {
+		log.Info("Removing user from the Nexus server", "User", spec.UserID)
+		if err := server.RemoveUser(nexus, spec.UserID, r); err != nil {
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(user, appsv1alpha1.NexusUserFinalizer)
+	return r.Update(context.TODO(), user)
+}
+
+// ensurePassword reads the user password from the referenced Secret, generating and storing a new one if required
+func (r *NexusUserReconciler) ensurePassword(user *appsv1alpha1.NexusUser) (string, error) {
+	ref := user.Spec.PasswordSecret
+	if ref == nil {
+		return "", nil
+	}
+	key := ref.Key
+	if len(key) == 0 {
+		key = appsv1alpha1.DefaultUserPasswordSecretKey
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: user.Namespace, Name: ref.Name}
+	if err := framework.Fetch(r, secretKey, secret, kind.SecretKind); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+		if !ref.Generate {
+			return "", fmt.Errorf("password Secret %s not found", ref.Name)
+		}
+		password, err := server.GenerateRandomPassword()
+		if err != nil {
+			return "", err
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: ref.Name, Namespace: user.Namespace},
+			StringData: map[string]string{key: password},
+		}
+		if err := controllerutil.SetControllerReference(user, secret, r.Scheme); err != nil {
+			return "", err
+		}
+		r.Log.Info("Creating Secret with generated password", "Secret", secretKey, "User", user.Name)
+		return password, r.Create(context.TODO(), secret)
+	}
+
+	if password := string(secret.Data[key]); len(password) > 0 {
+		return password, nil
+	}
+	if !ref.Generate {
+		return "", fmt.Errorf("key %s not found in password Secret %s", key, ref.Name)
+	}
+	password, err := server.GenerateRandomPassword()
+	if err != nil {
+		return "", err
+	}
+	if secret.StringData == nil {
+		secret.StringData = make(map[string]string)
+	}
+	secret.StringData[key] = password
+	r.Log.Info("Updating Secret with generated password", "Secret", secretKey, "User", user.Name)
+	return password, r.Update(context.TODO(), secret)
+}
+
+// userSpecWithDefaults returns a copy of the user spec with its defaults set
+func userSpecWithDefaults(user *appsv1alpha1.NexusUser) appsv1alpha1.NexusUserSpec {
+	spec := user.Spec.DeepCopy()
+	if len(spec.UserID) == 0 {
+		spec.UserID = user.Name
+	}
+	if len(spec.Source) == 0 {
+		spec.Source = appsv1alpha1.DefaultUserSource
+	}
+	return *spec
+}
+
+// nexusToUsers maps a Nexus instance to the NexusUsers that reference it
+func (r *NexusUserReconciler) nexusToUsers(obj handler.MapObject) []reconcile.Request {
+	return r.usersMatching(obj.Meta.GetNamespace(), func(user appsv1alpha1.NexusUser) bool {
+		return user.Spec.NexusName == obj.Meta.GetName()
+	})
+}
+
+// secretToUsers maps a Secret to the NexusUsers reading their passwords from it
+func (r *NexusUserReconciler) secretToUsers(obj handler.MapObject) []reconcile.Request {
+	return r.usersMatching(obj.Meta.GetNamespace(), func(user appsv1alpha1.NexusUser) bool {
+		return user.Spec.PasswordSecret != nil && user.Spec.PasswordSecret.Name == obj.Meta.GetName()
+	})
+}
+
+func (r *NexusUserReconciler) usersMatching(namespace string, matches func(user appsv1alpha1.NexusUser) bool) []reconcile.Request {
+	users := &appsv1alpha1.NexusUserList{}
+	if err := r.List(context.TODO(), users, client.InNamespace(namespace)); err != nil {
+		r.Log.Error(err, "Error while listing NexusUsers", "Namespace", namespace)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, user := range users.Items {
+		if matches(user) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}})
+		}
+	}
+	return requests
+}
+
+func (r *NexusUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.NexusUser{}).
+		Watches(&source.Kind{Type: &appsv1alpha1.Nexus{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.nexusToUsers)}).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(r.secretToUsers)}).
+		Complete(r)
+}
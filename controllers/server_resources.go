@@ -0,0 +1,95 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/controllers/nexus/server"
+)
+
+// Common bits shared by the controllers of resources managed inside a Nexus server (repositories, users, roles)
+
+const (
+	// how long to wait before trying again when the Nexus server can't handle the resource yet
+	serverResourceRequeueAfter = 30 * time.Second
+	// how often the resources are verified against the Nexus server, reverting changes made by hand
+	serverResourceResyncPeriod = 5 * time.Minute
+
+	conditionReady = "Ready"
+
+	reasonSynchronized        = "Synchronized"
+	reasonFailed              = "Failed"
+	reasonNexusNotFound       = "NexusNotFound"
+	reasonNexusServerNotReady = "NexusServerNotReady"
+)
+
+// fetchReferencedNexus fetches the Nexus instance referenced by a resource managed inside its server.
+// Returns nil if the instance doesn't exist.
+func fetchReferencedNexus(c client.Client, namespace, name string) (*appsv1alpha1.Nexus, error) {
+	nexus := &appsv1alpha1.Nexus{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, nexus); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return nexus, nil
+}
+
+// canRemoveFromServer verifies if a resource created by the operator must be removed from the Nexus server while being finalized.
+// If the Nexus instance is gone (or going), so is the resource.
+func canRemoveFromServer(nexus *appsv1alpha1.Nexus, created bool) bool {
+	return nexus != nil && created && nexus.DeletionTimestamp.IsZero()
+}
+
+// setReadyCondition sets the Ready condition in the given conditions
+func setReadyCondition(conditions *[]v1.Condition, generation int64, status v1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, v1.Condition{
+		Type:               conditionReady,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// handleServerOperationResult sets the Ready condition according to the result of an operation in the Nexus server
+// and returns what the reconciliation must return.
+func handleServerOperationResult(conditions *[]v1.Condition, generation int64, nexusName string, nexus *appsv1alpha1.Nexus, opErr error, successMessage string) (ctrl.Result, error) {
+	switch {
+	case nexus == nil:
+		setReadyCondition(conditions, generation, v1.ConditionFalse, reasonNexusNotFound, fmt.Sprintf("Nexus instance %s not found", nexusName))
+		return ctrl.Result{RequeueAfter: serverResourceRequeueAfter}, nil
+	case opErr == server.ErrServerNotReady:
+		setReadyCondition(conditions, generation, v1.ConditionFalse, reasonNexusServerNotReady, opErr.Error())
+		return ctrl.Result{RequeueAfter: serverResourceRequeueAfter}, nil
+	case opErr != nil:
+		setReadyCondition(conditions, generation, v1.ConditionFalse, reasonFailed, opErr.Error())
+		return ctrl.Result{}, opErr
+	}
+	setReadyCondition(conditions, generation, v1.ConditionTrue, reasonSynchronized, successMessage)
+	return ctrl.Result{RequeueAfter: serverResourceResyncPeriod}, nil
+}
@@ -4,6 +4,7 @@ go 1.15
 
 require (
 	github.com/RHsyseng/operator-utils v1.4.4
+	github.com/evanphx/json-patch v4.9.0+incompatible
 	// controller-runtime uses v0.1.0, klogv2 uses v0.2.0, which is the log module for k8s
 	// as soon as they sync, we can migrate to v0.2.0
 	github.com/go-logr/logr v0.1.0
@@ -21,6 +22,7 @@ require (
 	k8s.io/client-go v12.0.0+incompatible
 	k8s.io/kube-openapi v0.0.0-20200805222855-6aeccd4b50c6
 	sigs.k8s.io/controller-runtime v0.6.3
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
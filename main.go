@@ -35,6 +35,7 @@ import (
 	"github.com/m88i/nexus-operator/controllers"
 	"github.com/m88i/nexus-operator/controllers/nexus/resource"
 	"github.com/m88i/nexus-operator/pkg/cluster/discovery"
+	"github.com/m88i/nexus-operator/pkg/cluster/kubernetes"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -88,6 +89,7 @@ func main() {
 	}
 
 	discovery.SetClient(k8sdisc.NewDiscoveryClientForConfigOrDie(ctrl.GetConfigOrDie()))
+	kubernetes.SetPodExecutor(kubernetes.NewPodExecutorOrDie(ctrl.GetConfigOrDie()))
 	if err = (&controllers.NexusReconciler{
 		Client:     mgr.GetClient(),
 		Log:        ctrl.Log.WithName("controllers").WithName("Nexus"),
@@ -97,6 +99,30 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Nexus")
 		os.Exit(1)
 	}
+	if err = (&controllers.NexusRepositoryReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NexusRepository"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NexusRepository")
+		os.Exit(1)
+	}
+	if err = (&controllers.NexusUserReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NexusUser"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NexusUser")
+		os.Exit(1)
+	}
+	if err = (&controllers.NexusRoleReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("NexusRole"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NexusRole")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
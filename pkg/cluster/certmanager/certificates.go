@@ -0,0 +1,75 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// IssuerKind is the kind of the namespaced cert-manager issuers
+	IssuerKind = "Issuer"
+	// ClusterIssuerKind is the kind of the cluster wide cert-manager issuers
+	ClusterIssuerKind = "ClusterIssuer"
+
+	readyConditionType = "Ready"
+)
+
+// GroupVersion is the cert-manager API group version the Operator relies on
+var GroupVersion = schema.GroupVersion{Group: "cert-manager.io", Version: "v1"}
+
+// CertificateGroupVersionKind identifies the cert-manager Certificates.
+// We don't depend on the cert-manager API module, the Certificates are handled as unstructured objects.
+var CertificateGroupVersionKind = GroupVersion.WithKind("Certificate")
+
+// NewCertificate creates an empty cert-manager Certificate
+func NewCertificate() *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(CertificateGroupVersionKind)
+	return cert
+}
+
+// IsCertificateReady verifies if the given Certificate has been issued. A Certificate that doesn't exist is not ready.
+func IsCertificateReady(cli client.Client, key types.NamespacedName) (bool, error) {
+	cert := NewCertificate()
+	if err := cli.Get(context.TODO(), key, cert); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return IsReady(cert), nil
+}
+
+// IsReady verifies if the "Ready" condition of the given Certificate is "True"
+func IsReady(cert *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == readyConditionType {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsCertificateReady(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypeWithName(CertificateGroupVersionKind, &unstructured.Unstructured{})
+	cli := fake.NewFakeClientWithScheme(s)
+	key := types.NamespacedName{Namespace: "nexus", Name: "nexus3"}
+
+	// not created yet
+	ready, err := IsCertificateReady(cli, key)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+
+	// created, but not issued yet
+	cert := NewCertificate()
+	cert.SetNamespace(key.Namespace)
+	cert.SetName(key.Name)
+	assert.NoError(t, cli.Create(context.TODO(), cert))
+	ready, err = IsCertificateReady(cli, key)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+
+	// issued
+	assert.NoError(t, unstructured.SetNestedSlice(cert.Object, []interface{}{
+		map[string]interface{}{"type": "Issuing", "status": "False"},
+		map[string]interface{}{"type": "Ready", "status": "True"},
+	}, "status", "conditions"))
+	assert.NoError(t, cli.Update(context.TODO(), cert))
+	ready, err = IsCertificateReady(cli, key)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discovery
+
+import (
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
+)
+
+// IsCertManagerAvailable verifies if the current cluster has the Certificate API from cert-manager available
+func IsCertManagerAvailable() (bool, error) {
+	gvk := certmanager.CertificateGroupVersionKind
+	return hasGroupVersionKind(gvk.Group, gvk.Version, gvk.Kind)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodExecutor runs commands inside the containers of running pods
+type PodExecutor interface {
+	// Exec runs the given command in the container and returns what it wrote to the standard output
+	Exec(namespace, pod, container string, command ...string) (string, error)
+}
+
+var executor PodExecutor
+
+// ErrPodExecutorNotSet is returned when trying to run a command in a pod before setting the package-level PodExecutor
+var ErrPodExecutorNotSet = errors.New("pod executor not set")
+
+// SetPodExecutor sets the package-level PodExecutor.
+// You probably don't need this for production as it gets called from main.
+// Knock yourself out for testing.
+func SetPodExecutor(e PodExecutor) {
+	executor = e
+}
+
+// ExecInPod runs the given command in the container using the package-level PodExecutor
+func ExecInPod(namespace, pod, container string, command ...string) (string, error) {
+	if executor == nil {
+		return "", ErrPodExecutorNotSet
+	}
+	return executor.Exec(namespace, pod, container, command...)
+}
+
+type restPodExecutor struct {
+	config *rest.Config
+	client rest.Interface
+}
+
+// NewPodExecutorOrDie creates a new PodExecutor relying on the pods/exec API of the cluster, panicking in case of errors
+func NewPodExecutorOrDie(config *rest.Config) PodExecutor {
+	client, err := corev1client.NewForConfig(config)
+	if err != nil {
+		panic(err)
+	}
+	return &restPodExecutor{config: config, client: client.RESTClient()}
+}
+
+func (r *restPodExecutor) Exec(namespace, pod, container string, command ...string) (string, error) {
+	req := r.client.Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	if err := exec.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return "", fmt.Errorf("failed to run '%s' in pod %s/%s: %v: %s", strings.Join(command, " "), namespace, pod, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type echoExecutor struct{}
+
+func (e *echoExecutor) Exec(namespace, pod, container string, command ...string) (string, error) {
+	return strings.Join(append([]string{namespace, pod, container}, command...), " "), nil
+}
+
+func TestExecInPod(t *testing.T) {
+	SetPodExecutor(nil)
+	_, err := ExecInPod("test", "nexus", "nexus-server", "cat", "file")
+	assert.Equal(t, ErrPodExecutorNotSet, err)
+
+	SetPodExecutor(&echoExecutor{})
+	defer SetPodExecutor(nil)
+	out, err := ExecInPod("test", "nexus", "nexus-server", "cat", "file")
+	assert.NoError(t, err)
+	assert.Equal(t, "test nexus nexus-server cat file", out)
+}
@@ -26,21 +26,29 @@ import (
 	"github.com/m88i/nexus-operator/pkg/util"
 )
 
-// GetIngressURI discover the URI for Ingress
-func GetIngressURI(cli client.Client, ingressName types.NamespacedName) (string, error) {
+// GetIngressURIs discovers the URIs of the rules of an Ingress for the given hosts, in the order the rules are declared
+func GetIngressURIs(cli client.Client, ingressName types.NamespacedName, hosts []string) ([]string, error) {
 	ingress := &networking.Ingress{}
 	if err := cli.Get(context.TODO(), ingressName, ingress); err != nil && !errors.IsNotFound(err) {
-		return "", err
+		return nil, err
 	} else if errors.IsNotFound(err) {
-		return "", nil
+		return nil, nil
 	}
 
-	if len(ingress.Spec.Rules) > 0 {
-		if len(ingress.Spec.TLS) == 0 {
-			return fmt.Sprintf("%s%s", util.HTTPPrefixSchema, ingress.Spec.Rules[0].Host), nil
+	schema := util.HTTPPrefixSchema
+	if len(ingress.Spec.TLS) > 0 {
+		schema = util.HTTPSPrefixSchema
+	}
+	var uris []string
+	for _, rule := range ingress.Spec.Rules {
+		if !util.ContainsString(hosts, rule.Host) {
+			continue
+		}
+		path := ""
+		if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 && rule.HTTP.Paths[0].Path != "/" {
+			path = rule.HTTP.Paths[0].Path
 		}
-		return fmt.Sprintf("%s%s", util.HTTPSPrefixSchema, ingress.Spec.Rules[0].Host), nil
+		uris = append(uris, fmt.Sprintf("%s%s%s", schema, rule.Host, path))
 	}
-
-	return "", nil
+	return uris, nil
 }
@@ -0,0 +1,57 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/m88i/nexus-operator/pkg/test"
+)
+
+func TestGetIngressURIs(t *testing.T) {
+	key := types.NamespacedName{Namespace: "test", Name: "nexus3"}
+	rule := func(host, path string) networking.IngressRule {
+		return networking.IngressRule{Host: host, IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{
+			Paths: []networking.HTTPIngressPath{{Path: path}},
+		}}}
+	}
+	ingress := &networking.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: networking.IngressSpec{Rules: []networking.IngressRule{
+			rule("nexus.example.com", "/nexus"),
+			rule("nexus.internal.example.com", "/nexus"),
+			rule("registry.example.com", "/"),
+		}},
+	}
+
+	// no ingress yet
+	uris, err := GetIngressURIs(test.NewFakeClientBuilder().WithIngress().Build(), key, []string{"nexus.example.com"})
+	assert.NoError(t, err)
+	assert.Empty(t, uris)
+
+	cli := test.NewFakeClientBuilder(ingress).WithIngress().Build()
+	uris, err = GetIngressURIs(cli, key, []string{"nexus.internal.example.com", "nexus.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://nexus.example.com/nexus", "http://nexus.internal.example.com/nexus"}, uris)
+
+	uris, err = GetIngressURIs(cli, key, []string{"registry.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://registry.example.com"}, uris)
+}
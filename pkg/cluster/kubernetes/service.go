@@ -0,0 +1,61 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/m88i/nexus-operator/pkg/util"
+)
+
+const defaultHTTPPort = 80
+
+// GetLoadBalancerURI discovers the URI of the given port of a LoadBalancer Service, empty until the load balancer has been provisioned
+func GetLoadBalancerURI(cli client.Client, serviceName types.NamespacedName, port int32, path string) (string, error) {
+	svc := &corev1.Service{}
+	if err := cli.Get(context.TODO(), serviceName, svc); err != nil && !errors.IsNotFound(err) {
+		return "", err
+	} else if errors.IsNotFound(err) {
+		return "", nil
+	}
+
+	if len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return "", nil
+	}
+	host := svc.Status.LoadBalancer.Ingress[0].Hostname
+	if len(host) == 0 {
+		host = svc.Status.LoadBalancer.Ingress[0].IP
+	}
+	if len(host) == 0 {
+		return "", nil
+	}
+
+	if port != defaultHTTPPort {
+		host = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	} else if strings.Contains(host, ":") {
+		// IPv6
+		host = "[" + host + "]"
+	}
+	return fmt.Sprintf("%s%s%s", util.HTTPPrefixSchema, host, path), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/m88i/nexus-operator/pkg/test"
+)
+
+func TestGetLoadBalancerURI(t *testing.T) {
+	key := types.NamespacedName{Namespace: "test", Name: "nexus3"}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+
+	// no service yet
+	uri, err := GetLoadBalancerURI(test.NewFakeClientBuilder().Build(), key, 80, "")
+	assert.NoError(t, err)
+	assert.Empty(t, uri)
+
+	// the load balancer hasn't been provisioned yet
+	uri, err = GetLoadBalancerURI(test.NewFakeClientBuilder(svc).Build(), key, 80, "")
+	assert.NoError(t, err)
+	assert.Empty(t, uri)
+
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.168.1.240"}}
+	cli := test.NewFakeClientBuilder(svc).Build()
+	uri, err = GetLoadBalancerURI(cli, key, 80, "/nexus")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://192.168.1.240/nexus", uri)
+
+	uri, err = GetLoadBalancerURI(cli, key, 4180, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://192.168.1.240:4180", uri)
+
+	// the hostname is preferred
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.168.1.240", Hostname: "lb.example.com"}}
+	uri, err = GetLoadBalancerURI(test.NewFakeClientBuilder(svc).Build(), key, 80, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://lb.example.com", uri)
+
+	svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "2001:db8::1"}}
+	uri, err = GetLoadBalancerURI(test.NewFakeClientBuilder(svc).Build(), key, 80, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://[2001:db8::1]", uri)
+}
@@ -43,9 +43,9 @@ func GetRouteURI(client client.Client, routeName types.NamespacedName) (uri stri
 
 	if len(route.Spec.Host) > 0 {
 		if route.Spec.TLS != nil {
-			return fmt.Sprintf("%s%s", util.HTTPSPrefixSchema, route.Spec.Host), nil
+			return fmt.Sprintf("%s%s%s", util.HTTPSPrefixSchema, route.Spec.Host, route.Spec.Path), nil
 		}
-		return fmt.Sprintf("%s%s", util.HTTPPrefixSchema, route.Spec.Host), nil
+		return fmt.Sprintf("%s%s%s", util.HTTPPrefixSchema, route.Spec.Host, route.Spec.Path), nil
 	}
 
 	return "", nil
@@ -16,3 +16,6 @@ package framework
 
 // ReadWritePermission 666 filesystem permission to be used with a mount path
 var ReadWritePermission = int32(0666)
+
+// ReadOnlyPermission 444 filesystem permission to be used with a mount path
+var ReadOnlyPermission = int32(0444)
@@ -15,12 +15,13 @@
 package kind
 
 const (
-	ConfigMapKind  = "ConfigMap"
-	DeploymentKind = "Deployment"
-	IngressKind    = "Ingress"
-	PVCKind        = "Persistent Volume Claim"
-	RouteKind      = "Route"
-	SecretKind     = "Secret"
-	ServiceKind    = "Service"
-	SvcAccountKind = "Service Account"
+	CertificateKind = "Certificate"
+	ConfigMapKind   = "ConfigMap"
+	DeploymentKind  = "Deployment"
+	IngressKind     = "Ingress"
+	PVCKind         = "Persistent Volume Claim"
+	RouteKind       = "Route"
+	SecretKind      = "Secret"
+	ServiceKind     = "Service"
+	SvcAccountKind  = "Service Account"
 )
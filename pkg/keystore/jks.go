@@ -0,0 +1,179 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// See sun.security.provider.JavaKeyStore and sun.security.provider.KeyProtector for the details of the format
+const (
+	jksMagic        uint32 = 0xFEEDFEED
+	jksVersion      uint32 = 2
+	privateKeyTag   uint32 = 1
+	certificateType        = "X.509"
+	// whitener is hashed along with the password to compute the integrity digest of the keystore
+	whitener = "Mighty Aphrodite"
+)
+
+// keyProtectorOID identifies the proprietary algorithm protecting the private keys in JKS keystores
+var keyProtectorOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// JKSFromPEM creates a Java keystore (JKS) holding the private key and the certificate chain of the given PEM encoded data under the given alias.
+// The same input always results in the same keystore, so that it only changes along with the certificate.
+func JKSFromPEM(certPEM, keyPEM []byte, alias, password string) ([]byte, error) {
+	chain, err := parseCertificates(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the certificate: %v", err)
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordBytes := encodePassword(password)
+	// the salt is derived from the input rather than randomly generated to keep the keystore stable
+	salt := sha1.Sum(append(append([]byte{}, certPEM...), keyPEM...))
+	protectedKey, err := protectKey(key, passwordBytes, salt[:])
+	if err != nil {
+		return nil, err
+	}
+
+	b := new(bytes.Buffer)
+	writeUint32(b, jksMagic)
+	writeUint32(b, jksVersion)
+	writeUint32(b, 1)
+	writeUint32(b, privateKeyTag)
+	writeUTF(b, strings.ToLower(alias))
+	writeUint64(b, uint64(leaf.NotBefore.UnixNano()/1e6))
+	writeUint32(b, uint32(len(protectedKey)))
+	b.Write(protectedKey)
+	writeUint32(b, uint32(len(chain)))
+	for _, cert := range chain {
+		writeUTF(b, certificateType)
+		writeUint32(b, uint32(len(cert)))
+		b.Write(cert)
+	}
+
+	digest := sha1.New()
+	digest.Write(passwordBytes)
+	digest.Write([]byte(whitener))
+	digest.Write(b.Bytes())
+	b.Write(digest.Sum(nil))
+	return b.Bytes(), nil
+}
+
+func parseCertificates(certPEM []byte) ([][]byte, error) {
+	var chain [][]byte
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			chain = append(chain, block.Bytes)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	return chain, nil
+}
+
+// parsePrivateKey returns the PKCS #8 form of the first PEM encoded private key
+func parsePrivateKey(keyPEM []byte) ([]byte, error) {
+	for block, rest := pem.Decode(keyPEM); block != nil; block, rest = pem.Decode(rest) {
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+		if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			return block.Bytes, nil
+		}
+		if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			return x509.MarshalPKCS8PrivateKey(key)
+		}
+		if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+			return x509.MarshalPKCS8PrivateKey(key)
+		}
+		return nil, fmt.Errorf("unsupported private key format: %s", block.Type)
+	}
+	return nil, fmt.Errorf("no PEM encoded private key found")
+}
+
+// protectKey encrypts the private key by XORing it with a key stream made of chained SHA-1 digests of the password and the salt
+func protectKey(plainKey, password, salt []byte) ([]byte, error) {
+	rounds := (len(plainKey) + sha1.Size - 1) / sha1.Size
+	xorKey := make([]byte, 0, rounds*sha1.Size)
+	digest := salt
+	for i := 0; i < rounds; i++ {
+		h := sha1.New()
+		h.Write(password)
+		h.Write(digest)
+		digest = h.Sum(nil)
+		xorKey = append(xorKey, digest...)
+	}
+
+	protected := make([]byte, 0, len(salt)+len(plainKey)+sha1.Size)
+	protected = append(protected, salt...)
+	for i := range plainKey {
+		protected = append(protected, plainKey[i]^xorKey[i])
+	}
+	checksum := sha1.New()
+	checksum.Write(password)
+	checksum.Write(plainKey)
+	protected = append(protected, checksum.Sum(nil)...)
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: keyProtectorOID, Parameters: asn1.NullRawValue},
+		EncryptedData: protected,
+	})
+}
+
+// encodePassword encodes the password the way Java does with its chars: two bytes each, big endian
+func encodePassword(password string) []byte {
+	var b []byte
+	for _, c := range utf16.Encode([]rune(password)) {
+		b = append(b, byte(c>>8), byte(c))
+	}
+	return b
+}
+
+func writeUint32(b *bytes.Buffer, v uint32) {
+	_ = binary.Write(b, binary.BigEndian, v)
+}
+
+func writeUint64(b *bytes.Buffer, v uint64) {
+	_ = binary.Write(b, binary.BigEndian, v)
+}
+
+// writeUTF writes the string as Java's DataOutput.writeUTF, good enough for the ASCII aliases and types we use
+func writeUTF(b *bytes.Buffer, s string) {
+	_ = binary.Write(b, binary.BigEndian, uint16(len(s)))
+	b.WriteString(s)
+}
@@ -0,0 +1,148 @@
+// Copyright 2020 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generateCertificate(t *testing.T) (certPEM, keyPEM []byte, key *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "nexus3.nexus.svc"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, key
+}
+
+func TestJKSFromPEM(t *testing.T) {
+	certPEM, keyPEM, key := generateCertificate(t)
+	jks, err := JKSFromPEM(certPEM, keyPEM, "Jetty", "password")
+	assert.NoError(t, err)
+
+	// the same input results in the same keystore
+	again, err := JKSFromPEM(certPEM, keyPEM, "Jetty", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, jks, again)
+
+	// integrity digest
+	password := encodePassword("password")
+	content, digest := jks[:len(jks)-sha1.Size], jks[len(jks)-sha1.Size:]
+	h := sha1.New()
+	h.Write(password)
+	h.Write([]byte(whitener))
+	h.Write(content)
+	assert.Equal(t, h.Sum(nil), digest)
+
+	r := bytes.NewReader(content)
+	assert.Equal(t, jksMagic, readUint32(t, r))
+	assert.Equal(t, jksVersion, readUint32(t, r))
+	assert.Equal(t, uint32(1), readUint32(t, r))
+	assert.Equal(t, privateKeyTag, readUint32(t, r))
+	assert.Equal(t, "jetty", readUTF(t, r))
+	var date uint64
+	assert.NoError(t, binary.Read(r, binary.BigEndian, &date))
+	assert.Equal(t, uint64(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix()*1000), date)
+
+	// the private key can be recovered with the password
+	protectedKey := make([]byte, readUint32(t, r))
+	_, _ = r.Read(protectedKey)
+	info := encryptedPrivateKeyInfo{}
+	_, err = asn1.Unmarshal(protectedKey, &info)
+	assert.NoError(t, err)
+	assert.Equal(t, keyProtectorOID, info.Algorithm.Algorithm)
+	plainKey := unprotectKey(info.EncryptedData, password)
+	parsedKey, err := x509.ParsePKCS8PrivateKey(plainKey)
+	assert.NoError(t, err)
+	assert.True(t, key.Equal(parsedKey))
+	checksum := sha1.New()
+	checksum.Write(password)
+	checksum.Write(plainKey)
+	assert.Equal(t, checksum.Sum(nil), info.EncryptedData[len(info.EncryptedData)-sha1.Size:])
+
+	// the certificate chain
+	assert.Equal(t, uint32(1), readUint32(t, r))
+	assert.Equal(t, certificateType, readUTF(t, r))
+	cert := make([]byte, readUint32(t, r))
+	_, _ = r.Read(cert)
+	block, _ := pem.Decode(certPEM)
+	assert.Equal(t, block.Bytes, cert)
+	assert.Equal(t, 0, r.Len())
+}
+
+func TestJKSFromPEM_invalidInput(t *testing.T) {
+	certPEM, keyPEM, _ := generateCertificate(t)
+
+	_, err := JKSFromPEM(nil, keyPEM, "jetty", "password")
+	assert.EqualError(t, err, "no PEM encoded certificate found")
+
+	_, err = JKSFromPEM(certPEM, certPEM, "jetty", "password")
+	assert.EqualError(t, err, "no PEM encoded private key found")
+
+	_, err = JKSFromPEM(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}), "jetty", "password")
+	assert.EqualError(t, err, "unsupported private key format: PRIVATE KEY")
+}
+
+// unprotectKey reverses protectKey, the way Java reads the keystore
+func unprotectKey(protected, password []byte) []byte {
+	salt := protected[:sha1.Size]
+	encrypted := protected[sha1.Size : len(protected)-sha1.Size]
+	plainKey := make([]byte, len(encrypted))
+	digest := salt
+	for i := range encrypted {
+		if i%sha1.Size == 0 {
+			h := sha1.New()
+			h.Write(password)
+			h.Write(digest)
+			digest = h.Sum(nil)
+		}
+		plainKey[i] = encrypted[i] ^ digest[i%sha1.Size]
+	}
+	return plainKey
+}
+
+func readUint32(t *testing.T, r *bytes.Reader) uint32 {
+	var v uint32
+	assert.NoError(t, binary.Read(r, binary.BigEndian, &v))
+	return v
+}
+
+func readUTF(t *testing.T, r *bytes.Reader) string {
+	var length uint16
+	assert.NoError(t, binary.Read(r, binary.BigEndian, &length))
+	s := make([]byte, length)
+	_, _ = r.Read(s)
+	return string(s)
+}
@@ -21,6 +21,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/discovery"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/m88i/nexus-operator/api/v1alpha1"
+	"github.com/m88i/nexus-operator/pkg/cluster/certmanager"
 	"github.com/m88i/nexus-operator/pkg/framework/kind"
 	"github.com/m88i/nexus-operator/pkg/util"
 )
@@ -78,6 +80,15 @@ func (b *FakeClientBuilder) WithIngress() *FakeClientBuilder {
 	return b
 }
 
+// WithCertManager makes the fake client aware of cert-manager Certificates
+func (b *FakeClientBuilder) WithCertManager() *FakeClientBuilder {
+	gvk := certmanager.CertificateGroupVersionKind
+	b.scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+	b.scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+	b.resources = append(b.resources, &metav1.APIResourceList{GroupVersion: gvk.GroupVersion().String(), APIResources: []metav1.APIResource{{Kind: gvk.Kind}}})
+	return b
+}
+
 // Build returns the fake discovery client
 func (b *FakeClientBuilder) Build() *FakeClient {
 	return &FakeClient{
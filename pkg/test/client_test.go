@@ -38,9 +38,11 @@ func TestNewFakeClientBuilder(t *testing.T) {
 	b := NewFakeClientBuilder(nexus)
 
 	// client.Client
-	assert.Len(t, b.scheme.KnownTypes(v1alpha1.GroupVersion), 10)
+	assert.Len(t, b.scheme.KnownTypes(v1alpha1.GroupVersion), 16)
 	assert.Contains(t, b.scheme.KnownTypes(v1alpha1.GroupVersion), strings.Split(reflect.TypeOf(&v1alpha1.Nexus{}).String(), ".")[1])
 	assert.Contains(t, b.scheme.KnownTypes(v1alpha1.GroupVersion), strings.Split(reflect.TypeOf(&v1alpha1.NexusList{}).String(), ".")[1])
+	assert.Contains(t, b.scheme.KnownTypes(v1alpha1.GroupVersion), strings.Split(reflect.TypeOf(&v1alpha1.NexusRepository{}).String(), ".")[1])
+	assert.Contains(t, b.scheme.KnownTypes(v1alpha1.GroupVersion), strings.Split(reflect.TypeOf(&v1alpha1.NexusRepositoryList{}).String(), ".")[1])
 
 	// discovery.DiscoveryInterface
 	assert.True(t, resourceListsContainsGroupVersion(b.resources, v1alpha1.GroupVersion.String()))
@@ -15,8 +15,11 @@
 package util
 
 import (
-	"bytes"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
 )
 
 // AppendToStringMap adds the given key and value to the map. If map is nil, create it first
@@ -28,19 +31,178 @@ func AppendToStringMap(stringMap map[string]string, key, value string) map[strin
 	return stringMap
 }
 
-// FromMapToJavaProperties converts a given map to a Java properties file string.
+// FromMapToJavaProperties converts a given map to a Java properties file string, sorted by key.
+// Keys and values are escaped as done by java.util.Properties, so that the file is read back with the same entries.
 // Example:
-// # given myMap[string]string = { "key1": "value1", "key2": "value2" }
+// # given myMap[string]string = { "key2": "value2", "key1": "value1" }
 // # you got back:
-//   key1: value1
-//   key2: value2
+//   key1=value1
+//   key2=value2
 func FromMapToJavaProperties(theMap map[string]string) string {
 	if len(theMap) == 0 {
 		return ""
 	}
-	b := new(bytes.Buffer)
-	for key, value := range theMap {
-		_, _ = fmt.Fprintf(b, "%s: \"%s\"\n", key, value)
+	keys := make([]string, 0, len(theMap))
+	for key := range theMap {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	b := new(strings.Builder)
+	for _, key := range keys {
+		b.WriteString(escapeJavaProperty(key, true))
+		b.WriteByte('=')
+		b.WriteString(escapeJavaProperty(theMap[key], false))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// escapeJavaProperty escapes the given key or value the same way java.util.Properties#store does.
+// Non-ASCII characters are written as unicode escapes since properties files are read as ISO 8859-1.
+func escapeJavaProperty(s string, key bool) string {
+	b := new(strings.Builder)
+	for i, r := range s {
+		switch {
+		case r == ' ':
+			if key || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == '=', r == ':', r == '#', r == '!':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				_, _ = fmt.Fprintf(b, `\u%04X`, unit)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// FromJavaPropertiesToMap parses a Java properties file string into a map, following the format read by java.util.Properties#load.
+// Comments and blank lines are ignored and, when a key is repeated, the last value wins.
+func FromJavaPropertiesToMap(properties string) map[string]string {
+	theMap := map[string]string{}
+	for _, line := range logicalJavaPropertiesLines(properties) {
+		keyEnd, valueStart := len(line), len(line)
+		for i := 0; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+				continue
+			}
+			if line[i] == '=' || line[i] == ':' || isJavaPropertiesSpace(line[i]) {
+				keyEnd = i
+				valueStart = i
+				// skip the whitespace around the separator, which is either '=', ':' or the whitespace itself
+				for valueStart < len(line) && isJavaPropertiesSpace(line[valueStart]) {
+					valueStart++
+				}
+				if valueStart < len(line) && (line[valueStart] == '=' || line[valueStart] == ':') {
+					valueStart++
+					for valueStart < len(line) && isJavaPropertiesSpace(line[valueStart]) {
+						valueStart++
+					}
+				}
+				break
+			}
+		}
+		theMap[unescapeJavaProperty(line[:keyEnd])] = unescapeJavaProperty(line[valueStart:])
+	}
+	return theMap
+}
+
+// logicalJavaPropertiesLines joins the lines ending with an odd number of backslashes with the following ones, dropping comments and blank lines
+func logicalJavaPropertiesLines(properties string) []string {
+	var lines []string
+	logicalLine := ""
+	continuation := false
+	for _, line := range strings.FieldsFunc(properties, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		line = strings.TrimLeft(line, " \t\f")
+		if !continuation && (len(line) == 0 || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		backslashes := len(line) - len(strings.TrimRight(line, `\`))
+		continuation = backslashes%2 == 1
+		if continuation {
+			line = line[:len(line)-1]
+		}
+		logicalLine += line
+		if !continuation {
+			lines = append(lines, logicalLine)
+			logicalLine = ""
+		}
+	}
+	if len(logicalLine) > 0 {
+		lines = append(lines, logicalLine)
+	}
+	return lines
+}
+
+func isJavaPropertiesSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\f'
+}
+
+// unescapeJavaProperty reverts the escaping of a key or value, see escapeJavaProperty
+func unescapeJavaProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var units []uint16
+	b := new(strings.Builder)
+	flushUnits := func() {
+		if len(units) > 0 {
+			b.WriteString(string(utf16.Decode(units)))
+			units = nil
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			flushUnits()
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			flushUnits()
+			b.WriteByte('\t')
+		case 'n':
+			flushUnits()
+			b.WriteByte('\n')
+		case 'r':
+			flushUnits()
+			b.WriteByte('\r')
+		case 'f':
+			flushUnits()
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 <= len(s) {
+				if unit, err := strconv.ParseUint(s[i+1:i+5], 16, 16); err == nil {
+					units = append(units, uint16(unit))
+					i += 4
+					continue
+				}
+			}
+			flushUnits()
+			b.WriteByte(s[i])
+		default:
+			flushUnits()
+			b.WriteByte(s[i])
+		}
+	}
+	flushUnits()
 	return b.String()
 }
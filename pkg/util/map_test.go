@@ -0,0 +1,67 @@
+// Copyright 2021 Nexus Operator and/or its authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMapToJavaProperties(t *testing.T) {
+	assert.Empty(t, FromMapToJavaProperties(nil))
+	assert.Equal(t, "a=1\nb=2\nc=3\n", FromMapToJavaProperties(map[string]string{"c": "3", "a": "1", "b": "2"}))
+	assert.Equal(t, "nexus-context-path=/nexus\n", FromMapToJavaProperties(map[string]string{"nexus-context-path": "/nexus"}))
+	assert.Equal(t, `nexus-args=${jetty.etc}/jetty.xml,${jetty.etc}/jetty-https.xml`+"\n",
+		FromMapToJavaProperties(map[string]string{"nexus-args": "${jetty.etc}/jetty.xml,${jetty.etc}/jetty-https.xml"}))
+	assert.Equal(t, `key\ with\:separators\=\#\!=\ leading space, C\:\\path\=\:\#\!\tand\nlines`+"\n",
+		FromMapToJavaProperties(map[string]string{"key with:separators=#!": " leading space, C:\\path=:#!\tand\nlines"}))
+	assert.Equal(t, `greeting=ol\u00E1 \uD83D\uDE00`+"\n", FromMapToJavaProperties(map[string]string{"greeting": "olá 😀"}))
+}
+
+func TestFromJavaPropertiesToMap(t *testing.T) {
+	properties := `# comment
+! another comment
+
+a=1
+b : 2
+c 3
+  d=   4
+e=
+f
+g=multi\
+    line
+h=escaped\\
+i=last
+i=wins
+`
+	assert.Equal(t, map[string]string{
+		"a": "1", "b": "2", "c": "3", "d": "4", "e": "", "f": "", "g": "multiline", "h": `escaped\`, "i": "wins",
+	}, FromJavaPropertiesToMap(properties))
+	assert.Empty(t, FromJavaPropertiesToMap(""))
+	// the format written by older versions of the operator keeps the quotes in the values
+	assert.Equal(t, map[string]string{"nexus-context-path": `"/nexus"`}, FromJavaPropertiesToMap(`nexus-context-path: "/nexus"`))
+}
+
+func TestJavaPropertiesRoundTrip(t *testing.T) {
+	theMap := map[string]string{
+		"key with:separators=#!": " leading space, C:\\path=:#!\tand\nlines\r\f",
+		"greeting":               "olá 😀",
+		"empty":                  "",
+		"trailing\\":             "backslash\\",
+		"#comment":               "!not",
+	}
+	assert.Equal(t, theMap, FromJavaPropertiesToMap(FromMapToJavaProperties(theMap)))
+}